@@ -5,7 +5,7 @@ import (
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/logs"
-	"github.com/cloudfoundry/cf-acceptance-tests/helpers/skip_messages"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/manifest"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gbytes"
@@ -21,9 +21,6 @@ var _ = AppsDescribe("Readiness Healthcheck", func() {
 	var readinessHealthCheckTimeout = "25s" // 20s route emitter sync loop + 2s hc interval + bonus
 
 	BeforeEach(func() {
-		if !Config.GetReadinessHealthChecksEnabled() {
-			Skip(skip_messages.SkipReadinessHealthChecksMessage)
-		}
 		appName = random_name.CATSRandomName("APP")
 	})
 
@@ -38,7 +35,32 @@ var _ = AppsDescribe("Readiness Healthcheck", func() {
 
 			Expect(cf.Cf("push", appName,
 				"-p", assets.NewAssets().Dora,
-				"-f", assets.NewAssets().Dora+"/readiness_manifest.yml",
+				"-f", app_helpers.WriteManifest(manifest.Manifest{Applications: []manifest.Application{{
+					Name: appName,
+					Processes: []manifest.Process{
+						{
+							Type:                             "web",
+							Instances:                        manifest.Instances(1),
+							Memory:                           "1024M",
+							DiskQuota:                        "1024M",
+							LogRateLimitPerSecond:            "16K",
+							HealthCheckType:                  "http",
+							HealthCheckHTTPEndpoint:          "/",
+							ReadinessHealthCheckType:         "http",
+							ReadinessHealthCheckHTTPEndpoint: "/ready",
+							ReadinessHealthCheckInterval:     1,
+						},
+						{
+							Type:                     "worker",
+							Instances:                manifest.Instances(0),
+							Memory:                   "1024M",
+							DiskQuota:                "1024M",
+							LogRateLimitPerSecond:    "16K",
+							HealthCheckType:          "process",
+							ReadinessHealthCheckType: "process",
+						},
+					},
+				}}}),
 			).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
 
 			By("verifying the app starts")
@@ -70,7 +92,7 @@ var _ = AppsDescribe("Readiness Healthcheck", func() {
 			By("verifying that the app hasn't restarted")
 			Consistently(cf.Cf("events", appName)).ShouldNot(Say("audit.app.process.crash"))
 
-			if Config.GetIncludeSsh() {
+			if Config.GetIncludeSsh() && !Config.UsesClientCredentials() {
 				By("re-enabling the app's readiness endpoint")
 				Expect(cf.Cf("ssh", appName, "-c", "curl localhost:8080/ready/true").Wait(Config.DefaultTimeoutDuration())).To(Exit(0))
 
@@ -84,4 +106,4 @@ var _ = AppsDescribe("Readiness Healthcheck", func() {
 			}
 		})
 	})
-})
+}, RequiresReadinessHealthChecks)
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cleanup"
+	cfg "github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/run_metadata"
+)
+
+// This utility finds resources left behind by CATS runs that were interrupted before their
+// teardown, such as CATS-* orgs, users, service brokers and buildpacks, using the admin credentials
+// from $CONFIG. It lists them by default, and deletes them with -delete. With -run-id, it finds the
+// resources labeled with the ID of a run instead, whatever their names and age.
+func main() {
+	olderThan := flag.Duration("older-than", 24*time.Hour, "only consider resources created longer ago than this, so that running suites are left alone")
+	runID := flag.String("run-id", "", "only consider resources labeled with the ID of this run, as printed by the suite, regardless of their names and age")
+	deleteResources := flag.Bool("delete", false, "delete the resources instead of listing them")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: CONFIG=path/to/config.json %s [-older-than 24h | -run-id ID] [-delete]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	config, err := cfg.NewCatsConfig(os.Getenv("CONFIG"))
+	if err != nil {
+		fmt.Println("Invalid configuration:")
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	client, err := adminClient(config)
+	if err != nil {
+		fmt.Println("Could not authenticate as the admin:", err)
+		os.Exit(1)
+	}
+
+	filter := cleanup.Filter{
+		Names:         cleanup.NamePattern("CATS", config.GetNamePrefix()),
+		CreatedBefore: time.Now().Add(-*olderThan),
+	}
+	description := fmt.Sprintf("CATS resources older than %s", *olderThan)
+	if *runID != "" {
+		filter = cleanup.Filter{LabelSelector: run_metadata.Selector(*runID)}
+		description = fmt.Sprintf("resources of run %s", *runID)
+	}
+
+	resources, err := cleanup.Find(client, filter)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if len(resources) == 0 {
+		fmt.Printf("No %s found.\n", description)
+		return
+	}
+
+	if !*deleteResources {
+		printResources(resources)
+		fmt.Printf("\nRun with -delete to delete these %d resources.\n", len(resources))
+		return
+	}
+
+	err = cleanup.Delete(client, resources, func(r cleanup.Resource) {
+		fmt.Printf("Deleted %s %s\n", r.Kind, r.Name)
+	})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func adminClient(config cfg.CatsConfig) (*cc_client.Client, error) {
+	apiURL := config.Protocol() + config.GetApiEndpoint()
+
+	var token string
+	var err error
+	if config.GetAdminClient() != "" && config.GetAdminClientSecret() != "" {
+		token, err = cc_client.ClientToken(apiURL, config.GetAdminClient(), config.GetAdminClientSecret(), config.GetSkipSSLValidation())
+	} else {
+		token, err = cc_client.UserToken(apiURL, config.GetAdminUser(), config.GetAdminPassword(), config.GetSkipSSLValidation())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return cc_client.New(apiURL, token, config.GetSkipSSLValidation()), nil
+}
+
+func printResources(resources []cleanup.Resource) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tNAME\tCREATED\tGUID")
+	for _, r := range resources {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Kind, r.Name, r.CreatedAt.Format(time.RFC3339), r.GUID)
+	}
+	w.Flush()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"text/tabwriter"
 
 	cfg "github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
 )
@@ -20,7 +21,36 @@ var requiredConfig = `{
 
 // This Golang utility generates a complete config.json on demand from the existing defaults in the code.
 // Users could make use of it and modify the resulting JSON file as desired for their environments.
+//
+// Run with "effective [path]" to instead print the fully resolved config for path (or $CONFIG),
+// including files it extends and environment overrides, along with where each value came from.
+//
+// Run with "schema" to print a JSON Schema for the config file, for use by editors and linters.
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "effective":
+			path := os.Getenv("CONFIG")
+			if len(os.Args) > 2 {
+				path = os.Args[2]
+			}
+
+			if !printEffectiveConfig(path) {
+				os.Exit(1)
+			}
+			return
+		case "schema":
+			if !printSchema() {
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	generateCompleteConfig()
+}
+
+func generateCompleteConfig() {
 
 	tmpFile, err := os.CreateTemp("", "cats-config-with-required-fields-*.json")
 	if err != nil {
@@ -47,12 +77,19 @@ func main() {
 		return
 	}
 
-	configJSON, err := json.MarshalIndent(defaultsConfig, "", "  ")
+	configJSON, err := json.Marshal(defaultsConfig)
 	if err != nil {
 		fmt.Println("Error marshalling config to JSON:", err)
 		return
 	}
 
+	// Secrets set by environment overrides, e.g. $CATS_ADMIN_PASSWORD, are resolved into the config.
+	configJSON, err = cfg.RedactConfigJSON(configJSON)
+	if err != nil {
+		fmt.Println("Error redacting secrets from the config:", err)
+		return
+	}
+
 	catsConfigName := "complete-cats-config.json"
 	err = os.WriteFile(filepath.Join(".", catsConfigName), configJSON, 0644)
 	if err != nil {
@@ -62,3 +99,48 @@ func main() {
 
 	fmt.Println("Config JSON saved to", catsConfigName)
 }
+
+func printEffectiveConfig(path string) bool {
+	if path == "" {
+		fmt.Println("Usage: catsconfiggenerator effective [path], or set $CONFIG")
+		return false
+	}
+
+	values, loadErr := cfg.NewEffectiveConfig(path)
+	if values == nil && loadErr != nil {
+		fmt.Println("Error loading config:", loadErr)
+		return false
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+	for _, value := range values {
+		valueJSON, err := json.Marshal(value.Value)
+		if err != nil {
+			fmt.Println("Error marshalling config value to JSON:", err)
+			return false
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", value.Key, valueJSON, value.Source)
+	}
+	w.Flush()
+
+	if loadErr != nil {
+		fmt.Println()
+		fmt.Println("The effective config is not valid:")
+		fmt.Println(loadErr)
+		return false
+	}
+
+	return true
+}
+
+func printSchema() bool {
+	schema, err := cfg.JSONSchema()
+	if err != nil {
+		fmt.Println("Error generating config schema:", err)
+		return false
+	}
+
+	fmt.Println(string(schema))
+	return true
+}
@@ -5,15 +5,16 @@ import (
 	"fmt"
 	"net"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
-	"github.com/cloudfoundry/cf-acceptance-tests/helpers/skip_messages"
 	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
 	"github.com/cloudfoundry/cf-test-helpers/v2/workflowhelpers"
 
 	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gexec"
 )
@@ -32,81 +33,81 @@ var (
 	SftpPath  string
 )
 
-func AppSyslogTcpDescribe(description string, callback func()) bool {
-	return Describe("[app_syslog_tcp]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeAppSyslogTcp() {
-				Skip(skip_messages.SkipAppSyslogTcpMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+var suites []string
+
+// Suites returns the labels of all test groups, e.g. "http2_routing" for specs in the
+// "[HTTP/2 routing]" group, as used by --label-filter, the run labels and timeout_overrides.
+func Suites() []string {
+	return suites
 }
 
-func AppsDescribe(description string, callback func()) bool {
-	return Describe("[apps]", func() {
+// suiteDescribe is the outermost container of every test group. It labels the specs of the group,
+// skips them unless the config enables the group and any Requires* decorators of the spec and, for
+// the duration of each spec, replaces Config with the config for the group, so that its
+// timeout_overrides apply.
+func suiteDescribe(g gate, description string, callback func(), decorators ...interface{}) bool {
+	suite := suiteLabel(g.tag)
+	if !slices.Contains(suites, suite) {
+		suites = append(suites, suite)
+	}
+
+	return Describe(g.tag, Label(g.labels...), func() {
 		BeforeEach(func() {
-			if !Config.GetIncludeApps() {
-				Skip(skip_messages.SkipAppsMessage)
-			}
+			globalConfig := Config
+			Config = Config.ForSuite(suite)
+			SetDefaultEventuallyTimeout(Config.DefaultTimeoutDuration())
+			DeferCleanup(func() {
+				Config = globalConfig
+				SetDefaultEventuallyTimeout(globalConfig.DefaultTimeoutDuration())
+			})
 		})
-		Describe(description, callback)
-	})
-}
 
-func IsolatedTCPRoutingDescribe(description string, callback func()) bool {
-	return Describe("[isolated tcp routing]", func() {
 		BeforeEach(func() {
-			if !Config.GetIncludeTCPIsolationSegments() {
-				Skip(skip_messages.SkipIsolatedTCPRoutingMessage)
+			for _, r := range g.requirementsFor(CurrentSpecReport().Labels()) {
+				if !r.enabled(Config) {
+					Skip(r.skipMessage)
+				}
 			}
 		})
-		Describe(description, callback)
-	})
-}
 
-func IsolationSegmentsDescribe(description string, callback func()) bool {
-	return Describe("[isolation_segments]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeIsolationSegments() {
-				Skip(skip_messages.SkipIsolationSegmentsMessage)
+		// Runs after the AfterEach of the spec, which deletes what it cleans up.
+		AfterEach(func() {
+			if RunID == "" || TestSetup == nil || CurrentSpecReport().State.Is(types.SpecStateSkipped) {
+				return
 			}
+			labelPushedRoutes(TestSetup.RegularUserContext().Space)
 		})
-		Describe(description, callback)
+
+		Describe(description, append(decorators, callback)...)
 	})
 }
 
-func DetectDescribe(description string, callback func()) bool {
-	return Describe("[detect]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeDetect() {
-				Skip(skip_messages.SkipDetectMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func AppSyslogTcpDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(appSyslogTcpGate, description, callback, decorators...)
 }
 
-func DockerDescribe(description string, callback func()) bool {
-	return Describe("[docker]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeDocker() {
-				Skip(skip_messages.SkipDockerMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func AppsDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(appsGate, description, callback, decorators...)
 }
 
-func CNBDescribe(description string, callback func()) bool {
-	return Describe("[cnb]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeCNB() {
-				Skip(skip_messages.SkipCNBMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func IsolatedTCPRoutingDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(isolatedTCPRoutingGate, description, callback, decorators...)
+}
+
+func IsolationSegmentsDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(isolationSegmentsGate, description, callback, decorators...)
+}
+
+func DetectDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(detectGate, description, callback, decorators...)
+}
+
+func DockerDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(dockerGate, description, callback, decorators...)
+}
+
+func CNBDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(cnbGate, description, callback, decorators...)
 }
 
 const (
@@ -116,237 +117,109 @@ const (
 	WindowsLifecycle          = "windows"
 )
 
-func FileBasedServiceBindingsDescribe(description string, lifecycle string, callback func()) bool {
-	return Describe(fmt.Sprintf("[file-based service bindings %s]", lifecycle), func() {
-		BeforeEach(func() {
-			if lifecycle == BuildpackLifecycle && !Config.GetIncludeFileBasedServiceBindings() {
-				Skip(skip_messages.SkipFileBasedServiceBindingsBuildpackApp)
-			}
-			if lifecycle == CNBLifecycle && (!Config.GetIncludeFileBasedServiceBindings() || !Config.GetIncludeCNB()) {
-				Skip(skip_messages.SkipFileBasedServiceBindingsCnbApp)
-			}
-			if lifecycle == DockerLifecycle && (!Config.GetIncludeFileBasedServiceBindings() || !Config.GetIncludeDocker()) {
-				Skip(skip_messages.SkipFileBasedServiceBindingsDockerApp)
-			}
-			if lifecycle == WindowsLifecycle && (!Config.GetIncludeFileBasedServiceBindings() || !Config.GetIncludeWindows()) {
-				Skip(skip_messages.SkipFileBasedServiceBindingsWindowsApp)
-			}
-		})
-		Describe(description, callback)
-	})
+func FileBasedServiceBindingsDescribe(description string, lifecycle string, callback func(), decorators ...interface{}) bool {
+	g, ok := fileBasedServiceBindingsGates[lifecycle]
+	if !ok {
+		g = gate{tag: fmt.Sprintf("[file-based service bindings %s]", lifecycle)}
+		g.labels = []string{suiteLabel(g.tag)}
+	}
+	return suiteDescribe(g, description, callback, decorators...)
 }
 
-func IPv6Describe(description string, callback func()) bool {
-	return Describe("[ipv6]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeIPv6() {
-				Skip(skip_messages.SkipIPv6)
-			}
-		})
-		Describe(description, callback)
-	})
+func IPv6Describe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(ipv6Gate, description, callback, decorators...)
 }
 
-func InternetDependentDescribe(description string, callback func()) bool {
-	return Describe("[internet_dependent]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeInternetDependent() {
-				Skip(skip_messages.SkipInternetDependentMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func InternetDependentDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(internetDependentGate, description, callback, decorators...)
 }
 
-func RouteServicesDescribe(description string, callback func()) bool {
-	return Describe("[route_services]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeRouteServices() {
-				Skip(skip_messages.SkipRouteServicesMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func RevisionsDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(revisionsGate, description, callback, decorators...)
 }
 
-func RoutingDescribe(description string, callback func()) bool {
-	return Describe("[routing]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeRouting() {
-				Skip(skip_messages.SkipRoutingMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func SidecarsDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(sidecarsGate, description, callback, decorators...)
 }
 
-func HTTP2RoutingDescribe(description string, callback func()) bool {
-	return Describe("[HTTP/2 routing]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeHTTP2Routing() {
-				Skip(skip_messages.SkipHTTP2RoutingMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func MetadataDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(metadataGate, description, callback, decorators...)
 }
 
-func TCPRoutingDescribe(description string, callback func()) bool {
-	return Describe("[tcp routing]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeTCPRouting() {
-				Skip(skip_messages.SkipTCPRoutingMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func QuotasDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(quotasGate, description, callback, decorators...)
 }
 
-func RoutingIsolationSegmentsDescribe(description string, callback func()) bool {
-	return Describe("[routing_isolation_segments]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeRoutingIsolationSegments() {
-				Skip(skip_messages.SkipRoutingIsolationSegmentsMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func RoleMatrixDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(roleMatrixGate, description, callback, decorators...)
 }
 
-func ZipkinDescribe(description string, callback func()) bool {
-	return Describe("[zipkin]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeRouting() {
-				Skip(skip_messages.SkipRoutingMessage)
-			}
+func RouteServicesDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(routeServicesGate, description, callback, decorators...)
+}
 
-			if !Config.GetIncludeZipkin() {
-				Skip(skip_messages.SkipZipkinMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func RoutingDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(routingGate, description, callback, decorators...)
 }
 
-func SecurityGroupsDescribe(description string, callback func()) bool {
-	return Describe("[security_groups]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeSecurityGroups() {
-				Skip(skip_messages.SkipSecurityGroupsMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func HTTP2RoutingDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(http2RoutingGate, description, callback, decorators...)
 }
 
-func CommaDelimitedSecurityGroupsDescribe(description string, callback func()) bool {
-	return Describe("[comma_delimited_security_groups]", func() {
-		BeforeEach(func() {
-			if !Config.GetCommaDelimitedASGsEnabled() {
-				Skip(skip_messages.SkipCommaDelimitedSecurityGroupsMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func TCPRoutingDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(tcpRoutingGate, description, callback, decorators...)
 }
 
-func ServiceDiscoveryDescribe(description string, callback func()) bool {
-	return Describe("[service discovery]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeServiceDiscovery() {
-				Skip(skip_messages.SkipServiceDiscoveryMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func RoutingIsolationSegmentsDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(routingIsolationSegmentsGate, description, callback, decorators...)
 }
 
-func ServicesDescribe(description string, callback func()) bool {
-	return Describe("[services]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeServices() {
-				Skip(skip_messages.SkipServicesMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func ZipkinDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(zipkinGate, description, callback, decorators...)
 }
 
-func ServiceInstanceSharingDescribe(description string, callback func()) bool {
-	return Describe("[service instance sharing]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeServiceInstanceSharing() {
-				Skip(skip_messages.SkipServiceInstanceSharingMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func SecurityGroupsDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(securityGroupsGate, description, callback, decorators...)
 }
 
-func ServiceCredentialBindingRotationDescribe(description string, callback func()) bool {
-	return Describe("[service credential binding rotation]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeServiceCredentialBindingRotation() {
-				Skip(skip_messages.SkipServiceCredentialBindingRotationMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func CommaDelimitedSecurityGroupsDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(commaDelimitedSecurityGroupsGate, description, callback, decorators...)
 }
 
-func ServicesSsoDescribe(description string, callback func()) bool {
-	return Describe("[services sso]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeSSO() {
-				Skip(skip_messages.SkipSSOMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func ServiceDiscoveryDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(serviceDiscoveryGate, description, callback, decorators...)
 }
 
-func UserProvidedServicesDescribe(description string, callback func()) bool {
-	return Describe("[user provided services]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeUserProvidedServices() {
-				Skip(skip_messages.SkipUserProvidedServicesMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func ServicesDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(servicesGate, description, callback, decorators...)
 }
 
-func SshDescribe(description string, callback func()) bool {
-	return Describe("[ssh]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeSsh() {
-				Skip(skip_messages.SkipSSHMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func ServiceInstanceSharingDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(serviceInstanceSharingGate, description, callback, decorators...)
 }
 
-func V3Describe(description string, callback func()) bool {
-	return Describe("[v3]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeV3() {
-				Skip(skip_messages.SkipV3Message)
-			}
-		})
-		Describe(description, callback)
-	})
+func ServiceCredentialBindingRotationDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(serviceCredentialBindingRotationGate, description, callback, decorators...)
 }
 
-func TasksDescribe(description string, callback func()) bool {
-	return Describe("[tasks]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeTasks() {
-				Skip(skip_messages.SkipTasksMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func ServicesSsoDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(servicesSsoGate, description, callback, decorators...)
+}
+
+func UserProvidedServicesDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(userProvidedServicesGate, description, callback, decorators...)
+}
+
+func SshDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(sshGate, description, callback, decorators...)
+}
+
+func V3Describe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(v3Gate, description, callback, decorators...)
+}
+
+func TasksDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(tasksGate, description, callback, decorators...)
 }
 
 func GuidForAppName(appName string) string {
@@ -358,106 +231,40 @@ func GuidForAppName(appName string) string {
 	return appGuid
 }
 
-func CredhubDescribe(description string, callback func()) bool {
-	return Describe("[credhub]", func() {
-		BeforeEach(func() {
-			if !(Config.GetIncludeCredhubAssisted() || Config.GetIncludeCredhubNonAssisted()) {
-				Skip(skip_messages.SkipCredhubMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func CredhubDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(credhubGate, description, callback, decorators...)
 }
 
-func AssistedCredhubDescribe(description string, callback func()) bool {
-	return Describe("[assisted credhub]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeCredhubAssisted() {
-				Skip(skip_messages.SkipAssistedCredhubMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func AssistedCredhubDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(assistedCredhubGate, description, callback, decorators...)
 }
 
-func NonAssistedCredhubDescribe(description string, callback func()) bool {
-	return Describe("[non-assisted credhub]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeCredhubNonAssisted() {
-				Skip(skip_messages.SkipNonAssistedCredhubMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func NonAssistedCredhubDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(nonAssistedCredhubGate, description, callback, decorators...)
 }
 
-func WindowsCredhubDescribe(description string, callback func()) bool {
-	return Describe("[windows credhub]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeWindows() {
-				Skip(skip_messages.SkipWindowsMessage)
-			}
-			if !(Config.GetIncludeCredhubAssisted() || Config.GetIncludeCredhubNonAssisted()) {
-				Skip(skip_messages.SkipCredhubMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func WindowsCredhubDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(windowsCredhubGate, description, callback, decorators...)
 }
 
-func WindowsAssistedCredhubDescribe(description string, callback func()) bool {
-	return Describe("[windows assisted credhub]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeCredhubAssisted() {
-				Skip(skip_messages.SkipAssistedCredhubMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func WindowsAssistedCredhubDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(windowsAssistedCredhubGate, description, callback, decorators...)
 }
 
-func WindowsNonAssistedCredhubDescribe(description string, callback func()) bool {
-	return Describe("[windows non-assisted credhub]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeCredhubNonAssisted() {
-				Skip(skip_messages.SkipNonAssistedCredhubMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func WindowsNonAssistedCredhubDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(windowsNonAssistedCredhubGate, description, callback, decorators...)
 }
 
-func WindowsDescribe(description string, callback func()) bool {
-	return Describe("[windows]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeWindows() {
-				Skip(skip_messages.SkipWindowsMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func WindowsDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(windowsGate, description, callback, decorators...)
 }
 
-func WindowsTCPRoutingDescribe(description string, callback func()) bool {
-	return Describe("[windows routing]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeTCPRouting() || !Config.GetIncludeWindows() {
-				Skip(skip_messages.SkipTCPRoutingMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func WindowsTCPRoutingDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(windowsTCPRoutingGate, description, callback, decorators...)
 }
 
-func VolumeServicesDescribe(description string, callback func()) bool {
-	return Describe("[volume_services]", func() {
-		BeforeEach(func() {
-			if !Config.GetIncludeVolumeServices() {
-				Skip(skip_messages.SkipVolumeServicesMessage)
-			}
-		})
-		Describe(description, callback)
-	})
+func VolumeServicesDescribe(description string, callback func(), decorators ...interface{}) bool {
+	return suiteDescribe(volumeServicesGate, description, callback, decorators...)
 }
 
 func GetNServerResponses(n int, domainName, externalPort1 string) ([]string, error) {
@@ -0,0 +1,55 @@
+package cats_suite_helpers
+
+import (
+	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
+	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
+	"github.com/cloudfoundry/cf-test-helpers/v2/workflowhelpers"
+
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+)
+
+// testSpace is the org and space that cf-test-helpers creates for the regular user.
+type testSpace interface {
+	Create()
+	Destroy()
+	OrganizationName() string
+	SpaceName() string
+	QuotaName() string
+}
+
+// clientTestSpace gives the UAA client the specs run as the roles cf-test-helpers only gives to
+// users, as soon as the space is created and before the client targets it.
+type clientTestSpace struct {
+	testSpace
+	client string
+}
+
+func (s clientTestSpace) Create() {
+	s.testSpace.Create()
+	for _, role := range []string{"SpaceManager", "SpaceDeveloper", "SpaceAuditor"} {
+		setRole := cf.Cf("set-space-role", s.client, s.OrganizationName(), s.SpaceName(), role, "--client").Wait()
+		Expect(setRole).To(Exit(0), "failed to give the client the %s role", role)
+	}
+}
+
+// NewSuiteSetup returns the setup of the regular user and the test space, in which the regular
+// user is the UAA client existing_client if c sets one.
+func NewSuiteSetup(c CatsConfig) *workflowhelpers.ReproducibleTestSuiteSetup {
+	setup := workflowhelpers.NewTestSuiteSetup(c)
+	if c.UsesClientCredentials() {
+		setup.TestSpace = clientTestSpace{testSpace: setup.TestSpace, client: c.GetExistingClient()}
+	}
+	return setup
+}
+
+// RegularUserRoleArgs returns the arguments of cf set-space-role or cf set-org-role that give the
+// regular user, or client, of the run a role, e.g.
+// cf.Cf(RegularUserRoleArgs("set-space-role", orgName, spaceName, "SpaceDeveloper")...).
+func RegularUserRoleArgs(command string, target ...string) []string {
+	args := append([]string{command, TestSetup.RegularUserContext().Username}, target...)
+	if TestSetup.RegularUserContext().UseClientCredentials {
+		args = append(args, "--client")
+	}
+	return args
+}
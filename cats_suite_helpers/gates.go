@@ -0,0 +1,245 @@
+package cats_suite_helpers
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/skip_messages"
+
+	. "github.com/onsi/ginkgo/v2"
+)
+
+// Labels attached to test groups in addition to the name of the group, e.g. "tcp_routing" for
+// "[tcp routing]", so that specs can be selected with --label-filter.
+const (
+	BuildpackLabel = "buildpack"
+	CNBLabel       = "cnb"
+	DockerLabel    = "docker"
+	WindowsLabel   = "windows"
+
+	NeedsAdminLabel     = "needs-admin"
+	NeedsInternetLabel  = "needs-internet"
+	NeedsTCPDomainLabel = "needs-tcp-domain"
+)
+
+// requirement is a config value that must be enabled for the specs of a test group to run.
+type requirement struct {
+	key         string
+	enabled     func(CatsConfig) bool
+	skipMessage string
+}
+
+type gate struct {
+	tag          string
+	labels       []string
+	requirements []requirement
+}
+
+var gates []gate
+
+func newGate(tag string, labels []string, requirements ...requirement) gate {
+	g := gate{
+		tag:          tag,
+		labels:       append([]string{suiteLabel(tag)}, labels...),
+		requirements: requirements,
+	}
+	gates = append(gates, g)
+	return g
+}
+
+// suiteLabel turns the tag of a test group into a valid label, e.g. "[HTTP/2 routing]" into "http2_routing".
+func suiteLabel(tag string) string {
+	name := strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(tag, "["), "]"))
+	return strings.NewReplacer("/", "", " ", "_", "-", "_").Replace(name)
+}
+
+// requirementsFor returns the requirements of the group followed by those declared by the labels
+// of a spec, in the order they are checked.
+func (g gate) requirementsFor(labels []string) []requirement {
+	requirements := append([]requirement{}, g.requirements...)
+	for _, label := range labels {
+		if r, ok := specRequirements[label]; ok {
+			requirements = append(requirements, r)
+		}
+	}
+	return requirements
+}
+
+// firstDisabled returns the first of requirements that c disables, ignoring those set to "auto".
+func firstDisabled(requirements []requirement, c CatsConfig, autoKeys []string) (requirement, bool) {
+	for _, r := range requirements {
+		if !slices.Contains(autoKeys, r.key) && !r.enabled(c) {
+			return r, true
+		}
+	}
+	return requirement{}, false
+}
+
+// LabelFilter returns a --label-filter expression that excludes the test groups and specs disabled
+// by c, or "" if everything is enabled. Requirements set to "auto" are ignored, since those are
+// resolved once the suite has started.
+func LabelFilter(c CatsConfig) string {
+	autoKeys := c.GetAutoKeys()
+
+	var excluded []string
+	exclude := func(label string, requirements ...requirement) {
+		_, disabled := firstDisabled(requirements, c, autoKeys)
+		if disabled && !slices.Contains(excluded, "!"+label) {
+			excluded = append(excluded, "!"+label)
+		}
+	}
+
+	for _, g := range gates {
+		exclude(g.labels[0], g.requirements...)
+	}
+	for _, label := range slices.Sorted(maps.Keys(specRequirements)) {
+		exclude(label, specRequirements[label])
+	}
+	return strings.Join(excluded, " && ")
+}
+
+func includeCredhub(c CatsConfig) bool {
+	return c.GetIncludeCredhubAssisted() || c.GetIncludeCredhubNonAssisted()
+}
+
+func usesPasswordUser(c CatsConfig) bool {
+	return !c.UsesClientCredentials()
+}
+
+var (
+	requireApps                             = requirement{"include_apps", CatsConfig.GetIncludeApps, skip_messages.SkipAppsMessage}
+	requireAppSyslogTcp                     = requirement{"include_app_syslog_tcp", CatsConfig.GetIncludeAppSyslogTcp, skip_messages.SkipAppSyslogTcpMessage}
+	requireTCPIsolationSegments             = requirement{"include_tcp_isolation_segments", CatsConfig.GetIncludeTCPIsolationSegments, skip_messages.SkipIsolatedTCPRoutingMessage}
+	requireIsolationSegments                = requirement{"include_isolation_segments", CatsConfig.GetIncludeIsolationSegments, skip_messages.SkipIsolationSegmentsMessage}
+	requireDetect                           = requirement{"include_detect", CatsConfig.GetIncludeDetect, skip_messages.SkipDetectMessage}
+	requireDocker                           = requirement{"include_docker", CatsConfig.GetIncludeDocker, skip_messages.SkipDockerMessage}
+	requireCNB                              = requirement{"include_cnb", CatsConfig.GetIncludeCNB, skip_messages.SkipCNBMessage}
+	requireIPv6                             = requirement{"include_ipv6", CatsConfig.GetIncludeIPv6, skip_messages.SkipIPv6}
+	requireInternetDependent                = requirement{"include_internet_dependent", CatsConfig.GetIncludeInternetDependent, skip_messages.SkipInternetDependentMessage}
+	requireRevisions                        = requirement{"include_revisions", CatsConfig.GetIncludeRevisions, skip_messages.SkipRevisionsMessage}
+	requireSidecars                         = requirement{"include_sidecars", CatsConfig.GetIncludeSidecars, skip_messages.SkipSidecarsMessage}
+	requireMetadata                         = requirement{"include_metadata", CatsConfig.GetIncludeMetadata, skip_messages.SkipMetadataMessage}
+	requireQuotas                           = requirement{"include_quotas", CatsConfig.GetIncludeQuotas, skip_messages.SkipQuotasMessage}
+	requireRoleMatrix                       = requirement{"include_role_matrix", CatsConfig.GetIncludeRoleMatrix, skip_messages.SkipRoleMatrixMessage}
+	requireRouteServices                    = requirement{"include_route_services", CatsConfig.GetIncludeRouteServices, skip_messages.SkipRouteServicesMessage}
+	requireRouting                          = requirement{"include_routing", CatsConfig.GetIncludeRouting, skip_messages.SkipRoutingMessage}
+	requireHTTP2Routing                     = requirement{"include_http2_routing", CatsConfig.GetIncludeHTTP2Routing, skip_messages.SkipHTTP2RoutingMessage}
+	requireTCPRouting                       = requirement{"include_tcp_routing", CatsConfig.GetIncludeTCPRouting, skip_messages.SkipTCPRoutingMessage}
+	requireRoutingIsolationSegments         = requirement{"include_routing_isolation_segments", CatsConfig.GetIncludeRoutingIsolationSegments, skip_messages.SkipRoutingIsolationSegmentsMessage}
+	requireZipkin                           = requirement{"include_zipkin", CatsConfig.GetIncludeZipkin, skip_messages.SkipZipkinMessage}
+	requireSecurityGroups                   = requirement{"include_security_groups", CatsConfig.GetIncludeSecurityGroups, skip_messages.SkipSecurityGroupsMessage}
+	requireCommaDelimitedASGs               = requirement{"comma_delim_asgs_enabled", CatsConfig.GetCommaDelimitedASGsEnabled, skip_messages.SkipCommaDelimitedSecurityGroupsMessage}
+	requireServiceDiscovery                 = requirement{"include_service_discovery", CatsConfig.GetIncludeServiceDiscovery, skip_messages.SkipServiceDiscoveryMessage}
+	requireServices                         = requirement{"include_services", CatsConfig.GetIncludeServices, skip_messages.SkipServicesMessage}
+	requireServiceInstanceSharing           = requirement{"include_service_instance_sharing", CatsConfig.GetIncludeServiceInstanceSharing, skip_messages.SkipServiceInstanceSharingMessage}
+	requireServiceCredentialBindingRotation = requirement{"include_service_credential_binding_rotation", CatsConfig.GetIncludeServiceCredentialBindingRotation, skip_messages.SkipServiceCredentialBindingRotationMessage}
+	requireSSO                              = requirement{"include_sso", CatsConfig.GetIncludeSSO, skip_messages.SkipSSOMessage}
+	requireUserProvidedServices             = requirement{"include_user_provided_services", CatsConfig.GetIncludeUserProvidedServices, skip_messages.SkipUserProvidedServicesMessage}
+	requireSsh                              = requirement{"include_ssh", CatsConfig.GetIncludeSsh, skip_messages.SkipSSHMessage}
+	requireV3                               = requirement{"include_v3", CatsConfig.GetIncludeV3, skip_messages.SkipV3Message}
+	requireTasks                            = requirement{"include_tasks", CatsConfig.GetIncludeTasks, skip_messages.SkipTasksMessage}
+	requireCredhub                          = requirement{"credhub_mode", includeCredhub, skip_messages.SkipCredhubMessage}
+	requireAssistedCredhub                  = requirement{"credhub_mode", CatsConfig.GetIncludeCredhubAssisted, skip_messages.SkipAssistedCredhubMessage}
+	requireNonAssistedCredhub               = requirement{"credhub_mode", CatsConfig.GetIncludeCredhubNonAssisted, skip_messages.SkipNonAssistedCredhubMessage}
+	requireWindows                          = requirement{"include_windows", CatsConfig.GetIncludeWindows, skip_messages.SkipWindowsMessage}
+	requireVolumeServices                   = requirement{"include_volume_services", CatsConfig.GetIncludeVolumeServices, skip_messages.SkipVolumeServicesMessage}
+	requireFileBasedServiceBindings         = requirement{"include_file_based_service_bindings", CatsConfig.GetIncludeFileBasedServiceBindings, skip_messages.SkipFileBasedServiceBindingsBuildpackApp}
+	requirePasswordUser                     = requirement{"existing_client", usesPasswordUser, skip_messages.SkipClientCredentialsMessage}
+)
+
+// specRequirements are the requirements declared on individual containers and specs, keyed by label.
+var specRequirements = map[string]requirement{}
+
+func requires(label string, r requirement, labels ...string) Labels {
+	specRequirements[label] = r
+	return Label(append([]string{label}, labels...)...)
+}
+
+// Decorators for containers and specs within a test group that need more than the group itself,
+// e.g. Context("with a network policy", RequiresContainerNetworking, func() { ... }). Unlike a Skip
+// in a BeforeEach, they are checked before any setup runs and are known to --label-filter and dry_run.
+var (
+	RequiresAssistedCredhub       = requires("requires-assisted-credhub", requireAssistedCredhub)
+	RequiresContainerNetworking   = requires("requires-container-networking", requirement{"include_container_networking", CatsConfig.GetIncludeContainerNetworking, skip_messages.SkipContainerNetworkingMessage})
+	RequiresDeployments           = requires("requires-deployments", requirement{"include_deployments", CatsConfig.GetIncludeDeployments, skip_messages.SkipDeploymentsMessage})
+	RequiresDocker                = requires("requires-docker", requireDocker, DockerLabel, NeedsInternetLabel)
+	RequiresInternetDependent     = requires("requires-internet-dependent", requireInternetDependent, NeedsInternetLabel)
+	RequiresNonAssistedCredhub    = requires("requires-non-assisted-credhub", requireNonAssistedCredhub)
+	RequiresPasswordUser          = requires("requires-password-user", requirePasswordUser)
+	RequiresPrivateDockerRegistry = requires("requires-private-docker-registry", requirement{"include_private_docker_registry", CatsConfig.GetIncludePrivateDockerRegistry, skip_messages.SkipPrivateDockerRegistryMessage})
+	RequiresReadinessHealthChecks = requires("requires-readiness-health-checks", requirement{"readiness_health_checks_enabled", CatsConfig.GetReadinessHealthChecksEnabled, skip_messages.SkipReadinessHealthChecksMessage})
+	RequiresSecurityGroups        = requires("requires-security-groups", requireSecurityGroups)
+	RequiresServices              = requires("requires-services", requireServices)
+	RequiresSsh                   = requires("requires-ssh", requireSsh, RequiresPasswordUser...)
+	RequiresTasks                 = requires("requires-tasks", requireTasks)
+	RequiresTCPRouting            = requires("requires-tcp-routing", requireTCPRouting, NeedsTCPDomainLabel)
+	RequiresWindowsContextPath    = requires("requires-windows-context-path", requirement{"use_windows_context_path", CatsConfig.GetUseWindowsContextPath, skip_messages.SkipWindowsContextPathsMessage})
+	RequiresWindowsTestTask       = requires("requires-windows-test-task", requirement{"use_windows_test_task", CatsConfig.GetUseWindowsTestTask, skip_messages.SkipWindowsTasksMessage})
+)
+
+// requireWith returns r with a different skip message, for groups that report a combination of
+// requirements with a single message.
+func requireWith(r requirement, skipMessage string) requirement {
+	r.skipMessage = skipMessage
+	return r
+}
+
+var (
+	appSyslogTcpGate                     = newGate("[app_syslog_tcp]", []string{BuildpackLabel, NeedsAdminLabel, NeedsTCPDomainLabel}, requireAppSyslogTcp)
+	appsGate                             = newGate("[apps]", []string{BuildpackLabel, NeedsAdminLabel}, requireApps)
+	isolatedTCPRoutingGate               = newGate("[isolated tcp routing]", []string{BuildpackLabel, NeedsAdminLabel, NeedsTCPDomainLabel}, requireTCPIsolationSegments)
+	isolationSegmentsGate                = newGate("[isolation_segments]", []string{BuildpackLabel, NeedsAdminLabel}, requireIsolationSegments)
+	detectGate                           = newGate("[detect]", []string{BuildpackLabel}, requireDetect)
+	dockerGate                           = newGate("[docker]", []string{DockerLabel, NeedsAdminLabel, NeedsInternetLabel}, requireDocker)
+	cnbGate                              = newGate("[cnb]", []string{CNBLabel, NeedsAdminLabel, NeedsInternetLabel}, requireCNB)
+	ipv6Gate                             = newGate("[ipv6]", []string{BuildpackLabel}, requireIPv6)
+	internetDependentGate                = newGate("[internet_dependent]", []string{BuildpackLabel, NeedsInternetLabel}, requireInternetDependent)
+	revisionsGate                        = newGate("[revisions]", []string{BuildpackLabel}, requireRevisions)
+	sidecarsGate                         = newGate("[sidecars]", []string{BuildpackLabel}, requireSidecars)
+	metadataGate                         = newGate("[metadata]", []string{BuildpackLabel, NeedsAdminLabel}, requireMetadata)
+	quotasGate                           = newGate("[quotas]", []string{BuildpackLabel, NeedsAdminLabel}, requireQuotas)
+	roleMatrixGate                       = newGate("[role matrix]", []string{BuildpackLabel, NeedsAdminLabel}, requireRoleMatrix, requirePasswordUser)
+	routeServicesGate                    = newGate("[route_services]", []string{BuildpackLabel, NeedsAdminLabel}, requireRouteServices)
+	routingGate                          = newGate("[routing]", []string{BuildpackLabel, NeedsAdminLabel}, requireRouting)
+	http2RoutingGate                     = newGate("[HTTP/2 routing]", []string{BuildpackLabel}, requireHTTP2Routing)
+	tcpRoutingGate                       = newGate("[tcp routing]", []string{BuildpackLabel, NeedsAdminLabel, NeedsTCPDomainLabel}, requireTCPRouting)
+	routingIsolationSegmentsGate         = newGate("[routing_isolation_segments]", []string{BuildpackLabel, NeedsAdminLabel}, requireRoutingIsolationSegments)
+	zipkinGate                           = newGate("[zipkin]", []string{BuildpackLabel}, requireRouting, requireZipkin)
+	securityGroupsGate                   = newGate("[security_groups]", []string{BuildpackLabel, NeedsAdminLabel}, requireSecurityGroups)
+	commaDelimitedSecurityGroupsGate     = newGate("[comma_delimited_security_groups]", []string{BuildpackLabel, NeedsAdminLabel}, requireCommaDelimitedASGs)
+	serviceDiscoveryGate                 = newGate("[service discovery]", []string{BuildpackLabel, NeedsAdminLabel}, requireServiceDiscovery)
+	servicesGate                         = newGate("[services]", []string{BuildpackLabel, NeedsAdminLabel}, requireServices)
+	serviceInstanceSharingGate           = newGate("[service instance sharing]", []string{BuildpackLabel, NeedsAdminLabel}, requireServiceInstanceSharing)
+	serviceCredentialBindingRotationGate = newGate("[service credential binding rotation]", []string{BuildpackLabel}, requireServiceCredentialBindingRotation)
+	servicesSsoGate                      = newGate("[services sso]", []string{BuildpackLabel, NeedsAdminLabel}, requireSSO, requirePasswordUser)
+	userProvidedServicesGate             = newGate("[user provided services]", []string{BuildpackLabel}, requireUserProvidedServices)
+	sshGate                              = newGate("[ssh]", []string{BuildpackLabel}, requireSsh, requirePasswordUser)
+	v3Gate                               = newGate("[v3]", []string{BuildpackLabel, NeedsAdminLabel}, requireV3)
+	tasksGate                            = newGate("[tasks]", []string{BuildpackLabel, NeedsAdminLabel}, requireTasks)
+	credhubGate                          = newGate("[credhub]", []string{BuildpackLabel, NeedsAdminLabel}, requireCredhub)
+	assistedCredhubGate                  = newGate("[assisted credhub]", []string{BuildpackLabel, NeedsAdminLabel}, requireAssistedCredhub)
+	nonAssistedCredhubGate               = newGate("[non-assisted credhub]", []string{BuildpackLabel, NeedsAdminLabel}, requireNonAssistedCredhub)
+	windowsCredhubGate                   = newGate("[windows credhub]", []string{WindowsLabel, NeedsAdminLabel}, requireWindows, requireCredhub)
+	windowsAssistedCredhubGate           = newGate("[windows assisted credhub]", []string{WindowsLabel, NeedsAdminLabel}, requireAssistedCredhub)
+	windowsNonAssistedCredhubGate        = newGate("[windows non-assisted credhub]", []string{WindowsLabel, NeedsAdminLabel}, requireNonAssistedCredhub)
+	windowsGate                          = newGate("[windows]", []string{WindowsLabel, NeedsAdminLabel}, requireWindows)
+	windowsTCPRoutingGate                = newGate("[windows routing]", []string{WindowsLabel, NeedsAdminLabel, NeedsTCPDomainLabel},
+		requireTCPRouting, requireWith(requireWindows, skip_messages.SkipTCPRoutingMessage))
+	volumeServicesGate = newGate("[volume_services]", []string{BuildpackLabel, NeedsAdminLabel, NeedsTCPDomainLabel}, requireVolumeServices)
+
+	fileBasedServiceBindingsGates = map[string]gate{
+		BuildpackLifecycle: newGate("[file-based service bindings buildpack]", []string{BuildpackLabel},
+			requireFileBasedServiceBindings),
+		CNBLifecycle: newGate("[file-based service bindings CNB]", []string{CNBLabel, NeedsInternetLabel},
+			requireWith(requireFileBasedServiceBindings, skip_messages.SkipFileBasedServiceBindingsCnbApp),
+			requireWith(requireCNB, skip_messages.SkipFileBasedServiceBindingsCnbApp)),
+		DockerLifecycle: newGate("[file-based service bindings Docker]", []string{DockerLabel, NeedsInternetLabel},
+			requireWith(requireFileBasedServiceBindings, skip_messages.SkipFileBasedServiceBindingsDockerApp),
+			requireWith(requireDocker, skip_messages.SkipFileBasedServiceBindingsDockerApp)),
+		WindowsLifecycle: newGate("[file-based service bindings windows]", []string{WindowsLabel},
+			requireWith(requireFileBasedServiceBindings, skip_messages.SkipFileBasedServiceBindingsWindowsApp),
+			requireWith(requireWindows, skip_messages.SkipFileBasedServiceBindingsWindowsApp)),
+	}
+)
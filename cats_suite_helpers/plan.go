@@ -0,0 +1,169 @@
+package cats_suite_helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+const PlanFileName = "plan.json"
+
+// Statuses of a spec in a plan.
+const (
+	PlanWillRun     = "will run"
+	PlanSkipped     = "skipped"
+	PlanAuto        = "auto"
+	PlanPending     = "pending"
+	PlanNotSelected = "not selected"
+)
+
+type PlannedSpec struct {
+	Text     string `json:"text"`
+	Location string `json:"location"`
+	Status   string `json:"status"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type SuitePlan struct {
+	Suite   string        `json:"suite"`
+	WillRun int           `json:"will_run"`
+	Skipped int           `json:"skipped"`
+	Specs   []PlannedSpec `json:"specs"`
+}
+
+// NewPlan decides for every spec in report, typically that of a dry run, whether it will run with
+// config c and, if not, why. It only looks at the config and the labels of the specs, so it does
+// not need to talk to the platform.
+func NewPlan(c CatsConfig, report Report) []SuitePlan {
+	autoKeys := c.GetAutoKeys()
+
+	var plans []SuitePlan
+	for _, spec := range report.SpecReports {
+		if spec.LeafNodeType != types.NodeTypeIt || len(spec.ContainerHierarchyTexts) == 0 {
+			continue
+		}
+
+		tag := spec.ContainerHierarchyTexts[0]
+		suite := strings.TrimSuffix(strings.TrimPrefix(tag, "["), "]")
+		i := slices.IndexFunc(plans, func(p SuitePlan) bool { return p.Suite == suite })
+		if i == -1 {
+			plans = append(plans, SuitePlan{Suite: suite})
+			i = len(plans) - 1
+		}
+
+		planned := PlannedSpec{
+			Text:     spec.FullText(),
+			Location: relativeLocation(spec.LeafNodeLocation),
+		}
+		switch spec.State {
+		case types.SpecStatePending:
+			planned.Status = PlanPending
+		case types.SpecStateSkipped:
+			planned.Status, planned.Reason = PlanNotSelected, "excluded by --focus, --skip or --label-filter"
+		default:
+			planned.Status, planned.Reason = planSpec(gateFor(tag).requirementsFor(spec.Labels()), c, autoKeys)
+		}
+
+		if planned.Status == PlanWillRun {
+			plans[i].WillRun++
+		} else {
+			plans[i].Skipped++
+		}
+		plans[i].Specs = append(plans[i].Specs, planned)
+	}
+
+	slices.SortFunc(plans, func(a, b SuitePlan) int { return strings.Compare(a.Suite, b.Suite) })
+	for _, plan := range plans {
+		slices.SortFunc(plan.Specs, func(a, b PlannedSpec) int { return strings.Compare(a.Text, b.Text) })
+	}
+	return plans
+}
+
+func planSpec(requirements []requirement, c CatsConfig, autoKeys []string) (string, string) {
+	if r, disabled := firstDisabled(requirements, c, autoKeys); disabled {
+		return PlanSkipped, r.skipMessage
+	}
+
+	for _, r := range requirements {
+		if slices.Contains(autoKeys, r.key) {
+			return PlanAuto, fmt.Sprintf("depends on '%s', which is resolved when the suite starts", r.key)
+		}
+	}
+	return PlanWillRun, ""
+}
+
+func relativeLocation(location types.CodeLocation) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return location.String()
+	}
+	fileName, err := filepath.Rel(wd, location.FileName)
+	if err != nil {
+		return location.String()
+	}
+	return fmt.Sprintf("%s:%d", fileName, location.LineNumber)
+}
+
+func gateFor(tag string) gate {
+	i := slices.IndexFunc(gates, func(g gate) bool { return g.tag == tag })
+	if i == -1 {
+		return gate{tag: tag}
+	}
+	return gates[i]
+}
+
+// WritePlan prints how many specs of each test group will run and why the others won't.
+func WritePlan(w io.Writer, plans []SuitePlan) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SUITE\tWILL RUN\tSKIPPED\tREASON")
+	for _, plan := range plans {
+		var reasons []string
+		skipped := map[string]int{}
+		for _, spec := range plan.Specs {
+			if spec.Status == PlanWillRun {
+				continue
+			}
+
+			// skip messages end with notes on separate lines, which are left to the JSON plan
+			reason := spec.Status
+			if spec.Reason != "" {
+				reason = strings.TrimSpace(strings.SplitN(spec.Reason, "\n", 2)[0])
+			}
+			if skipped[reason] == 0 {
+				reasons = append(reasons, reason)
+			}
+			skipped[reason]++
+		}
+
+		if len(reasons) == 0 {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t\n", plan.Suite, plan.WillRun, 0)
+		}
+		for i, reason := range reasons {
+			if i == 0 {
+				fmt.Fprintf(tw, "%s\t%d\t%d\t%s\n", plan.Suite, plan.WillRun, skipped[reason], reason)
+			} else {
+				fmt.Fprintf(tw, "\t\t%d\t%s\n", skipped[reason], reason)
+			}
+		}
+	}
+	return tw.Flush()
+}
+
+// SavePlan writes the plan as JSON to PlanFileName in dir.
+func SavePlan(dir string, plans []SuitePlan) error {
+	contents, err := json.MarshalIndent(plans, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, PlanFileName), contents, 0644)
+}
@@ -0,0 +1,155 @@
+package cats_suite_helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/run_metadata"
+	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+)
+
+// RunID identifies the run in the labels of the resources it creates. It is generated by the first
+// parallel process and shared with the others.
+var RunID string
+
+// RunLabels returns the metadata labels for resources created by the current spec, or by the suite
+// setup outside of specs.
+func RunLabels() map[string]string {
+	suite := ""
+	if hierarchy := CurrentSpecReport().ContainerHierarchyTexts; len(hierarchy) > 0 {
+		suite = suiteLabel(hierarchy[0])
+	}
+	return run_metadata.LabelsFor(RunID, suite)
+}
+
+// RunMetadataJSON returns RunLabels as the "metadata" of a v3 request body.
+func RunMetadataJSON() string {
+	metadata, err := json.Marshal(map[string]interface{}{"labels": RunLabels()})
+	Expect(err).NotTo(HaveOccurred())
+	return string(metadata)
+}
+
+// LabelResource adds RunLabels to the v3 resource at path, e.g. "/v3/apps/<guid>", as the current
+// cf user.
+func LabelResource(path string) {
+	if RunID == "" {
+		return
+	}
+	Expect(cf.Cf("curl", path, "--fail", "-X", "PATCH", "-d", fmt.Sprintf(`{"metadata": %s}`, RunMetadataJSON())).Wait()).To(Exit(0))
+}
+
+// LabelResourcesNamed adds RunLabels to the resources with the given name in a v3 collection, e.g.
+// the app named name in "apps".
+func LabelResourcesNamed(collection, name string) {
+	if RunID == "" {
+		return
+	}
+
+	guids, err := listGuids(fmt.Sprintf("/v3/%s?names=%s", collection, url.QueryEscape(name)))
+	Expect(err).NotTo(HaveOccurred())
+	Expect(guids).NotTo(BeEmpty(), "no %s named %s", collection, name)
+
+	for _, guid := range guids {
+		LabelResource(fmt.Sprintf("/v3/%s/%s", collection, guid))
+	}
+}
+
+// testSpaceGuids caches the GUIDs of the test spaces labelPushedRoutes looks in, by name.
+var testSpaceGuids = map[string]string{}
+
+// labelPushedRoutes adds RunLabels to the routes cf push created in the space for the apps of a
+// spec, which the push helpers cannot label. Only routes created since the run started are labeled,
+// as the space may be an existing one. It is best-effort: failures are logged rather than failing
+// the spec.
+func labelPushedRoutes(spaceName string) {
+	err := tryLabelPushedRoutes(spaceName)
+	if err != nil {
+		fmt.Fprintf(GinkgoWriter, "Could not label the routes created in space %s: %s\n", spaceName, err)
+	}
+}
+
+func tryLabelPushedRoutes(spaceName string) error {
+	startedAt, ok := run_metadata.StartedAt(RunID)
+	if !ok {
+		return nil
+	}
+
+	spaceGuid, ok := testSpaceGuids[spaceName]
+	if !ok {
+		guids, err := listGuids("/v3/spaces?names=" + url.QueryEscape(spaceName))
+		if err != nil {
+			return err
+		}
+		if len(guids) != 1 {
+			return fmt.Errorf("found %d spaces named %s", len(guids), spaceName)
+		}
+		spaceGuid = guids[0]
+		testSpaceGuids[spaceName] = spaceGuid
+	}
+
+	query := url.Values{
+		"space_guids":     {spaceGuid},
+		"label_selector":  {"!" + run_metadata.RunIDLabel},
+		"created_ats[gt]": {startedAt.Format(time.RFC3339)},
+		"per_page":        {"5000"},
+	}
+	guids, err := listGuids("/v3/routes?" + query.Encode())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, guid := range guids {
+		_, err := curl("/v3/routes/"+guid, "-X", "PATCH", "-d", fmt.Sprintf(`{"metadata": %s}`, RunMetadataJSON()))
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// listGuids returns the GUIDs of the resources in the first page of a v3 list.
+func listGuids(path string) ([]string, error) {
+	contents, err := curl(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Resources []struct {
+			GUID string `json:"guid"`
+		} `json:"resources"`
+	}
+	err = json.Unmarshal(contents, &list)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the response to %s: %w", path, err)
+	}
+
+	var guids []string
+	for _, resource := range list.Resources {
+		guids = append(guids, resource.GUID)
+	}
+	return guids, nil
+}
+
+// curl runs cf curl as the current cf user and returns the response. Unlike cf.Cf(...).Wait(), it
+// returns an error, rather than failing the spec, when the command fails or times out.
+func curl(path string, args ...string) ([]byte, error) {
+	session := cf.Cf(append([]string{"curl", path, "--fail"}, args...)...)
+	select {
+	case <-session.Exited:
+	case <-time.After(Config.DefaultTimeoutDuration()):
+		session.Kill()
+		return nil, fmt.Errorf("cf curl %s timed out", path)
+	}
+
+	if session.ExitCode() != 0 {
+		return nil, fmt.Errorf("cf curl %s exited with status %d: %s", path, session.ExitCode(), session.Out.Contents())
+	}
+	return session.Out.Contents(), nil
+}
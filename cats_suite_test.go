@@ -1,10 +1,14 @@
 package cats_test
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -23,6 +27,10 @@ import (
 	_ "github.com/cloudfoundry/cf-acceptance-tests/internet_dependent"
 	_ "github.com/cloudfoundry/cf-acceptance-tests/ipv6"
 	_ "github.com/cloudfoundry/cf-acceptance-tests/isolation_segments"
+	_ "github.com/cloudfoundry/cf-acceptance-tests/metadata"
+	_ "github.com/cloudfoundry/cf-acceptance-tests/quotas"
+	_ "github.com/cloudfoundry/cf-acceptance-tests/revisions"
+	_ "github.com/cloudfoundry/cf-acceptance-tests/role_matrix"
 	_ "github.com/cloudfoundry/cf-acceptance-tests/route_services"
 	_ "github.com/cloudfoundry/cf-acceptance-tests/routing"
 	_ "github.com/cloudfoundry/cf-acceptance-tests/routing_isolation_segments"
@@ -30,6 +38,7 @@ import (
 	_ "github.com/cloudfoundry/cf-acceptance-tests/service_credential_binding_rotation"
 	_ "github.com/cloudfoundry/cf-acceptance-tests/service_discovery"
 	_ "github.com/cloudfoundry/cf-acceptance-tests/services"
+	_ "github.com/cloudfoundry/cf-acceptance-tests/sidecars"
 	_ "github.com/cloudfoundry/cf-acceptance-tests/ssh"
 	_ "github.com/cloudfoundry/cf-acceptance-tests/tasks"
 	_ "github.com/cloudfoundry/cf-acceptance-tests/tcp_routing"
@@ -38,8 +47,15 @@ import (
 	_ "github.com/cloudfoundry/cf-acceptance-tests/volume_services"
 	_ "github.com/cloudfoundry/cf-acceptance-tests/windows"
 
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/capabilities"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cleanup"
 	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/cli_version_check"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/diagnostics"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/reporters"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/run_metadata"
 	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
 	"github.com/cloudfoundry/cf-test-helpers/v2/helpers"
 	"github.com/cloudfoundry/cf-test-helpers/v2/workflowhelpers"
@@ -50,27 +66,71 @@ import (
 
 const minCliVersion = "8.5.0"
 
+var honeyCombReporter *reporters.HoneyCombReporter
+
+// suiteStartTime bounds the search for leaked resources to the ones created by this run.
+var suiteStartTime time.Time
+
+// suitePayload is shared by the first parallel process with all the others after the suite setup.
+type suitePayload struct {
+	CLIVersion string                 `json:"cli_version"`
+	RunID      string                 `json:"run_id"`
+	AutoConfig map[string]interface{} `json:"auto_config,omitempty"`
+}
+
 func TestCATS(t *testing.T) {
+	suiteStartTime = time.Now()
+
 	var validationError error
 	Config, validationError = config.NewCatsConfig(os.Getenv("CONFIG"))
+	validationError = errors.Join(validationError, config.ValidateTimeoutOverrideSuites(Config, Suites()))
 	if validationError != nil {
 		fmt.Println("Invalid configuration.  ")
 		fmt.Println(validationError)
 		fmt.Println("Please fix the contents of $CONFIG:\n  " + os.Getenv("CONFIG") + "\nbefore proceeding.")
 		t.FailNow()
 	}
+	random_name.SetPrefix(Config.GetNamePrefix())
+
+	sc, rc := GinkgoConfiguration()
+	if Config.GetDryRun() {
+		// Disabled groups are kept in the plan, so that it can tell why they are skipped.
+		sc.DryRun = true
+	} else {
+		sc.LabelFilter = joinLabelFilters(sc.LabelFilter, LabelFilter(Config))
+	}
 
-	_, rc := GinkgoConfiguration()
 	if Config.GetArtifactsDirectory() != "" {
 		helpers.EnableCFTrace(Config, "CATS")
 		rc.JUnitReport = filepath.Join(Config.GetArtifactsDirectory(), fmt.Sprintf("junit-%s-%d.xml", "CATS", GinkgoParallelProcess()))
 	}
 
+	reporterConfig := Config.GetReporterConfig()
+	if !Config.GetDryRun() && reporterConfig.HoneyCombWriteKey != "" && reporterConfig.HoneyCombDataset != "" {
+		honeyCombReporter = reporters.NewHoneyCombReporter(
+			reporterConfig.HoneyCombAPIHost,
+			reporterConfig.HoneyCombWriteKey,
+			reporterConfig.HoneyCombDataset,
+			reporterConfig.CustomTags,
+		)
+	}
+
 	RegisterFailHandler(Fail)
-	RunSpecs(t, "CATS", rc)
+	RunSpecs(t, "CATS", sc, rc)
+}
+
+// joinLabelFilters combines the --label-filter given on the command line with the one derived from
+// the config, so that groups disabled in the config are not scheduled at all.
+func joinLabelFilters(given, derived string) string {
+	if given == "" || derived == "" {
+		return given + derived
+	}
+	return fmt.Sprintf("(%s) && %s", given, derived)
 }
 
 var _ = SynchronizedBeforeSuite(func() []byte {
+	DeferCleanup(redactTraceLog)
+
 	installedVersion, err := GetInstalledCliVersionString()
 
 	Expect(err).ToNot(HaveOccurred(), "Error trying to determine CF CLI version")
@@ -81,6 +141,13 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 
 	Expect(ParseRawCliVersionString(installedVersion).AtLeast(ParseRawCliVersionString(minCliVersion))).To(BeTrue(), "CLI version "+minCliVersion+" is required")
 
+	payload := suitePayload{CLIVersion: installedVersion, RunID: run_metadata.NewRunID()}
+	fmt.Fprintf(GinkgoWriter, "Resources created by this run are labeled %s\n", run_metadata.Selector(payload.RunID))
+	if len(Config.GetAutoKeys()) > 0 {
+		payload.AutoConfig = resolveAutoConfig()
+		Expect(Config.ResolveAuto(payload.AutoConfig)).To(Succeed())
+	}
+
 	if Config.GetIncludeSsh() {
 		ScpPath, err = exec.LookPath("scp")
 		Expect(err).NotTo(HaveOccurred())
@@ -128,12 +195,31 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	err = zip.Archive(doraFileNames, assets.NewAssets().DoraZip)
 	Expect(err).NotTo(HaveOccurred())
 
-	return []byte{}
-}, func([]byte) {
+	encodedPayload, err := json.Marshal(payload)
+	Expect(err).NotTo(HaveOccurred())
+	return encodedPayload
+}, func(encodedPayload []byte) {
+	// Cleanup registered here runs after the suite, including when it fails or is interrupted.
+	DeferCleanup(redactTraceLog)
+
+	var payload suitePayload
+	Expect(json.Unmarshal(encodedPayload, &payload)).To(Succeed())
+
+	if payload.AutoConfig != nil {
+		Expect(Config.ResolveAuto(payload.AutoConfig)).To(Succeed())
+	}
+
+	RunID = payload.RunID
+
+	if honeyCombReporter != nil {
+		honeyCombReporter.SetGlobalTag("cf_cli_version", payload.CLIVersion)
+		honeyCombReporter.SetGlobalTag("run_id", payload.RunID)
+	}
+
 	SetDefaultEventuallyTimeout(Config.DefaultTimeoutDuration())
 	SetDefaultEventuallyPollingInterval(1 * time.Second)
 
-	TestSetup = workflowhelpers.NewTestSuiteSetup(Config)
+	TestSetup = NewSuiteSetup(Config)
 
 	workflowhelpers.AsUser(TestSetup.AdminUserContext(), Config.GetScaledTimeout(1*time.Minute), func() {
 		buildpacksSession := cf.Cf("buildpacks").Wait()
@@ -149,6 +235,134 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	})
 
 	TestSetup.Setup()
+	labelTestSpace()
+})
+
+// redactTraceLog redacts the configured secrets from the CF_TRACE log of this process, whenever
+// CF_TRACE is a path that the cf CLI writes to rather than true or false.
+func redactTraceLog() {
+	trace := os.Getenv("CF_TRACE")
+	if trace == "" || strings.EqualFold(trace, "true") || strings.EqualFold(trace, "false") {
+		return
+	}
+
+	err := config.RedactSecretsInFile(trace, Config.GetSecrets())
+	if err != nil {
+		fmt.Fprintf(GinkgoWriter, "Failed to redact secrets from the CF trace log: %s\n", err)
+	}
+}
+
+// labelTestSpace labels the org and space the specs of this process run in, so that the resources
+// in them can be traced back to the run as well.
+func labelTestSpace() {
+	workflowhelpers.AsUser(TestSetup.AdminUserContext(), Config.GetScaledTimeout(1*time.Minute), func() {
+		if !Config.GetUseExistingOrganization() {
+			LabelResourcesNamed("organizations", TestSetup.GetOrganizationName())
+		}
+		if !Config.GetUseExistingSpace() {
+			LabelResourcesNamed("spaces", TestSetup.TestSpace.SpaceName())
+		}
+	})
+}
+
+// resolveAutoConfig probes the platform as the admin user to decide the config values set to "auto".
+func resolveAutoConfig() map[string]interface{} {
+	platform := capabilities.Probe(adminClient())
+	decisions := capabilities.Resolve(Config, platform)
+
+	PauseOutputInterception()
+	err := capabilities.WriteReport(GinkgoWriter, platform, decisions)
+	ResumeOutputInterception()
+	Expect(err).NotTo(HaveOccurred())
+
+	if Config.GetArtifactsDirectory() != "" {
+		Expect(capabilities.SaveReport(Config.GetArtifactsDirectory(), platform, decisions)).To(Succeed())
+	}
+
+	return capabilities.Values(decisions)
+}
+
+// adminClient returns a Cloud Controller client authenticated as the admin user.
+func adminClient() *cc_client.Client {
+	var token string
+	adminSetup := workflowhelpers.NewTestSuiteSetup(Config)
+	workflowhelpers.AsUser(adminSetup.AdminUserContext(), Config.GetScaledTimeout(1*time.Minute), func() {
+		session := cf.Cf("oauth-token").Wait()
+		Expect(session).To(Exit(0))
+		token = string(session.Out.Contents())
+	})
+
+	return cc_client.New(Config.Protocol()+Config.GetApiEndpoint(), token, Config.GetSkipSSLValidation())
+}
+
+// checkForLeakedResources fails the suite if resources created during this run outlived their specs
+// and the suite teardown. They are found by their run labels, and by their names and creation times,
+// for the resources that have no labels, such as security groups and quotas, or were created
+// without them.
+func checkForLeakedResources() {
+	filters := []cleanup.Filter{{
+		Names:        cleanup.NamePattern("CATS", Config.GetNamePrefix()),
+		CreatedAfter: suiteStartTime,
+	}}
+	if RunID != "" {
+		filters = append(filters, cleanup.Filter{LabelSelector: run_metadata.Selector(RunID)})
+	}
+
+	leaked, err := cleanup.Find(adminClient(), filters...)
+	Expect(err).NotTo(HaveOccurred())
+
+	if Config.GetShouldKeepUser() {
+		leaked = slices.DeleteFunc(leaked, func(r cleanup.Resource) bool {
+			return r.Kind == "user"
+		})
+	}
+
+	var descriptions []string
+	for _, r := range leaked {
+		descriptions = append(descriptions, fmt.Sprintf("%s %s (%s)", r.Kind, r.Name, r.GUID))
+	}
+	Expect(descriptions).To(BeEmpty(), "This run leaked resources, which bin/cats-cleanup can delete")
+}
+
+// Diagnostics are collected in a JustAfterEach rather than a ReportAfterEach, because the AfterEach
+// of most specs deletes their apps.
+var _ = JustAfterEach(func() {
+	report := CurrentSpecReport()
+	if !report.Failed() || Config.GetArtifactsDirectory() == "" || TestSetup == nil {
+		return
+	}
+
+	dir := diagnostics.SpecDir(Config.GetArtifactsDirectory(), report)
+	err := diagnostics.Collect(dir, TestSetup.TestSpace.SpaceName(), Config)
+	if err != nil {
+		fmt.Fprintf(GinkgoWriter, "Failed to collect some diagnostics into %s: %s\n", dir, err)
+	}
+	AddReportEntry("diagnostics", dir)
+})
+
+var _ = ReportAfterEach(func(report SpecReport) {
+	if honeyCombReporter == nil {
+		return
+	}
+
+	err := honeyCombReporter.ReportSpec(report)
+	if err != nil {
+		fmt.Fprintf(GinkgoWriter, "Failed to send spec event to Honeycomb: %s\n", err)
+	}
+})
+
+var _ = ReportAfterSuite("dry_run plan", func(report Report) {
+	if !Config.GetDryRun() {
+		return
+	}
+
+	plans := NewPlan(Config, report)
+	fmt.Println("Plan for the configured test groups (dry_run is enabled, no specs were run):")
+	Expect(WritePlan(os.Stdout, plans)).To(Succeed())
+
+	if Config.GetArtifactsDirectory() != "" {
+		Expect(SavePlan(Config.GetArtifactsDirectory(), plans)).To(Succeed())
+	}
 })
 
 var _ = SynchronizedAfterSuite(func() {
@@ -157,4 +371,8 @@ var _ = SynchronizedAfterSuite(func() {
 	}
 }, func() {
 	os.Remove(assets.NewAssets().DoraZip)
+
+	if Config.GetFailOnLeakedResources() {
+		checkForLeakedResources()
+	}
 })
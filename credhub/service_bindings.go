@@ -79,7 +79,7 @@ var _ = CredhubDescribe("service bindings", func() {
 
 		workflowhelpers.AsUser(TestSetup.AdminUserContext(), Config.DefaultTimeoutDuration(), func() {
 			serviceUrl := "https://" + chBrokerAppName + "." + Config.GetAppsDomain()
-			createServiceBroker := cf.Cf("create-service-broker", chBrokerAppName, Config.GetAdminUser(), Config.GetAdminPassword(), serviceUrl).Wait()
+			createServiceBroker := cf.Cf("create-service-broker", chBrokerAppName, TestSetup.AdminUserContext().Username, TestSetup.AdminUserContext().Password, serviceUrl).Wait()
 			Expect(createServiceBroker).To(Exit(0), "failed creating credhub-enabled service broker")
 
 			enableAccess := cf.Cf("enable-service-access", chServiceName, "-o", TestSetup.RegularUserContext().Org).Wait()
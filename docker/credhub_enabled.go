@@ -13,7 +13,6 @@ import (
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
-	"github.com/cloudfoundry/cf-acceptance-tests/helpers/skip_messages"
 	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
 	"github.com/cloudfoundry/cf-test-helpers/v2/helpers"
 	"github.com/cloudfoundry/cf-test-helpers/v2/workflowhelpers"
@@ -65,7 +64,7 @@ var _ = DockerDescribe("Docker App Lifecycle CredHub Integration", func() {
 
 			workflowhelpers.AsUser(TestSetup.AdminUserContext(), Config.DefaultTimeoutDuration(), func() {
 				serviceUrl := "https://" + chBrokerName + "." + Config.GetAppsDomain()
-				createServiceBroker := cf.Cf("create-service-broker", chBrokerName, Config.GetAdminUser(), Config.GetAdminPassword(), serviceUrl).Wait()
+				createServiceBroker := cf.Cf("create-service-broker", chBrokerName, TestSetup.AdminUserContext().Username, TestSetup.AdminUserContext().Password, serviceUrl).Wait()
 				Expect(createServiceBroker).To(Exit(0), "failed creating credhub-enabled service broker")
 
 				enableAccess := cf.Cf("enable-service-access", chServiceName, "-o", TestSetup.RegularUserContext().Org).Wait()
@@ -125,12 +124,8 @@ var _ = DockerDescribe("Docker App Lifecycle CredHub Integration", func() {
 				})
 			})
 
-			Context("in assisted mode", func() {
+			Context("in assisted mode", RequiresAssistedCredhub, func() {
 				BeforeEach(func() {
-					if !Config.GetIncludeCredhubAssisted() {
-						Skip(skip_messages.SkipAssistedCredhubMessage)
-					}
-
 					dockerImage = Config.GetPublicDockerAppImage()
 				})
 
@@ -142,12 +137,8 @@ var _ = DockerDescribe("Docker App Lifecycle CredHub Integration", func() {
 				})
 			})
 
-			Context("in non-assisted mode", func() {
+			Context("in non-assisted mode", RequiresNonAssistedCredhub, func() {
 				BeforeEach(func() {
-					if !Config.GetIncludeCredhubNonAssisted() {
-						Skip(skip_messages.SkipNonAssistedCredhubMessage)
-					}
-
 					// TODO: use the credhub enabled app docker image and interpolate the vcap_services manually
 					dockerImage = Config.GetPublicDockerAppImage()
 				})
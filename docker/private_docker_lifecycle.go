@@ -10,7 +10,6 @@ import (
 	. "github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
-	"github.com/cloudfoundry/cf-acceptance-tests/helpers/skip_messages"
 	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
 	"github.com/cloudfoundry/cf-test-helpers/v2/helpers"
 	. "github.com/onsi/ginkgo/v2"
@@ -26,12 +25,6 @@ var _ = DockerDescribe("Private Docker Registry Application Lifecycle", func() {
 		repository string
 	)
 
-	BeforeEach(func() {
-		if !Config.GetIncludePrivateDockerRegistry() {
-			Skip(skip_messages.SkipPrivateDockerRegistryMessage)
-		}
-	})
-
 	JustBeforeEach(func() {
 		spaceName := TestSetup.RegularUserContext().Space
 		session := cf.Cf("space", spaceName, "--guid")
@@ -84,4 +77,4 @@ var _ = DockerDescribe("Private Docker Registry Application Lifecycle", func() {
 			}).Should(Equal("SUCCEEDED"))
 		})
 	})
-})
+}, RequiresPrivateDockerRegistry)
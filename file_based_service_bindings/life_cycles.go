@@ -6,12 +6,11 @@ import (
 	. "github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/manifest"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/services"
 	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gexec"
-	"os"
-	"path/filepath"
 )
 
 type LifeCycle interface {
@@ -108,22 +107,11 @@ func CreateUpsi(serviceName string) string {
 }
 
 func CreateManifest(appName, serviceName, appFeatureFlag string) string {
-	tmpdir, err := os.MkdirTemp(os.TempDir(), appName)
-	Expect(err).ToNot(HaveOccurred())
-
-	manifestFile := filepath.Join(tmpdir, "manifest.yml")
-	manifestContent := fmt.Sprintf(`---
-applications:
-- name: %s
-  features:
-    %s: true
-  services:
-    - %s
-`, appName, appFeatureFlag, serviceName)
-	err = os.WriteFile(manifestFile, []byte(manifestContent), 0644)
-	Expect(err).ToNot(HaveOccurred())
-
-	return manifestFile
+	return app_helpers.WriteManifest(manifest.Manifest{Applications: []manifest.Application{{
+		Name:     appName,
+		Features: map[string]bool{appFeatureFlag: true},
+		Services: []manifest.Service{{Name: serviceName}},
+	}}})
 }
 
 func BindUpsi(appName, serviceName string) {
@@ -2,10 +2,13 @@ package app_helpers
 
 import (
 	"fmt"
+	"os"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/manifest"
 
 	. "github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/logs"
@@ -23,7 +26,7 @@ func CatnipWithArgs(appName string, args ...string) []string {
 		"-p", assets.NewAssets().Catnip,
 		"-c", "./catnip",
 	}
-	pushArgs = append(pushArgs, args...)
+	pushArgs = append(pushArgs, withRunLabels(appName, args)...)
 	return pushArgs
 }
 
@@ -35,7 +38,7 @@ func WindowsCatnipWithArgs(appName string, args ...string) []string {
 		"-p", assets.NewAssets().Catnip,
 		"-c", "./catnip.exe",
 	}
-	pushArgs = append(pushArgs, args...)
+	pushArgs = append(pushArgs, withRunLabels(appName, args)...)
 	return pushArgs
 }
 
@@ -46,7 +49,7 @@ func BinaryWithArgs(appName string, args ...string) []string {
 		"-p", assets.NewAssets().Binary,
 		"-c", "./app",
 	}
-	pushArgs = append(pushArgs, args...)
+	pushArgs = append(pushArgs, withRunLabels(appName, args)...)
 	return pushArgs
 }
 
@@ -56,7 +59,7 @@ func GRPCWithArgs(appName string, args ...string) []string {
 		"-b", Config.GetGoBuildpackName(),
 		"-p", assets.NewAssets().GRPC,
 	}
-	pushArgs = append(pushArgs, args...)
+	pushArgs = append(pushArgs, withRunLabels(appName, args)...)
 	return pushArgs
 }
 
@@ -66,7 +69,7 @@ func HelloWorldWithArgs(appName string, args ...string) []string {
 		"-b", Config.GetRubyBuildpackName(),
 		"-p", assets.NewAssets().HelloWorld,
 	}
-	pushArgs = append(pushArgs, args...)
+	pushArgs = append(pushArgs, withRunLabels(appName, args)...)
 	return pushArgs
 }
 
@@ -76,10 +79,76 @@ func HTTP2WithArgs(appName string, args ...string) []string {
 		"-b", Config.GetGoBuildpackName(),
 		"-p", assets.NewAssets().HTTP2,
 	}
+	pushArgs = append(pushArgs, withRunLabels(appName, args)...)
+	return pushArgs
+}
+
+// ProxyWithArgs pushes the proxy app with the environment the go buildpack needs to build it.
+func ProxyWithArgs(appName string, args ...string) []string {
+	proxy := manifest.Application{
+		Name:      appName,
+		Memory:    "32M",
+		DiskQuota: "32M",
+		Env:       map[string]string{"GOPACKAGENAME": "example-apps/proxy", "GOVERSION": "latest"},
+	}
+	pushArgs := []string{
+		"push", appName,
+		"-b", Config.GetGoBuildpackName(),
+		"-p", assets.NewAssets().Proxy,
+		"-f", WriteManifest(manifest.Manifest{Applications: []manifest.Application{proxy}}),
+	}
 	pushArgs = append(pushArgs, args...)
 	return pushArgs
 }
 
+// WriteManifest writes m to a temporary file for cf push -f, which is removed when the spec ends.
+// The apps in it are labelled with RunLabels.
+func WriteManifest(m manifest.Manifest) string {
+	if RunID != "" {
+		m = m.WithLabels(RunLabels())
+	}
+
+	manifestFile, err := m.WriteTempFile()
+	Expect(err).NotTo(HaveOccurred())
+	ginkgo.DeferCleanup(os.Remove, manifestFile)
+	return manifestFile
+}
+
+// withRunLabels returns the arguments of cf push with the app labelled with RunLabels when it is
+// created: in a copy of the manifest given with -f, or through a manifest that sets nothing else.
+// The copy is in the temporary directory, so paths in it must be absolute or given with -p.
+func withRunLabels(appName string, args []string) []string {
+	if RunID == "" {
+		return args
+	}
+
+	for i, arg := range args {
+		if (arg == "-f" || arg == "--manifest") && i+1 < len(args) {
+			labelled := slices.Clone(args)
+			labelled[i+1] = writeLabelledManifest(args[i+1])
+			return labelled
+		}
+	}
+	return append([]string{"-f", WriteManifest(manifest.Manifest{Applications: []manifest.Application{{Name: appName}}})}, args...)
+}
+
+// writeLabelledManifest writes a copy of the manifest file with its apps labelled with RunLabels to
+// a temporary file, which is removed when the spec ends.
+func writeLabelledManifest(path string) string {
+	contents, err := os.ReadFile(path)
+	Expect(err).NotTo(HaveOccurred())
+	contents, err = manifest.LabelYAML(contents, RunLabels())
+	Expect(err).NotTo(HaveOccurred())
+
+	file, err := os.CreateTemp("", "manifest-*.yml")
+	Expect(err).NotTo(HaveOccurred())
+	defer file.Close()
+	ginkgo.DeferCleanup(os.Remove, file.Name())
+	_, err = file.Write(contents)
+	Expect(err).NotTo(HaveOccurred())
+	return file.Name()
+}
+
 func GetAppGuid(appName string) string {
 	cfApp := cf.Cf("app", appName, "--guid")
 	Eventually(cfApp).Should(Exit(0))
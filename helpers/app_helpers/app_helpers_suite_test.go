@@ -0,0 +1,29 @@
+package app_helpers_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeConfig sets the buildpacks the push helpers use. Calling any other method of the config
+// panics.
+type fakeConfig struct {
+	config.CatsConfig
+}
+
+func (c fakeConfig) GetBinaryBuildpackName() string { return "binary_buildpack" }
+func (c fakeConfig) GetGoBuildpackName() string     { return "go_buildpack" }
+
+func TestAppHelpers(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "App Helpers Suite")
+}
+
+var _ = BeforeSuite(func() {
+	cats_suite_helpers.Config = fakeConfig{}
+})
@@ -0,0 +1,78 @@
+package app_helpers_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
+	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("app helpers", func() {
+	It("pushes apps with their buildpack and assets, followed by the given arguments", func() {
+		Expect(CatnipWithArgs("app", "-m", "128M")).To(Equal([]string{
+			"push", "app",
+			"-b", "binary_buildpack",
+			"-p", "assets/catnip/bin",
+			"-c", "./catnip",
+			"-m", "128M",
+		}))
+	})
+
+	Context("when the run has an ID", func() {
+		BeforeEach(func() {
+			DeferCleanup(func(runID string) { RunID = runID }, RunID)
+			RunID = "some-run"
+		})
+
+		It("labels the apps it pushes with the run labels", func() {
+			args := CatnipWithArgs("app", "-m", "128M")
+			Expect(args).To(HaveLen(12))
+			Expect(args[8]).To(Equal("-f"))
+			Expect(args[10:]).To(Equal([]string{"-m", "128M"}), "the given arguments come last, so they override the manifest")
+
+			Expect(os.ReadFile(args[9])).To(MatchYAML(`
+applications:
+- name: app
+  metadata:
+    labels:
+      cats.cloudfoundry.org/run-id: some-run
+      cats.cloudfoundry.org/suite: app_helpers
+`))
+		})
+
+		It("labels the apps in the manifest it is given instead of adding another", func() {
+			given := filepath.Join(GinkgoT().TempDir(), "manifest.yml")
+			Expect(os.WriteFile(given, []byte("applications:\n- name: app\n  sidecars: [{name: sidecar, command: sleep}]\n"), 0600)).To(Succeed())
+
+			args := CatnipWithArgs("app", "-f", given)
+			Expect(args).To(HaveLen(10))
+			Expect(args[8]).To(Equal("-f"))
+			Expect(args[9]).NotTo(Equal(given))
+
+			Expect(os.ReadFile(args[9])).To(MatchYAML(`
+applications:
+- name: app
+  sidecars: [{name: sidecar, command: sleep}]
+  metadata:
+    labels:
+      cats.cloudfoundry.org/run-id: some-run
+      cats.cloudfoundry.org/suite: app_helpers
+`))
+		})
+	})
+
+	It("finds app usage events", func() {
+		var started, stopped AppUsageEvent
+		started.State.Current = "STARTED"
+		started.App.Name = "app"
+		stopped.State.Current = "STOPPED"
+		stopped.App.Name = "app"
+
+		Expect(UsageEventsInclude([]AppUsageEvent{started}, started)).To(BeTrue())
+		Expect(UsageEventsInclude([]AppUsageEvent{started}, stopped)).To(BeFalse())
+	})
+})
@@ -0,0 +1,209 @@
+package capabilities_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/capabilities"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func listResponse(names ...string) string {
+	resources := []map[string]interface{}{}
+	for _, name := range names {
+		resources = append(resources, map[string]interface{}{"name": name})
+	}
+
+	contents, err := json.Marshal(map[string]interface{}{
+		"pagination": map[string]interface{}{"next": nil},
+		"resources":  resources,
+	})
+	Expect(err).NotTo(HaveOccurred())
+	return string(contents)
+}
+
+var _ = Describe("Capabilities", func() {
+	var (
+		server    *httptest.Server
+		responses map[string]string
+		catsCfg   config.CatsConfig
+		rawConfig map[string]interface{}
+	)
+
+	loadConfig := func() config.CatsConfig {
+		contents, err := json.Marshal(rawConfig)
+		Expect(err).NotTo(HaveOccurred())
+
+		path := filepath.Join(GinkgoT().TempDir(), "config.json")
+		Expect(os.WriteFile(path, contents, 0644)).To(Succeed())
+
+		c, err := config.NewCatsConfig(path)
+		Expect(err).NotTo(HaveOccurred())
+		return c
+	}
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			response, ok := responses[r.URL.RequestURI()]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fmt.Fprint(w, response)
+		}))
+
+		responses = map[string]string{
+			"/": fmt.Sprintf(`{"links": {
+				"cloud_controller_v3": {"href": "%[1]s/v3"},
+				"log_cache": {"href": "%[1]s/log-cache"},
+				"routing": {"href": "%[1]s/routing"},
+				"network_policy_v1": {"href": "%[1]s/networking/v1/external"},
+				"app_ssh": null,
+				"credhub": null
+			}}`, server.URL),
+			"/v3/info": `{"version": 3, "build": "some-build"}`,
+			"/v3/feature_flags": `{"pagination": {"next": null}, "resources": [
+				{"name": "diego_docker", "enabled": true},
+				{"name": "diego_cnb", "enabled": false}
+			]}`,
+			"/routing/v1/router_groups":       `[{"name": "default-tcp", "type": "tcp"}]`,
+			"/v3/stacks":                      listResponse("cflinuxfs4", "windows"),
+			"/v3/isolation_segments":          listResponse("shared", "persistent_isolation_segment"),
+			"/v3/service_offerings":           listResponse("nfs"),
+			"/v3/domains?names=apps.internal": listResponse("apps.internal"),
+		}
+
+		rawConfig = map[string]interface{}{
+			"api":                        "api.example.com",
+			"admin_user":                 "admin",
+			"admin_password":             "admin",
+			"skip_ssl_validation":        true,
+			"apps_domain":                "example.com",
+			"use_http":                   false,
+			"skip_dns_validation":        true,
+			"isolation_segment_name":     "persistent_isolation_segment",
+			"volume_service_name":        "nfs",
+			"include_docker":             "auto",
+			"include_cnb":                "auto",
+			"include_ssh":                "auto",
+			"include_apps":               "auto",
+			"include_windows":            "auto",
+			"include_tcp_routing":        "auto",
+			"include_service_discovery":  "auto",
+			"include_isolation_segments": "auto",
+			"include_volume_services":    "auto",
+			"include_zipkin":             "auto",
+			"include_tasks":              true,
+			"credhub_mode":               "auto",
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	resolve := func() (Platform, []Decision) {
+		catsCfg = loadConfig()
+		platform := Probe(cc_client.New(server.URL, "bearer some-token", false))
+		return platform, Resolve(catsCfg, platform)
+	}
+
+	It("leaves 'auto' values disabled until they are resolved", func() {
+		catsCfg = loadConfig()
+		Expect(catsCfg.GetIncludeDocker()).To(BeFalse())
+		Expect(catsCfg.GetIncludeApps()).To(BeFalse())
+		Expect(catsCfg.GetAutoKeys()).To(ContainElements("include_docker", "credhub_mode"))
+		Expect(catsCfg.GetAutoKeys()).NotTo(ContainElement("include_tasks"))
+	})
+
+	It("resolves each 'auto' value from the platform", func() {
+		platform, decisions := resolve()
+		Expect(platform.CCVersion).To(Equal("3 (some-build)"))
+		Expect(platform.Errors).To(BeEmpty())
+
+		Expect(Values(decisions)).To(Equal(map[string]interface{}{
+			"include_docker":             true,
+			"include_cnb":                false,
+			"include_ssh":                false,
+			"include_apps":               true,
+			"include_windows":            true,
+			"include_tcp_routing":        true,
+			"include_service_discovery":  true,
+			"include_isolation_segments": true,
+			"include_volume_services":    true,
+			"include_zipkin":             false,
+			"credhub_mode":               "",
+		}))
+		Expect(decisions).To(ContainElement(Decision{Key: "include_cnb", Value: false, Reason: "feature flag 'diego_cnb' is disabled"}))
+		Expect(decisions).To(ContainElement(Decision{Key: "include_ssh", Value: false, Reason: "the API root does not link to 'app_ssh'"}))
+	})
+
+	It("applies the decisions to the config", func() {
+		_, decisions := resolve()
+
+		Expect(catsCfg.ResolveAuto(Values(decisions))).To(Succeed())
+		Expect(catsCfg.GetIncludeDocker()).To(BeTrue())
+		Expect(catsCfg.GetIncludeCNB()).To(BeFalse())
+		Expect(catsCfg.GetIncludeTasks()).To(BeTrue())
+		Expect(catsCfg.GetAutoKeys()).To(BeEmpty())
+	})
+
+	It("disables capabilities whose probe failed and explains why", func() {
+		delete(responses, "/routing/v1/router_groups")
+		delete(responses, "/v3/stacks")
+
+		platform, decisions := resolve()
+		Expect(platform.Errors).To(HaveKey("RouterGroupTypes"))
+		Expect(decisions).To(ContainElement(And(
+			HaveField("Key", "include_windows"),
+			HaveField("Value", false),
+			HaveField("Reason", ContainSubstring("could not list stacks: GET %s/v3/stacks responded with status 404", server.URL)),
+		)))
+		Expect(Values(decisions)).To(HaveKeyWithValue("include_tcp_routing", false))
+		Expect(Values(decisions)).To(HaveKeyWithValue("include_docker", true))
+	})
+
+	It("uses assisted mode when CredHub is advertised and configured", func() {
+		responses["/"] = fmt.Sprintf(`{"links": {"credhub": {"href": "%s/credhub"}}}`, server.URL)
+		rawConfig["credhub_secret"] = "some-secret"
+
+		_, decisions := resolve()
+		Expect(decisions).To(ContainElement(Decision{
+			Key:    "credhub_mode",
+			Value:  "assisted",
+			Reason: "the API root links to 'credhub', 'credhub_secret' is configured",
+		}))
+	})
+
+	Describe("reports", func() {
+		It("lists every decision", func() {
+			platform, decisions := resolve()
+
+			var out bytes.Buffer
+			Expect(WriteReport(&out, platform, decisions)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("against Cloud Controller 3 (some-build)"))
+			Expect(out.String()).To(MatchRegexp(`include_docker\s+enabled\s+feature flag 'diego_docker' is enabled`))
+			Expect(out.String()).To(MatchRegexp(`include_zipkin\s+disabled\s+cannot be detected`))
+		})
+
+		It("saves the decisions as JSON", func() {
+			platform, decisions := resolve()
+			dir := GinkgoT().TempDir()
+
+			Expect(SaveReport(dir, platform, decisions)).To(Succeed())
+
+			contents, err := os.ReadFile(filepath.Join(dir, ReportFileName))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(ContainSubstring(`"key": "include_windows"`))
+		})
+	})
+})
@@ -0,0 +1,121 @@
+package capabilities
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+)
+
+const serviceDiscoveryDomain = "apps.internal"
+
+// Platform is what the Cloud Controller reports about the foundation under test.
+type Platform struct {
+	CCVersion string
+
+	// Links are the hrefs of the entries in the root endpoint's links, e.g. "log_cache" or "credhub".
+	Links map[string]string
+
+	FeatureFlags      map[string]bool
+	RouterGroupTypes  []string
+	Stacks            []string
+	IsolationSegments []string
+	ServiceOfferings  []string
+	Domains           []string
+
+	// Errors holds the probes that failed, keyed by the name of the Platform field they populate.
+	Errors map[string]error
+}
+
+type link struct {
+	Href string `json:"href"`
+}
+
+type named struct {
+	Name string `json:"name"`
+}
+
+// Probe queries the Cloud Controller, and the routing API if it is advertised, for the features
+// of the platform. Probes that fail are recorded in Platform.Errors rather than returned, so that
+// capabilities that don't depend on them can still be resolved.
+func Probe(client *cc_client.Client) Platform {
+	platform := Platform{
+		Links:        map[string]string{},
+		FeatureFlags: map[string]bool{},
+		Errors:       map[string]error{},
+	}
+
+	var root struct {
+		Links map[string]*link `json:"links"`
+	}
+	err := client.Get("/", &root)
+	if err != nil {
+		platform.Errors["Links"] = err
+	}
+	for name, l := range root.Links {
+		if l != nil && l.Href != "" {
+			platform.Links[name] = l.Href
+		}
+	}
+
+	var info struct {
+		Version interface{} `json:"version"`
+		Build   string      `json:"build"`
+	}
+	err = client.Get("/v3/info", &info)
+	if err != nil {
+		platform.Errors["CCVersion"] = err
+	} else {
+		platform.CCVersion = fmt.Sprint(info.Version)
+		if info.Build != "" {
+			platform.CCVersion += " (" + info.Build + ")"
+		}
+	}
+
+	featureFlags, err := cc_client.GetAll[struct {
+		Name    string `json:"name"`
+		Enabled bool   `json:"enabled"`
+	}](client, "/v3/feature_flags")
+	if err != nil {
+		platform.Errors["FeatureFlags"] = err
+	}
+	for _, flag := range featureFlags {
+		platform.FeatureFlags[flag.Name] = flag.Enabled
+	}
+
+	if routingURL, ok := platform.Links["routing"]; ok {
+		var routerGroups []struct {
+			Type string `json:"type"`
+		}
+		err = client.Get(routingURL+"/v1/router_groups", &routerGroups)
+		if err != nil {
+			platform.Errors["RouterGroupTypes"] = err
+		}
+		for _, group := range routerGroups {
+			platform.RouterGroupTypes = append(platform.RouterGroupTypes, group.Type)
+		}
+	} else if platform.Errors["Links"] == nil {
+		platform.Errors["RouterGroupTypes"] = fmt.Errorf("the routing API is not advertised by the Cloud Controller")
+	}
+
+	platform.Stacks = probeNames(client, "/v3/stacks", "Stacks", platform.Errors)
+	platform.IsolationSegments = probeNames(client, "/v3/isolation_segments", "IsolationSegments", platform.Errors)
+	platform.ServiceOfferings = probeNames(client, "/v3/service_offerings", "ServiceOfferings", platform.Errors)
+	platform.Domains = probeNames(client, "/v3/domains?names="+url.QueryEscape(serviceDiscoveryDomain), "Domains", platform.Errors)
+
+	return platform
+}
+
+func probeNames(client *cc_client.Client, path, field string, errs map[string]error) []string {
+	resources, err := cc_client.GetAll[named](client, path)
+	if err != nil {
+		errs[field] = err
+		return nil
+	}
+
+	names := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		names = append(names, resource.Name)
+	}
+	return names
+}
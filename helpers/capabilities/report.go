@@ -0,0 +1,62 @@
+package capabilities
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+)
+
+const ReportFileName = "capabilities.json"
+
+type report struct {
+	CCVersion   string            `json:"cc_version"`
+	Decisions   []Decision        `json:"decisions"`
+	ProbeErrors map[string]string `json:"probe_errors,omitempty"`
+}
+
+// WriteReport prints which "auto" config keys were enabled or disabled, and why.
+func WriteReport(w io.Writer, p Platform, decisions []Decision) error {
+	fmt.Fprintf(w, "Resolved 'auto' config values against Cloud Controller %s:\n", p.CCVersion)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, decision := range decisions {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", decision.Key, describeValue(decision.Value), decision.Reason)
+	}
+	return tw.Flush()
+}
+
+// SaveReport writes the report as JSON to ReportFileName in dir.
+func SaveReport(dir string, p Platform, decisions []Decision) error {
+	r := report{CCVersion: p.CCVersion, Decisions: decisions}
+	if len(p.Errors) > 0 {
+		r.ProbeErrors = map[string]string{}
+		for field, err := range p.Errors {
+			r.ProbeErrors[field] = err.Error()
+		}
+	}
+
+	contents, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ReportFileName), contents, 0644)
+}
+
+func describeValue(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return "enabled"
+		}
+		return "disabled"
+	case string:
+		if v == "" {
+			return `""`
+		}
+		return v
+	}
+	return fmt.Sprint(value)
+}
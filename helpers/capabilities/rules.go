@@ -0,0 +1,234 @@
+package capabilities
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
+)
+
+// Decision is the value an "auto" config key was resolved to, and why.
+type Decision struct {
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Reason string      `json:"reason"`
+}
+
+type rule func(p Platform, c config.CatsConfig) (bool, string)
+
+var coreFeature = always(true, "available on every supported Cloud Controller")
+
+var includeRules = map[string]rule{
+	"include_app_syslog_tcp":                      coreFeature,
+	"include_detect":                              coreFeature,
+	"include_deployments":                         coreFeature,
+	"include_file_based_service_bindings":         coreFeature,
+	"include_metadata":                            coreFeature,
+	"include_quotas":                              coreFeature,
+	"include_revisions":                           coreFeature,
+	"include_role_matrix":                         coreFeature,
+	"include_route_services":                      coreFeature,
+	"include_routing":                             coreFeature,
+	"include_security_groups":                     coreFeature,
+	"include_service_credential_binding_rotation": coreFeature,
+	"include_services":                            coreFeature,
+	"include_sidecars":                            coreFeature,
+	"include_tasks":                               coreFeature,
+	"include_user_provided_services":              coreFeature,
+	"include_v3":                                  coreFeature,
+
+	"include_apps":                     requireLink("log_cache"),
+	"include_ssh":                      requireLink("app_ssh"),
+	"include_container_networking":     requireLink("network_policy_v1"),
+	"include_service_discovery":        all(requireLink("network_policy_v1"), requireDomain(serviceDiscoveryDomain)),
+	"include_docker":                   requireFeatureFlag("diego_docker"),
+	"include_cnb":                      requireFeatureFlag("diego_cnb"),
+	"include_service_instance_sharing": requireFeatureFlag("service_instance_sharing"),
+	"include_private_docker_registry": all(
+		requireFeatureFlag("diego_docker"),
+		requireConfigured("private_docker_registry_image", config.CatsConfig.GetPrivateDockerRegistryImage),
+		requireConfigured("private_docker_registry_username", config.CatsConfig.GetPrivateDockerRegistryUsername),
+		requireConfigured("private_docker_registry_password", config.CatsConfig.GetPrivateDockerRegistryPassword),
+	),
+	"include_tcp_routing":                requireRouterGroup("tcp"),
+	"include_windows":                    requireWindowsStack,
+	"include_isolation_segments":         requireIsolationSegment,
+	"include_routing_isolation_segments": all(requireIsolationSegment, requireConfigured("isolation_segment_domain", config.CatsConfig.GetIsolationSegmentDomain)),
+	"include_tcp_isolation_segments":     all(requireIsolationSegment, requireRouterGroup("tcp")),
+	"include_volume_services":            requireVolumeService,
+
+	"include_http2_routing":      undetectable,
+	"include_internet_dependent": undetectable,
+	"include_ipv6":               undetectable,
+	"include_sso":                undetectable,
+	"include_zipkin":             undetectable,
+}
+
+var undetectable = always(false, "cannot be detected through the API, set it to true or false explicitly")
+
+// Resolve decides the value of every "auto" config key from what the platform supports.
+func Resolve(c config.CatsConfig, p Platform) []Decision {
+	var decisions []Decision
+	for _, key := range c.GetAutoKeys() {
+		if key == "credhub_mode" {
+			decisions = append(decisions, resolveCredhubMode(c, p))
+			continue
+		}
+
+		r, ok := includeRules[key]
+		if !ok {
+			r = undetectable
+		}
+
+		enabled, reason := r(p, c)
+		decisions = append(decisions, Decision{Key: key, Value: enabled, Reason: reason})
+	}
+
+	return decisions
+}
+
+// Values returns the decisions in the form accepted by CatsConfig.ResolveAuto.
+func Values(decisions []Decision) map[string]interface{} {
+	values := map[string]interface{}{}
+	for _, decision := range decisions {
+		values[decision.Key] = decision.Value
+	}
+	return values
+}
+
+func resolveCredhubMode(c config.CatsConfig, p Platform) Decision {
+	decision := Decision{Key: "credhub_mode", Value: ""}
+
+	enabled, reason := all(
+		requireLink("credhub"),
+		requireConfigured("credhub_secret", config.CatsConfig.GetCredHubBrokerClientSecret),
+	)(p, c)
+	if enabled {
+		decision.Value = config.CredhubAssistedMode
+	}
+	decision.Reason = reason
+
+	return decision
+}
+
+func always(enabled bool, reason string) rule {
+	return func(Platform, config.CatsConfig) (bool, string) {
+		return enabled, reason
+	}
+}
+
+func all(rules ...rule) rule {
+	return func(p Platform, c config.CatsConfig) (bool, string) {
+		var reasons []string
+		for _, r := range rules {
+			enabled, reason := r(p, c)
+			if !enabled {
+				return false, reason
+			}
+			reasons = append(reasons, reason)
+		}
+		return true, strings.Join(reasons, ", ")
+	}
+}
+
+func probeFailed(p Platform, field, description string) (bool, string) {
+	return false, fmt.Sprintf("could not list %s: %s", description, p.Errors[field])
+}
+
+func requireLink(name string) rule {
+	return func(p Platform, _ config.CatsConfig) (bool, string) {
+		if p.Errors["Links"] != nil {
+			return probeFailed(p, "Links", "the API root links")
+		}
+		if _, ok := p.Links[name]; !ok {
+			return false, fmt.Sprintf("the API root does not link to '%s'", name)
+		}
+		return true, fmt.Sprintf("the API root links to '%s'", name)
+	}
+}
+
+func requireFeatureFlag(name string) rule {
+	return func(p Platform, _ config.CatsConfig) (bool, string) {
+		if p.Errors["FeatureFlags"] != nil {
+			return probeFailed(p, "FeatureFlags", "feature flags")
+		}
+		if !p.FeatureFlags[name] {
+			return false, fmt.Sprintf("feature flag '%s' is disabled", name)
+		}
+		return true, fmt.Sprintf("feature flag '%s' is enabled", name)
+	}
+}
+
+func requireRouterGroup(routerGroupType string) rule {
+	return func(p Platform, _ config.CatsConfig) (bool, string) {
+		if p.Errors["RouterGroupTypes"] != nil {
+			return probeFailed(p, "RouterGroupTypes", "router groups")
+		}
+		if !slices.Contains(p.RouterGroupTypes, routerGroupType) {
+			return false, fmt.Sprintf("there is no %s router group", routerGroupType)
+		}
+		return true, fmt.Sprintf("there is a %s router group", routerGroupType)
+	}
+}
+
+func requireDomain(name string) rule {
+	return func(p Platform, _ config.CatsConfig) (bool, string) {
+		if p.Errors["Domains"] != nil {
+			return probeFailed(p, "Domains", "domains")
+		}
+		if !slices.Contains(p.Domains, name) {
+			return false, fmt.Sprintf("domain '%s' does not exist", name)
+		}
+		return true, fmt.Sprintf("domain '%s' exists", name)
+	}
+}
+
+func requireConfigured(key string, get func(config.CatsConfig) string) rule {
+	return func(_ Platform, c config.CatsConfig) (bool, string) {
+		if get(c) == "" {
+			return false, fmt.Sprintf("'%s' is not configured", key)
+		}
+		return true, fmt.Sprintf("'%s' is configured", key)
+	}
+}
+
+func requireWindowsStack(p Platform, _ config.CatsConfig) (bool, string) {
+	if p.Errors["Stacks"] != nil {
+		return probeFailed(p, "Stacks", "stacks")
+	}
+	for _, stack := range p.Stacks {
+		if strings.HasPrefix(stack, "windows") {
+			return true, fmt.Sprintf("stack '%s' exists", stack)
+		}
+	}
+	return false, "there is no windows stack"
+}
+
+func requireIsolationSegment(p Platform, c config.CatsConfig) (bool, string) {
+	name := c.GetIsolationSegmentName()
+	if name == "" {
+		return false, "'isolation_segment_name' is not configured"
+	}
+	if p.Errors["IsolationSegments"] != nil {
+		return probeFailed(p, "IsolationSegments", "isolation segments")
+	}
+	if !slices.Contains(p.IsolationSegments, name) {
+		return false, fmt.Sprintf("isolation segment '%s' does not exist", name)
+	}
+	return true, fmt.Sprintf("isolation segment '%s' exists", name)
+}
+
+func requireVolumeService(p Platform, c config.CatsConfig) (bool, string) {
+	name := c.GetVolumeServiceName()
+	if name == "" {
+		return false, "'volume_service_name' is not configured"
+	}
+	if p.Errors["ServiceOfferings"] != nil {
+		return probeFailed(p, "ServiceOfferings", "service offerings")
+	}
+	if !slices.Contains(p.ServiceOfferings, name) {
+		return false, fmt.Sprintf("service offering '%s' does not exist", name)
+	}
+	return true, fmt.Sprintf("service offering '%s' exists", name)
+}
@@ -0,0 +1,213 @@
+package cc_client
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client makes authenticated requests to the Cloud Controller API without going through the cf CLI.
+type Client struct {
+	apiURL     string
+	token      string
+	httpClient *http.Client
+
+	jobTimeout time.Duration
+	jobBackoff Backoff
+}
+
+// New returns a client for the API at apiURL (including the scheme, e.g. "https://api.example.com").
+// token is an Authorization header value such as the output of "cf oauth-token".
+func New(apiURL, token string, skipSSLValidation bool) *Client {
+	return &Client{
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		token:      strings.TrimSpace(token),
+		httpClient: newHTTPClient(skipSSLValidation),
+		jobTimeout: 5 * time.Minute,
+		jobBackoff: DefaultBackoff,
+	}
+}
+
+// WithJobPolling returns a copy of the client that waits up to timeout for asynchronous jobs,
+// polling them with backoff.
+func (c *Client) WithJobPolling(timeout time.Duration, backoff Backoff) *Client {
+	copied := *c
+	copied.jobTimeout, copied.jobBackoff = timeout, backoff
+	return &copied
+}
+
+func newHTTPClient(skipSSLValidation bool) *http.Client {
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: skipSSLValidation},
+		},
+	}
+}
+
+// Get fetches path, which is either relative to the API URL or an absolute URL such as a
+// pagination link, and decodes the JSON response into result.
+func (c *Client) Get(path string, result interface{}) error {
+	return c.request(http.MethodGet, path, nil, result)
+}
+
+// Post sends body as JSON to path and decodes the JSON response into result, unless it is nil. If
+// the Cloud Controller handles the request asynchronously, Post waits for the job to complete.
+func (c *Client) Post(path string, body, result interface{}) error {
+	return c.request(http.MethodPost, path, body, result)
+}
+
+// Patch is like Post for PATCH requests.
+func (c *Client) Patch(path string, body, result interface{}) error {
+	return c.request(http.MethodPatch, path, body, result)
+}
+
+// Delete deletes the resource at path. If the Cloud Controller deletes it asynchronously, Delete
+// waits for the job to complete.
+func (c *Client) Delete(path string) error {
+	return c.request(http.MethodDelete, path, nil, nil)
+}
+
+// YAML is a request body that is sent as it is rather than encoded as JSON, such as a manifest.
+type YAML []byte
+
+// Error is one of the errors in the body of an unsuccessful Cloud Controller response.
+type Error struct {
+	Code   int    `json:"code"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+// ResponseError is returned for unsuccessful responses.
+type ResponseError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Errors     []Error
+	Body       string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%s %s responded with status %d: %s", e.Method, e.URL, e.StatusCode, e.Body)
+}
+
+// HasTitle reports whether the response included an error with title, e.g. "CF-ResourceNotFound".
+func (e *ResponseError) HasTitle(title string) bool {
+	for _, ccError := range e.Errors {
+		if ccError.Title == title {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) request(method, path string, body, result interface{}) error {
+	requestURL := path
+	if strings.HasPrefix(path, "/") {
+		requestURL = c.apiURL + path
+	}
+
+	var requestBody io.Reader
+	contentType := "application/json"
+	if yamlBody, ok := body.(YAML); ok {
+		requestBody = bytes.NewReader(yamlBody)
+		contentType = "application/x-yaml"
+	} else if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		requestBody = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, requestURL, requestBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 300 {
+		responseError := &ResponseError{
+			Method:     method,
+			URL:        requestURL,
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(string(responseBody)),
+		}
+		var errorBody struct {
+			Errors []Error `json:"errors"`
+		}
+		if json.Unmarshal(responseBody, &errorBody) == nil {
+			responseError.Errors = errorBody.Errors
+		}
+		return responseError
+	}
+
+	if resp.StatusCode == http.StatusAccepted && resp.Header.Get("Location") != "" {
+		err = Wait(c.JobOperation(resp.Header.Get("Location")), c.jobTimeout, c.jobBackoff)
+		if err != nil {
+			return err
+		}
+	}
+
+	if result == nil || len(responseBody) == 0 {
+		return nil
+	}
+	err = json.Unmarshal(responseBody, result)
+	if err != nil {
+		return fmt.Errorf("%s %s returned invalid JSON: %w", method, requestURL, err)
+	}
+	return nil
+}
+
+type page[T any] struct {
+	Pagination struct {
+		Next *struct {
+			Href string `json:"href"`
+		} `json:"next"`
+	} `json:"pagination"`
+	Resources []T `json:"resources"`
+}
+
+// GetAll fetches every page of the v3 list endpoint at path and returns all of their resources.
+func GetAll[T any](c *Client, path string) ([]T, error) {
+	var resources []T
+	for path != "" {
+		var p page[T]
+		err := c.Get(path, &p)
+		if err != nil {
+			return nil, err
+		}
+
+		resources = append(resources, p.Resources...)
+
+		path = ""
+		if p.Pagination.Next != nil {
+			path = p.Pagination.Next.Href
+		}
+	}
+
+	return resources, nil
+}
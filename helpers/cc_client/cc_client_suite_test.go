@@ -0,0 +1,13 @@
+package cc_client_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCCClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CC Client Suite")
+}
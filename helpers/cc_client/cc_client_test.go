@@ -0,0 +1,179 @@
+package cc_client_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type stack struct {
+	Name string `json:"name"`
+}
+
+var _ = Describe("Client", func() {
+	var (
+		server *httptest.Server
+		client *Client
+	)
+
+	BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/v3/stacks", func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "bearer some-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"errors": [{"title": "CF-InvalidAuthToken"}]}`)
+				return
+			}
+
+			if r.URL.Query().Get("page") == "2" {
+				fmt.Fprint(w, `{"pagination": {"next": null}, "resources": [{"name": "windows"}]}`)
+				return
+			}
+			fmt.Fprintf(w, `{"pagination": {"next": {"href": "%s/v3/stacks?page=2"}}, "resources": [{"name": "cflinuxfs4"}]}`, server.URL)
+		})
+		server = httptest.NewServer(mux)
+
+		client = New(server.URL+"/", "bearer some-token\n", false)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("follows pagination links", func() {
+		stacks, err := GetAll[stack](client, "/v3/stacks")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stacks).To(Equal([]stack{{Name: "cflinuxfs4"}, {Name: "windows"}}))
+	})
+
+	It("returns an error including the response for unsuccessful requests", func() {
+		client = New(server.URL, "bearer expired-token", false)
+
+		_, err := GetAll[stack](client, "/v3/stacks")
+		Expect(err).To(MatchError(ContainSubstring("responded with status 401")))
+		Expect(err).To(MatchError(ContainSubstring("CF-InvalidAuthToken")))
+	})
+
+	Describe("Delete", func() {
+		var jobPolls atomic.Int32
+
+		BeforeEach(func() {
+			// replaces the server of the outer BeforeEach
+			server.Close()
+			jobPolls.Store(0)
+			mux := http.NewServeMux()
+			mux.HandleFunc("DELETE /v3/spaces/{guid}", func(w http.ResponseWriter, r *http.Request) {
+				if r.PathValue("guid") == "missing" {
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, `{"errors": [{"title": "CF-ResourceNotFound"}]}`)
+					return
+				}
+				w.Header().Set("Location", server.URL+"/v3/jobs/"+r.PathValue("guid"))
+				w.WriteHeader(http.StatusAccepted)
+			})
+			mux.HandleFunc("DELETE /v3/buildpacks/some-guid", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})
+			mux.HandleFunc("GET /v3/jobs/{guid}", func(w http.ResponseWriter, r *http.Request) {
+				if jobPolls.Add(1) < 3 {
+					fmt.Fprint(w, `{"state": "PROCESSING"}`)
+					return
+				}
+				switch r.PathValue("guid") {
+				case "failing":
+					fmt.Fprint(w, `{"state": "FAILED", "errors": [{"detail": "space has service instances"}]}`)
+				case "stuck":
+					fmt.Fprint(w, `{"state": "PROCESSING"}`)
+				default:
+					fmt.Fprint(w, `{"state": "COMPLETE"}`)
+				}
+			})
+			server = httptest.NewServer(mux)
+
+			client = New(server.URL, "bearer some-token", false).WithJobPolling(100*time.Millisecond, Backoff{Initial: time.Millisecond})
+		})
+
+		It("deletes resources synchronously", func() {
+			Expect(client.Delete("/v3/buildpacks/some-guid")).To(Succeed())
+			Expect(jobPolls.Load()).To(BeZero())
+		})
+
+		It("waits for the deletion job to complete", func() {
+			Expect(client.Delete("/v3/spaces/some-guid")).To(Succeed())
+			Expect(jobPolls.Load()).To(BeEquivalentTo(3))
+		})
+
+		It("returns the errors of failed deletion jobs", func() {
+			err := client.Delete("/v3/spaces/failing")
+			Expect(err).To(MatchError(MatchRegexp(`failed after \S+ in state FAILED: space has service instances`)))
+		})
+
+		It("gives up on jobs that do not complete in time", func() {
+			err := client.Delete("/v3/spaces/stuck")
+			Expect(err).To(MatchError(ContainSubstring("did not complete within 100ms, last state: PROCESSING")))
+		})
+
+		It("returns an error for unsuccessful requests", func() {
+			err := client.Delete("/v3/spaces/missing")
+			Expect(err).To(MatchError(ContainSubstring("responded with status 404")))
+		})
+	})
+
+	Describe("tokens", func() {
+		var forms chan string
+
+		BeforeEach(func() {
+			server.Close()
+			forms = make(chan string, 1)
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{"links": {"login": {"href": "%[1]s/login"}, "uaa": {"href": "%[1]s/uaa/"}}}`, server.URL)
+			})
+			mux.HandleFunc("POST /uaa/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+				clientID, clientSecret, _ := r.BasicAuth()
+				Expect(r.ParseForm()).To(Succeed())
+				forms <- fmt.Sprintf("%s:%s %s", clientID, clientSecret, r.PostForm.Encode())
+
+				if r.PostForm.Get("password") == "wrong" {
+					w.WriteHeader(http.StatusUnauthorized)
+					fmt.Fprint(w, `{"error": "unauthorized"}`)
+					return
+				}
+				fmt.Fprint(w, `{"access_token": "some-token", "token_type": "bearer"}`)
+			})
+			server = httptest.NewServer(mux)
+		})
+
+		It("finds the UAA from the API root", func() {
+			uaaURL, err := New(server.URL, "", false).UAAURL()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(uaaURL).To(Equal(server.URL + "/uaa"))
+		})
+
+		It("logs users in with the cf client", func() {
+			token, err := UserToken(server.URL, "admin", "secret", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token).To(Equal("bearer some-token"))
+			Expect(<-forms).To(Equal("cf: grant_type=password&password=secret&username=admin"))
+		})
+
+		It("gets tokens for clients", func() {
+			token, err := ClientToken(server.URL, "cats-admin", "client-secret", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token).To(Equal("bearer some-token"))
+			Expect(<-forms).To(Equal("cats-admin:client-secret grant_type=client_credentials"))
+		})
+
+		It("returns an error when the UAA rejects the credentials", func() {
+			_, err := UserToken(server.URL, "admin", "wrong", false)
+			Expect(err).To(MatchError(ContainSubstring("responded with status 401")))
+		})
+	})
+})
@@ -0,0 +1,745 @@
+package cc_client
+
+import (
+	"net/url"
+	"time"
+)
+
+// Metadata holds the labels and annotations of a resource.
+type Metadata struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// MetadataUpdate changes the labels and annotations of a resource. Keys set to nil are removed and
+// keys left out are kept.
+type MetadataUpdate struct {
+	Labels      map[string]*string `json:"labels,omitempty"`
+	Annotations map[string]*string `json:"annotations,omitempty"`
+}
+
+// Ref refers to a resource by its GUID.
+type Ref struct {
+	GUID string `json:"guid"`
+}
+
+// ToOne is a to-one relationship. Its Data is nil when the relationship is unset.
+type ToOne struct {
+	Data *Ref `json:"data"`
+}
+
+// RelationshipTo returns a to-one relationship to the resource with guid, or an unset one if guid
+// is empty.
+func RelationshipTo(guid string) ToOne {
+	if guid == "" {
+		return ToOne{}
+	}
+	return ToOne{Data: &Ref{GUID: guid}}
+}
+
+// ToMany is a to-many relationship.
+type ToMany struct {
+	Data []Ref `json:"data"`
+}
+
+func toMany(guids []string) ToMany {
+	relationship := ToMany{Data: []Ref{}}
+	for _, guid := range guids {
+		relationship.Data = append(relationship.Data, Ref{GUID: guid})
+	}
+	return relationship
+}
+
+// Link is an entry in the links of a resource.
+type Link struct {
+	Href   string `json:"href"`
+	Method string `json:"method,omitempty"`
+}
+
+// Lifecycle is how an app, build or droplet is staged, e.g. "buildpack" or "docker".
+type Lifecycle struct {
+	Type string        `json:"type"`
+	Data LifecycleData `json:"data"`
+}
+
+type LifecycleData struct {
+	Buildpacks []string `json:"buildpacks,omitempty"`
+	Stack      string   `json:"stack,omitempty"`
+}
+
+type App struct {
+	GUID      string          `json:"guid"`
+	Name      string          `json:"name"`
+	State     string          `json:"state"`
+	Lifecycle Lifecycle       `json:"lifecycle"`
+	CreatedAt time.Time       `json:"created_at"`
+	Metadata  Metadata        `json:"metadata"`
+	Links     map[string]Link `json:"links"`
+}
+
+type CreateAppRequest struct {
+	Name                 string            `json:"name"`
+	EnvironmentVariables map[string]string `json:"environment_variables,omitempty"`
+	Lifecycle            *Lifecycle        `json:"lifecycle,omitempty"`
+	Relationships        struct {
+		Space ToOne `json:"space"`
+	} `json:"relationships"`
+	Metadata *Metadata `json:"metadata,omitempty"`
+}
+
+func (c *Client) ListApps(query url.Values) ([]App, error) {
+	return GetAll[App](c, withQuery("/v3/apps", query))
+}
+
+func (c *Client) GetApp(guid string) (App, error) {
+	var app App
+	err := c.Get("/v3/apps/"+guid, &app)
+	return app, err
+}
+
+func (c *Client) CreateApp(request CreateAppRequest) (App, error) {
+	var app App
+	err := c.Post("/v3/apps", request, &app)
+	return app, err
+}
+
+// DeleteApp deletes the app and waits for the deletion job.
+func (c *Client) DeleteApp(guid string) error {
+	return c.Delete("/v3/apps/" + guid)
+}
+
+func (c *Client) StartApp(guid string) (App, error) {
+	var app App
+	err := c.Post("/v3/apps/"+guid+"/actions/start", nil, &app)
+	return app, err
+}
+
+func (c *Client) StopApp(guid string) (App, error) {
+	var app App
+	err := c.Post("/v3/apps/"+guid+"/actions/stop", nil, &app)
+	return app, err
+}
+
+// SetCurrentDroplet makes the app run the droplet the next time it is started.
+func (c *Client) SetCurrentDroplet(appGUID, dropletGUID string) error {
+	return c.Patch("/v3/apps/"+appGUID+"/relationships/current_droplet", RelationshipTo(dropletGUID), nil)
+}
+
+func (c *Client) GetCurrentDroplet(appGUID string) (Droplet, error) {
+	var droplet Droplet
+	err := c.Get("/v3/apps/"+appGUID+"/droplets/current", &droplet)
+	return droplet, err
+}
+
+type Process struct {
+	GUID       string `json:"guid"`
+	Type       string `json:"type"`
+	Command    string `json:"command"`
+	Instances  int    `json:"instances"`
+	MemoryInMB int    `json:"memory_in_mb"`
+	DiskInMB   int    `json:"disk_in_mb"`
+}
+
+// ProcessInstance is the state of an instance in the stats of a process.
+type ProcessInstance struct {
+	Index int    `json:"index"`
+	Type  string `json:"type"`
+	State string `json:"state"`
+}
+
+// ScaleRequest changes the scale of a process. Nil fields are left as they are.
+type ScaleRequest struct {
+	Instances  *int `json:"instances,omitempty"`
+	MemoryInMB *int `json:"memory_in_mb,omitempty"`
+	DiskInMB   *int `json:"disk_in_mb,omitempty"`
+}
+
+func (c *Client) ListAppProcesses(appGUID string, query url.Values) ([]Process, error) {
+	return GetAll[Process](c, withQuery("/v3/apps/"+appGUID+"/processes", query))
+}
+
+func (c *Client) GetProcess(guid string) (Process, error) {
+	var process Process
+	err := c.Get("/v3/processes/"+guid, &process)
+	return process, err
+}
+
+func (c *Client) GetProcessStats(guid string) ([]ProcessInstance, error) {
+	var stats struct {
+		Resources []ProcessInstance `json:"resources"`
+	}
+	err := c.Get("/v3/processes/"+guid+"/stats", &stats)
+	return stats.Resources, err
+}
+
+func (c *Client) ScaleProcess(appGUID, processType string, request ScaleRequest) (Process, error) {
+	var process Process
+	err := c.Post("/v3/apps/"+appGUID+"/processes/"+processType+"/actions/scale", request, &process)
+	return process, err
+}
+
+// SetProcessCommand gives the process a custom start command, which takes effect the next time the
+// app is started or deployed.
+func (c *Client) SetProcessCommand(guid, command string) (Process, error) {
+	var process Process
+	err := c.Patch("/v3/processes/"+guid, map[string]string{"command": command}, &process)
+	return process, err
+}
+
+// Sidecar is an additional command run in the containers of the processes with its ProcessTypes.
+type Sidecar struct {
+	GUID         string   `json:"guid"`
+	Name         string   `json:"name"`
+	Command      string   `json:"command"`
+	ProcessTypes []string `json:"process_types"`
+	MemoryInMB   int      `json:"memory_in_mb"`
+	Origin       string   `json:"origin"`
+}
+
+func (c *Client) ListAppSidecars(appGUID string) ([]Sidecar, error) {
+	return GetAll[Sidecar](c, "/v3/apps/"+appGUID+"/sidecars")
+}
+
+func (c *Client) ListProcessSidecars(processGUID string) ([]Sidecar, error) {
+	return GetAll[Sidecar](c, "/v3/processes/"+processGUID+"/sidecars")
+}
+
+type Package struct {
+	GUID  string          `json:"guid"`
+	Type  string          `json:"type"`
+	State string          `json:"state"`
+	Links map[string]Link `json:"links"`
+}
+
+type CreatePackageRequest struct {
+	Type          string `json:"type"`
+	Relationships struct {
+		App ToOne `json:"app"`
+	} `json:"relationships"`
+	Data *PackageData `json:"data,omitempty"`
+}
+
+// PackageData is the image of a docker package.
+type PackageData struct {
+	Image string `json:"image"`
+}
+
+func (c *Client) CreatePackage(request CreatePackageRequest) (Package, error) {
+	var pkg Package
+	err := c.Post("/v3/packages", request, &pkg)
+	return pkg, err
+}
+
+func (c *Client) GetPackage(guid string) (Package, error) {
+	var pkg Package
+	err := c.Get("/v3/packages/"+guid, &pkg)
+	return pkg, err
+}
+
+type Build struct {
+	GUID    string `json:"guid"`
+	State   string `json:"state"`
+	Error   string `json:"error"`
+	Droplet *Ref   `json:"droplet"`
+}
+
+type CreateBuildRequest struct {
+	Package   Ref        `json:"package"`
+	Lifecycle *Lifecycle `json:"lifecycle,omitempty"`
+}
+
+func (c *Client) CreateBuild(request CreateBuildRequest) (Build, error) {
+	var build Build
+	err := c.Post("/v3/builds", request, &build)
+	return build, err
+}
+
+func (c *Client) GetBuild(guid string) (Build, error) {
+	var build Build
+	err := c.Get("/v3/builds/"+guid, &build)
+	return build, err
+}
+
+type Droplet struct {
+	GUID  string `json:"guid"`
+	State string `json:"state"`
+	Error string `json:"error"`
+}
+
+func (c *Client) GetDroplet(guid string) (Droplet, error) {
+	var droplet Droplet
+	err := c.Get("/v3/droplets/"+guid, &droplet)
+	return droplet, err
+}
+
+type Deployment struct {
+	GUID   string `json:"guid"`
+	Status struct {
+		Value   string `json:"value"`
+		Reason  string `json:"reason"`
+		Details struct {
+			Error string `json:"error"`
+		} `json:"details"`
+		Canary *CanaryStatus `json:"canary,omitempty"`
+	} `json:"status"`
+	Strategy        string            `json:"strategy"`
+	Options         DeploymentOptions `json:"options"`
+	Droplet         Ref               `json:"droplet"`
+	PreviousDroplet Ref               `json:"previous_droplet"`
+	Revision        *RevisionRef      `json:"revision"`
+	NewProcesses    []ProcessRef      `json:"new_processes"`
+	Metadata        Metadata          `json:"metadata"`
+	CreatedAt       time.Time         `json:"created_at"`
+}
+
+type CanaryStatus struct {
+	Steps CanaryProgress `json:"steps"`
+}
+
+// CanaryProgress is the step a canary deployment is at, counting from 1, out of Total.
+type CanaryProgress struct {
+	Current int `json:"current"`
+	Total   int `json:"total"`
+}
+
+type RevisionRef struct {
+	GUID    string `json:"guid"`
+	Version int    `json:"version"`
+}
+
+type ProcessRef struct {
+	GUID string `json:"guid"`
+	Type string `json:"type"`
+}
+
+// DeploymentOptions are the options of a deployment. Zero values are left for the Cloud Controller
+// to default, e.g. to the current web process.
+type DeploymentOptions struct {
+	MaxInFlight  int            `json:"max_in_flight,omitempty"`
+	WebInstances int            `json:"web_instances,omitempty"`
+	MemoryInMB   int            `json:"memory_in_mb,omitempty"`
+	DiskInMB     int            `json:"disk_in_mb,omitempty"`
+	Canary       *CanaryOptions `json:"canary,omitempty"`
+}
+
+type CanaryOptions struct {
+	Steps []CanaryStep `json:"steps"`
+}
+
+type CanaryStep struct {
+	InstanceWeight int `json:"instance_weight"`
+}
+
+// InstanceWeights returns the percentage of instances running the new droplet at each canary
+// step, e.g. [25, 50, 75], or nil if the deployment has no explicit steps.
+func (d Deployment) InstanceWeights() []int {
+	if d.Options.Canary == nil {
+		return nil
+	}
+	var weights []int
+	for _, step := range d.Options.Canary.Steps {
+		weights = append(weights, step.InstanceWeight)
+	}
+	return weights
+}
+
+// CanaryStep returns the progress of a canary deployment, or zero values for other deployments.
+func (d Deployment) CanaryStep() CanaryProgress {
+	if d.Status.Canary == nil {
+		return CanaryProgress{}
+	}
+	return d.Status.Canary.Steps
+}
+
+type CreateDeploymentRequest struct {
+	Droplet       *Ref               `json:"droplet,omitempty"`
+	Revision      *Ref               `json:"revision,omitempty"`
+	Strategy      string             `json:"strategy,omitempty"`
+	Options       *DeploymentOptions `json:"options,omitempty"`
+	Metadata      *Metadata          `json:"metadata,omitempty"`
+	Relationships struct {
+		App ToOne `json:"app"`
+	} `json:"relationships"`
+}
+
+// NewCanarySteps returns canary options with a step for each of the instance weights.
+func NewCanarySteps(instanceWeights ...int) *CanaryOptions {
+	options := &CanaryOptions{}
+	for _, weight := range instanceWeights {
+		options.Steps = append(options.Steps, CanaryStep{InstanceWeight: weight})
+	}
+	return options
+}
+
+func (c *Client) CreateDeployment(request CreateDeploymentRequest) (Deployment, error) {
+	var deployment Deployment
+	err := c.Post("/v3/deployments", request, &deployment)
+	return deployment, err
+}
+
+func (c *Client) ListDeployments(query url.Values) ([]Deployment, error) {
+	return GetAll[Deployment](c, withQuery("/v3/deployments", query))
+}
+
+func (c *Client) GetDeployment(guid string) (Deployment, error) {
+	var deployment Deployment
+	err := c.Get("/v3/deployments/"+guid, &deployment)
+	return deployment, err
+}
+
+func (c *Client) CancelDeployment(guid string) error {
+	return c.Post("/v3/deployments/"+guid+"/actions/cancel", nil, nil)
+}
+
+// ContinueDeployment moves a paused canary deployment on to its next step, or to a full rollout
+// after its last step.
+func (c *Client) ContinueDeployment(guid string) error {
+	return c.Post("/v3/deployments/"+guid+"/actions/continue", nil, nil)
+}
+
+// Revision is a snapshot of the droplet, environment variables and process commands an app ran
+// with, created whenever one of them changes on a start or deployment.
+type Revision struct {
+	GUID        string                     `json:"guid"`
+	Version     int                        `json:"version"`
+	Description string                     `json:"description"`
+	Deployable  bool                       `json:"deployable"`
+	Droplet     Ref                        `json:"droplet"`
+	Processes   map[string]RevisionProcess `json:"processes"`
+	Metadata    Metadata                   `json:"metadata"`
+	CreatedAt   time.Time                  `json:"created_at"`
+}
+
+// RevisionProcess is a process of a revision. Its Command is nil unless the app has a custom
+// start command for the process.
+type RevisionProcess struct {
+	Command *string `json:"command"`
+}
+
+// environmentVariables is how the Cloud Controller wraps the environment variables of apps and
+// revisions.
+type environmentVariables struct {
+	Var map[string]string `json:"var"`
+}
+
+// ListAppRevisions returns the revisions of the app, oldest first unless query orders them
+// otherwise.
+func (c *Client) ListAppRevisions(appGUID string, query url.Values) ([]Revision, error) {
+	return GetAll[Revision](c, withQuery("/v3/apps/"+appGUID+"/revisions", query))
+}
+
+// ListDeployedRevisions returns the revisions the app's running processes were started from.
+func (c *Client) ListDeployedRevisions(appGUID string) ([]Revision, error) {
+	return GetAll[Revision](c, "/v3/apps/"+appGUID+"/revisions/deployed")
+}
+
+func (c *Client) GetRevision(guid string) (Revision, error) {
+	var revision Revision
+	err := c.Get("/v3/revisions/"+guid, &revision)
+	return revision, err
+}
+
+func (c *Client) GetRevisionEnvironmentVariables(guid string) (map[string]string, error) {
+	var env environmentVariables
+	err := c.Get("/v3/revisions/"+guid+"/environment_variables", &env)
+	return env.Var, err
+}
+
+func (c *Client) GetAppEnvironmentVariables(appGUID string) (map[string]string, error) {
+	var env environmentVariables
+	err := c.Get("/v3/apps/"+appGUID+"/environment_variables", &env)
+	return env.Var, err
+}
+
+type Route struct {
+	GUID         string        `json:"guid"`
+	Host         string        `json:"host"`
+	Path         string        `json:"path"`
+	Port         *int          `json:"port"`
+	URL          string        `json:"url"`
+	Destinations []Destination `json:"destinations"`
+}
+
+// Destination is where a route sends its traffic to: a process of an app.
+type Destination struct {
+	GUID     string         `json:"guid,omitempty"`
+	App      DestinationApp `json:"app"`
+	Port     int            `json:"port,omitempty"`
+	Protocol string         `json:"protocol,omitempty"`
+	Weight   int            `json:"weight,omitempty"`
+}
+
+type DestinationApp struct {
+	GUID    string              `json:"guid"`
+	Process *DestinationProcess `json:"process,omitempty"`
+}
+
+type DestinationProcess struct {
+	Type string `json:"type"`
+}
+
+func (c *Client) ListRoutes(query url.Values) ([]Route, error) {
+	return GetAll[Route](c, withQuery("/v3/routes", query))
+}
+
+// InsertRouteDestinations adds destinations to the route and returns all of its destinations.
+func (c *Client) InsertRouteDestinations(routeGUID string, destinations []Destination) ([]Destination, error) {
+	var result struct {
+		Destinations []Destination `json:"destinations"`
+	}
+	err := c.Post("/v3/routes/"+routeGUID+"/destinations", map[string][]Destination{"destinations": destinations}, &result)
+	return result.Destinations, err
+}
+
+type Domain struct {
+	GUID     string `json:"guid"`
+	Name     string `json:"name"`
+	Internal bool   `json:"internal"`
+}
+
+func (c *Client) ListDomains(query url.Values) ([]Domain, error) {
+	return GetAll[Domain](c, withQuery("/v3/domains", query))
+}
+
+type IsolationSegment struct {
+	GUID     string   `json:"guid"`
+	Name     string   `json:"name"`
+	Metadata Metadata `json:"metadata"`
+}
+
+func (c *Client) ListIsolationSegments(query url.Values) ([]IsolationSegment, error) {
+	return GetAll[IsolationSegment](c, withQuery("/v3/isolation_segments", query))
+}
+
+func (c *Client) CreateIsolationSegment(name string) (IsolationSegment, error) {
+	var isolationSegment IsolationSegment
+	err := c.Post("/v3/isolation_segments", map[string]string{"name": name}, &isolationSegment)
+	return isolationSegment, err
+}
+
+func (c *Client) DeleteIsolationSegment(guid string) error {
+	return c.Delete("/v3/isolation_segments/" + guid)
+}
+
+// EntitleOrganizations allows the organizations to use the isolation segment.
+func (c *Client) EntitleOrganizations(isolationSegmentGUID string, orgGUIDs ...string) error {
+	return c.Post("/v3/isolation_segments/"+isolationSegmentGUID+"/relationships/organizations", toMany(orgGUIDs), nil)
+}
+
+func (c *Client) RevokeOrganization(isolationSegmentGUID, orgGUID string) error {
+	return c.Delete("/v3/isolation_segments/" + isolationSegmentGUID + "/relationships/organizations/" + orgGUID)
+}
+
+// SetSpaceIsolationSegment assigns the isolation segment to the space, or unassigns the space's
+// isolation segment if isolationSegmentGUID is empty.
+func (c *Client) SetSpaceIsolationSegment(spaceGUID, isolationSegmentGUID string) error {
+	return c.Patch("/v3/spaces/"+spaceGUID+"/relationships/isolation_segment", RelationshipTo(isolationSegmentGUID), nil)
+}
+
+// SetDefaultIsolationSegment sets the default isolation segment of the organization, or unsets it if
+// isolationSegmentGUID is empty.
+func (c *Client) SetDefaultIsolationSegment(orgGUID, isolationSegmentGUID string) error {
+	return c.Patch("/v3/organizations/"+orgGUID+"/relationships/default_isolation_segment", RelationshipTo(isolationSegmentGUID), nil)
+}
+
+// GetDefaultIsolationSegment returns the GUID of the default isolation segment of the
+// organization, or "" if it has none.
+func (c *Client) GetDefaultIsolationSegment(orgGUID string) (string, error) {
+	var relationship ToOne
+	err := c.Get("/v3/organizations/"+orgGUID+"/relationships/default_isolation_segment", &relationship)
+	if err != nil || relationship.Data == nil {
+		return "", err
+	}
+	return relationship.Data.GUID, nil
+}
+
+// QuotaLimits are the limits of an organization or space quota. Nil limits are unlimited.
+type QuotaLimits struct {
+	Apps     AppQuota     `json:"apps"`
+	Services ServiceQuota `json:"services"`
+	Routes   RouteQuota   `json:"routes"`
+}
+
+type AppQuota struct {
+	TotalMemoryInMB              *int `json:"total_memory_in_mb"`
+	PerProcessMemoryInMB         *int `json:"per_process_memory_in_mb"`
+	TotalInstances               *int `json:"total_instances"`
+	PerAppTasks                  *int `json:"per_app_tasks"`
+	LogRateLimitInBytesPerSecond *int `json:"log_rate_limit_in_bytes_per_second"`
+}
+
+type ServiceQuota struct {
+	PaidServicesAllowed   *bool `json:"paid_services_allowed,omitempty"`
+	TotalServiceInstances *int  `json:"total_service_instances"`
+	TotalServiceKeys      *int  `json:"total_service_keys"`
+}
+
+type RouteQuota struct {
+	TotalRoutes        *int `json:"total_routes"`
+	TotalReservedPorts *int `json:"total_reserved_ports"`
+}
+
+// Limit returns a quota limit of n.
+func Limit(n int) *int {
+	return &n
+}
+
+// Allowed returns a quota setting that allows, or disallows, something. Nil settings are left to
+// the Cloud Controller.
+func Allowed(allowed bool) *bool {
+	return &allowed
+}
+
+// Quota is an organization or space quota.
+type Quota struct {
+	GUID string `json:"guid"`
+	Name string `json:"name"`
+	QuotaLimits
+	Relationships struct {
+		Organizations ToMany `json:"organizations"`
+		Organization  ToOne  `json:"organization"`
+		Spaces        ToMany `json:"spaces"`
+	} `json:"relationships"`
+}
+
+// CreateOrganizationQuota creates an organization quota and applies it to the organizations.
+func (c *Client) CreateOrganizationQuota(name string, limits QuotaLimits, orgGUIDs ...string) (Quota, error) {
+	request := struct {
+		Name string `json:"name"`
+		QuotaLimits
+		Relationships struct {
+			Organizations ToMany `json:"organizations"`
+		} `json:"relationships"`
+	}{Name: name, QuotaLimits: limits}
+	request.Relationships.Organizations = toMany(orgGUIDs)
+	var quota Quota
+	err := c.Post("/v3/organization_quotas", request, &quota)
+	return quota, err
+}
+
+// CreateSpaceQuota creates a space quota in the organization and applies it to the spaces.
+func (c *Client) CreateSpaceQuota(name string, limits QuotaLimits, orgGUID string, spaceGUIDs ...string) (Quota, error) {
+	request := struct {
+		Name string `json:"name"`
+		QuotaLimits
+		Relationships struct {
+			Organization ToOne  `json:"organization"`
+			Spaces       ToMany `json:"spaces"`
+		} `json:"relationships"`
+	}{Name: name, QuotaLimits: limits}
+	request.Relationships.Organization = RelationshipTo(orgGUID)
+	request.Relationships.Spaces = toMany(spaceGUIDs)
+	var quota Quota
+	err := c.Post("/v3/space_quotas", request, &quota)
+	return quota, err
+}
+
+// GetQuota returns a quota of kind "organization_quotas" or "space_quotas".
+func (c *Client) GetQuota(kind, guid string) (Quota, error) {
+	var quota Quota
+	err := c.Get("/v3/"+kind+"/"+guid, &quota)
+	return quota, err
+}
+
+// UpdateQuota replaces all of the limits of a quota of kind "organization_quotas" or
+// "space_quotas".
+func (c *Client) UpdateQuota(kind, guid string, limits QuotaLimits) (Quota, error) {
+	var quota Quota
+	err := c.Patch("/v3/"+kind+"/"+guid, limits, &quota)
+	return quota, err
+}
+
+func (c *Client) DeleteQuota(kind, guid string) error {
+	return c.Delete("/v3/" + kind + "/" + guid)
+}
+
+// ApplyOrganizationQuota applies the quota to the organizations instead of the quota they had.
+func (c *Client) ApplyOrganizationQuota(quotaGUID string, orgGUIDs ...string) error {
+	return c.Post("/v3/organization_quotas/"+quotaGUID+"/relationships/organizations", toMany(orgGUIDs), nil)
+}
+
+func (c *Client) ApplySpaceQuota(quotaGUID string, spaceGUIDs ...string) error {
+	return c.Post("/v3/space_quotas/"+quotaGUID+"/relationships/spaces", toMany(spaceGUIDs), nil)
+}
+
+// resourceMetadata is the GUID and metadata of any v3 resource.
+type resourceMetadata struct {
+	GUID     string   `json:"guid"`
+	Metadata Metadata `json:"metadata"`
+}
+
+// GetMetadata returns the metadata of the resource with guid in a v3 collection, e.g. "apps".
+func (c *Client) GetMetadata(collection, guid string) (Metadata, error) {
+	var resource resourceMetadata
+	err := c.Get("/v3/"+collection+"/"+guid, &resource)
+	return resource.Metadata, err
+}
+
+// UpdateMetadata changes the metadata of the resource with guid in a v3 collection, e.g. "apps",
+// and returns its metadata afterwards.
+func (c *Client) UpdateMetadata(collection, guid string, update MetadataUpdate) (Metadata, error) {
+	request := struct {
+		Metadata MetadataUpdate `json:"metadata"`
+	}{update}
+	var resource resourceMetadata
+	err := c.Patch("/v3/"+collection+"/"+guid, request, &resource)
+	return resource.Metadata, err
+}
+
+// ListGUIDs returns the GUIDs of the resources in a v3 collection, e.g. "apps", that match query.
+func (c *Client) ListGUIDs(collection string, query url.Values) ([]string, error) {
+	resources, err := GetAll[resourceMetadata](c, withQuery("/v3/"+collection, query))
+	var guids []string
+	for _, resource := range resources {
+		guids = append(guids, resource.GUID)
+	}
+	return guids, err
+}
+
+// ServiceCredentialBinding is an app binding ("app") or service key ("key") of a service instance.
+type ServiceCredentialBinding struct {
+	GUID          string `json:"guid"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	Relationships struct {
+		App             ToOne `json:"app"`
+		ServiceInstance ToOne `json:"service_instance"`
+	} `json:"relationships"`
+}
+
+func (c *Client) ListServiceCredentialBindings(query url.Values) ([]ServiceCredentialBinding, error) {
+	return GetAll[ServiceCredentialBinding](c, withQuery("/v3/service_credential_bindings", query))
+}
+
+// ManifestDiffOperation is a JSON Patch operation, "add", "replace" or "remove", that applying a
+// manifest would make. Path points into the applied manifest, e.g. "/applications/0/env/KEY", and
+// Was is the current value of what is replaced or removed.
+type ManifestDiffOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Was   interface{} `json:"was,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// DiffSpaceManifest returns the changes that applying the manifest to the apps in the space would
+// make, without making them.
+func (c *Client) DiffSpaceManifest(spaceGUID string, manifest []byte) ([]ManifestDiffOperation, error) {
+	var result struct {
+		Diff []ManifestDiffOperation `json:"diff"`
+	}
+	err := c.Post("/v3/spaces/"+spaceGUID+"/manifest_diff", YAML(manifest), &result)
+	return result.Diff, err
+}
+
+// ApplySpaceManifest applies the manifest to the apps named in it and waits for the job to
+// complete. The apps are not restarted.
+func (c *Client) ApplySpaceManifest(spaceGUID string, manifest []byte) error {
+	return c.Post("/v3/spaces/"+spaceGUID+"/actions/apply_manifest", YAML(manifest), nil)
+}
+
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
@@ -0,0 +1,168 @@
+package cc_client_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Resources", func() {
+	var (
+		server   *httptest.Server
+		client   *Client
+		requests map[string]string
+	)
+
+	BeforeEach(func() {
+		requests = map[string]string{}
+		record := func(r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			requests[r.Method+" "+r.URL.RequestURI()] = string(body)
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /v3/apps", func(w http.ResponseWriter, r *http.Request) {
+			record(r)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"guid": "app-guid", "name": "some-app", "state": "STOPPED", "links": {"self": {"href": "https://api.example.com/v3/apps/app-guid"}}}`)
+		})
+		mux.HandleFunc("GET /v3/apps", func(w http.ResponseWriter, r *http.Request) {
+			record(r)
+			fmt.Fprint(w, `{"pagination": {"next": null}, "resources": [{"guid": "app-guid", "name": "some-app"}]}`)
+		})
+		mux.HandleFunc("POST /v3/apps/app-guid/processes/web/actions/scale", func(w http.ResponseWriter, r *http.Request) {
+			record(r)
+			fmt.Fprint(w, `{"guid": "process-guid", "type": "web", "instances": 3, "memory_in_mb": 256}`)
+		})
+		mux.HandleFunc("GET /v3/processes/process-guid/stats", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"resources": [{"index": 0, "state": "RUNNING"}, {"index": 1, "state": "STARTING"}]}`)
+		})
+		mux.HandleFunc("DELETE /v3/apps/app-guid", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Location", server.URL+"/v3/jobs/delete-job")
+			w.WriteHeader(http.StatusAccepted)
+		})
+		mux.HandleFunc("GET /v3/jobs/delete-job", func(w http.ResponseWriter, r *http.Request) {
+			requests["job polled"] = ""
+			fmt.Fprint(w, `{"state": "COMPLETE"}`)
+		})
+		mux.HandleFunc("GET /v3/builds/missing-guid", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors": [{"code": 10010, "title": "CF-ResourceNotFound", "detail": "Build not found"}]}`)
+		})
+		mux.HandleFunc("PATCH /v3/spaces/space-guid/relationships/isolation_segment", func(w http.ResponseWriter, r *http.Request) {
+			record(r)
+			fmt.Fprint(w, `{"data": null}`)
+		})
+		mux.HandleFunc("GET /v3/organizations/org-guid/relationships/default_isolation_segment", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"data": {"guid": "iso-seg-guid"}}`)
+		})
+		mux.HandleFunc("GET /v3/revisions/revision-guid/environment_variables", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"var": {"FOO": "bar"}, "links": {"self": {"href": "https://api.example.com/v3/revisions/revision-guid/environment_variables"}}}`)
+		})
+		mux.HandleFunc("POST /v3/spaces/space-guid/manifest_diff", func(w http.ResponseWriter, r *http.Request) {
+			record(r)
+			requests["manifest content type"] = r.Header.Get("Content-Type")
+			fmt.Fprint(w, `{"diff": [{"op": "replace", "path": "/applications/0/memory", "was": "256M", "value": "512M"}]}`)
+		})
+		server = httptest.NewServer(mux)
+
+		client = New(server.URL, "bearer some-token", false).WithJobPolling(time.Second, Backoff{Initial: time.Millisecond})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("creates apps from typed requests", func() {
+		request := CreateAppRequest{
+			Name:                 "some-app",
+			EnvironmentVariables: map[string]string{"foo": "bar"},
+			Lifecycle:            &Lifecycle{Type: "docker"},
+			Metadata:             &Metadata{Labels: map[string]string{"some": "label"}},
+		}
+		request.Relationships.Space = RelationshipTo("space-guid")
+
+		app, err := client.CreateApp(request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(app.GUID).To(Equal("app-guid"))
+		Expect(app.Links["self"].Href).To(Equal("https://api.example.com/v3/apps/app-guid"))
+
+		Expect(requests["POST /v3/apps"]).To(MatchJSON(`{
+			"name": "some-app",
+			"environment_variables": {"foo": "bar"},
+			"lifecycle": {"type": "docker", "data": {}},
+			"relationships": {"space": {"data": {"guid": "space-guid"}}},
+			"metadata": {"labels": {"some": "label"}}
+		}`))
+	})
+
+	It("lists resources with a query", func() {
+		apps, err := client.ListApps(url.Values{"names": {"some-app"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(apps).To(HaveLen(1))
+		Expect(requests).To(HaveKey("GET /v3/apps?names=some-app"))
+	})
+
+	It("scales processes with numbers and leaves out the unchanged values", func() {
+		instances := 3
+		process, err := client.ScaleProcess("app-guid", "web", ScaleRequest{Instances: &instances})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(process.Instances).To(Equal(3))
+
+		Expect(requests["POST /v3/apps/app-guid/processes/web/actions/scale"]).To(MatchJSON(`{"instances": 3}`))
+	})
+
+	It("gets the stats of processes", func() {
+		instances, err := client.GetProcessStats("process-guid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instances).To(Equal([]ProcessInstance{{Index: 0, State: "RUNNING"}, {Index: 1, State: "STARTING"}}))
+	})
+
+	It("waits for asynchronous deletions", func() {
+		Expect(client.DeleteApp("app-guid")).To(Succeed())
+		Expect(requests).To(HaveKey("job polled"))
+	})
+
+	It("returns the errors of the Cloud Controller", func() {
+		_, err := client.GetBuild("missing-guid")
+
+		var responseError *ResponseError
+		Expect(errors.As(err, &responseError)).To(BeTrue())
+		Expect(responseError.StatusCode).To(Equal(http.StatusNotFound))
+		Expect(responseError.Errors).To(Equal([]Error{{Code: 10010, Title: "CF-ResourceNotFound", Detail: "Build not found"}}))
+		Expect(responseError.HasTitle("CF-ResourceNotFound")).To(BeTrue())
+	})
+
+	It("unsets to-one relationships with null", func() {
+		Expect(client.SetSpaceIsolationSegment("space-guid", "")).To(Succeed())
+		Expect(requests["PATCH /v3/spaces/space-guid/relationships/isolation_segment"]).To(MatchJSON(`{"data": null}`))
+	})
+
+	It("reads to-one relationships", func() {
+		Expect(client.GetDefaultIsolationSegment("org-guid")).To(Equal("iso-seg-guid"))
+	})
+
+	It("sends manifests as YAML", func() {
+		manifest := "applications:\n- name: some-app\n  memory: 512M\n"
+		diff, err := client.DiffSpaceManifest("space-guid", []byte(manifest))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff).To(Equal([]ManifestDiffOperation{{Op: "replace", Path: "/applications/0/memory", Was: "256M", Value: "512M"}}))
+
+		Expect(requests["POST /v3/spaces/space-guid/manifest_diff"]).To(Equal(manifest))
+		Expect(requests["manifest content type"]).To(Equal("application/x-yaml"))
+	})
+
+	It("unwraps environment variables", func() {
+		Expect(client.GetRevisionEnvironmentVariables("revision-guid")).To(Equal(map[string]string{"FOO": "bar"}))
+	})
+})
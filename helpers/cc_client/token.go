@@ -0,0 +1,94 @@
+package cc_client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// UserToken logs a UAA user in with the UAA advertised by the API at apiURL, like "cf auth" does,
+// and returns an Authorization header value for New.
+func UserToken(apiURL, username, password string, skipSSLValidation bool) (string, error) {
+	return fetchToken(apiURL, skipSSLValidation, "cf", "", url.Values{
+		"grant_type": {"password"},
+		"username":   {username},
+		"password":   {password},
+	})
+}
+
+// ClientToken gets a token for a UAA client with the client_credentials grant, like
+// "cf auth --client-credentials" does, and returns an Authorization header value for New.
+func ClientToken(apiURL, clientID, clientSecret string, skipSSLValidation bool) (string, error) {
+	return fetchToken(apiURL, skipSSLValidation, clientID, clientSecret, url.Values{
+		"grant_type": {"client_credentials"},
+	})
+}
+
+// UAAURL returns the URL of the UAA advertised by the root endpoint of the API.
+func (c *Client) UAAURL() (string, error) {
+	var root struct {
+		Links map[string]*link `json:"links"`
+	}
+	err := c.Get("/", &root)
+	if err != nil {
+		return "", err
+	}
+
+	for _, name := range []string{"uaa", "login"} {
+		if l := root.Links[name]; l != nil && l.Href != "" {
+			return strings.TrimSuffix(l.Href, "/"), nil
+		}
+	}
+	return "", fmt.Errorf("the API root at %s does not link to a UAA", c.apiURL)
+}
+
+type link struct {
+	Href string `json:"href"`
+}
+
+func fetchToken(apiURL string, skipSSLValidation bool, clientID, clientSecret string, form url.Values) (string, error) {
+	uaaURL, err := New(apiURL, "", skipSSLValidation).UAAURL()
+	if err != nil {
+		return "", err
+	}
+
+	tokenURL := uaaURL + "/oauth/token"
+	req, err := http.NewRequest(http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(clientID, clientSecret)
+
+	resp, err := newHTTPClient(skipSSLValidation).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("POST %s responded with status %d: %s", tokenURL, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+	}
+	err = json.Unmarshal(body, &token)
+	if err != nil {
+		return "", fmt.Errorf("POST %s returned invalid JSON: %w", tokenURL, err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("POST %s did not return an access token", tokenURL)
+	}
+
+	return token.TokenType + " " + token.AccessToken, nil
+}
@@ -0,0 +1,252 @@
+package cc_client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Backoff is how often an operation is polled: first after Initial, then after intervals growing
+// by Factor up to Max.
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+	Factor  float64
+}
+
+var DefaultBackoff = Backoff{Initial: time.Second, Max: 10 * time.Second, Factor: 1.5}
+
+func (b Backoff) next(interval time.Duration) time.Duration {
+	if b.Factor > 1 {
+		interval = time.Duration(float64(interval) * b.Factor)
+	}
+	if b.Max > 0 && interval > b.Max {
+		interval = b.Max
+	}
+	return interval
+}
+
+// Status is the state of an asynchronous operation when it was polled.
+type Status struct {
+	State    string
+	Done     bool
+	Failed   bool
+	Detail   string
+	Warnings []string
+}
+
+// Operation is an asynchronous operation of the Cloud Controller, such as a job or the last
+// operation of a service instance.
+type Operation struct {
+	// Description names the operation in errors, e.g. "job /v3/jobs/<guid>".
+	Description string
+	Poll        func() (Status, error)
+}
+
+// OperationError is returned by Wait when an operation fails or does not finish in time.
+type OperationError struct {
+	Description string
+	Status      Status
+	Elapsed     time.Duration
+
+	// TimedOut is set when the operation was still in progress after Timeout.
+	TimedOut bool
+	Timeout  time.Duration
+}
+
+func (e *OperationError) Error() string {
+	var message string
+	if e.TimedOut {
+		message = fmt.Sprintf("%s did not complete within %s, last state: %s", e.Description, e.Timeout, e.Status.State)
+	} else {
+		message = fmt.Sprintf("%s failed after %s in state %s", e.Description, e.Elapsed.Round(time.Millisecond), e.Status.State)
+	}
+	if e.Status.Detail != "" {
+		message += ": " + e.Status.Detail
+	}
+	if len(e.Status.Warnings) > 0 {
+		message += " (warnings: " + strings.Join(e.Status.Warnings, "; ") + ")"
+	}
+	return message
+}
+
+// Wait polls the operation with backoff until it is done, and returns an *OperationError if it
+// fails or is not done within timeout. Errors polling the operation are returned as they are.
+func Wait(operation Operation, timeout time.Duration, backoff Backoff) error {
+	start := time.Now()
+	interval := backoff.Initial
+	for {
+		status, err := operation.Poll()
+		if err != nil {
+			return fmt.Errorf("polling %s: %w", operation.Description, err)
+		}
+
+		elapsed := time.Since(start)
+		if status.Failed {
+			return &OperationError{Description: operation.Description, Status: status, Elapsed: elapsed}
+		}
+		if status.Done {
+			return nil
+		}
+		if elapsed+interval > timeout {
+			return &OperationError{Description: operation.Description, Status: status, Elapsed: elapsed, TimedOut: true, Timeout: timeout}
+		}
+
+		time.Sleep(interval)
+		interval = backoff.next(interval)
+	}
+}
+
+type jobResource struct {
+	State    string  `json:"state"`
+	Errors   []Error `json:"errors"`
+	Warnings []struct {
+		Detail string `json:"detail"`
+	} `json:"warnings"`
+}
+
+// JobOperation polls the job at jobURL, e.g. the Location of a 202 response.
+func (c *Client) JobOperation(jobURL string) Operation {
+	return Operation{
+		Description: "job " + jobURL,
+		Poll: func() (Status, error) {
+			var job jobResource
+			err := c.Get(jobURL, &job)
+			if err != nil {
+				return Status{}, err
+			}
+
+			status := Status{State: job.State, Done: job.State == "COMPLETE", Failed: job.State == "FAILED"}
+			var details []string
+			for _, jobError := range job.Errors {
+				details = append(details, jobError.Detail)
+			}
+			status.Detail = strings.Join(details, "; ")
+			for _, warning := range job.Warnings {
+				status.Warnings = append(status.Warnings, warning.Detail)
+			}
+			return status, nil
+		},
+	}
+}
+
+type lastOperation struct {
+	Type        string `json:"type"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+}
+
+func lastOperationStatus(operation lastOperation) Status {
+	return Status{
+		State:  operation.Type + " " + operation.State,
+		Done:   operation.State == "succeeded",
+		Failed: operation.State == "failed",
+		Detail: operation.Description,
+	}
+}
+
+// ServiceInstanceOperation polls the last operation of the service instance named name. It is
+// done when the operation succeeded, or when the instance is gone after a deletion.
+func (c *Client) ServiceInstanceOperation(name string) Operation {
+	return Operation{
+		Description: "last operation of service instance " + name,
+		Poll: func() (Status, error) {
+			instances, err := GetAll[struct {
+				LastOperation lastOperation `json:"last_operation"`
+			}](c, withQuery("/v3/service_instances", url.Values{"names": {name}}))
+			if err != nil {
+				return Status{}, err
+			}
+			if len(instances) == 0 {
+				return Status{State: "deleted", Done: true}, nil
+			}
+			return lastOperationStatus(instances[0].LastOperation), nil
+		},
+	}
+}
+
+// ServiceCredentialBindingOperation polls the last operation of the app binding or service key
+// with guid. It is done when the operation succeeded, or when the binding is gone after a deletion.
+func (c *Client) ServiceCredentialBindingOperation(guid string) Operation {
+	return Operation{
+		Description: "last operation of service credential binding " + guid,
+		Poll: func() (Status, error) {
+			var binding struct {
+				LastOperation lastOperation `json:"last_operation"`
+			}
+			err := c.Get("/v3/service_credential_bindings/"+guid, &binding)
+			var responseError *ResponseError
+			if errors.As(err, &responseError) && responseError.StatusCode == http.StatusNotFound {
+				return Status{State: "deleted", Done: true}, nil
+			}
+			if err != nil {
+				return Status{}, err
+			}
+			return lastOperationStatus(binding.LastOperation), nil
+		},
+	}
+}
+
+// BuildOperation polls the build with guid until it is staged.
+func (c *Client) BuildOperation(guid string) Operation {
+	return Operation{
+		Description: "build " + guid,
+		Poll: func() (Status, error) {
+			build, err := c.GetBuild(guid)
+			if err != nil {
+				return Status{}, err
+			}
+			return Status{State: build.State, Done: build.State == "STAGED", Failed: build.State == "FAILED", Detail: build.Error}, nil
+		},
+	}
+}
+
+// DeploymentOperation polls the deployment with guid until it is finalized, and fails if it is
+// finalized for another reason than reason, e.g. "DEPLOYED" or "CANCELED".
+func (c *Client) DeploymentOperation(guid, reason string) Operation {
+	return c.DeploymentStatusOperation(guid, "FINALIZED", reason)
+}
+
+// DeploymentStatusOperation polls the deployment with guid until its status has value and reason,
+// e.g. "ACTIVE" and "PAUSED", and fails if it is finalized with another reason.
+func (c *Client) DeploymentStatusOperation(guid, value, reason string) Operation {
+	return c.deploymentOperation(guid, func(deployment Deployment) bool {
+		return deployment.Status.Value == value && deployment.Status.Reason == reason
+	})
+}
+
+// CanaryStepOperation polls the canary deployment with guid until it is paused at step, counting
+// from 1, and fails if it is finalized first.
+func (c *Client) CanaryStepOperation(guid string, step int) Operation {
+	operation := c.deploymentOperation(guid, func(deployment Deployment) bool {
+		return deployment.Status.Reason == "PAUSED" && deployment.CanaryStep().Current == step
+	})
+	operation.Description = fmt.Sprintf("step %d of canary deployment %s", step, guid)
+	return operation
+}
+
+func (c *Client) deploymentOperation(guid string, done func(Deployment) bool) Operation {
+	return Operation{
+		Description: "deployment " + guid,
+		Poll: func() (Status, error) {
+			deployment, err := c.GetDeployment(guid)
+			if err != nil {
+				return Status{}, err
+			}
+
+			status := Status{State: deployment.Status.Value, Detail: deployment.Status.Details.Error}
+			if deployment.Status.Reason != "" {
+				status.State += " (" + deployment.Status.Reason + ")"
+			}
+			if progress := deployment.CanaryStep(); progress.Total > 0 {
+				status.State += fmt.Sprintf(" at canary step %d/%d", progress.Current, progress.Total)
+			}
+			status.Done = done(deployment)
+			status.Failed = !status.Done && deployment.Status.Value == "FINALIZED"
+			return status, nil
+		},
+	}
+}
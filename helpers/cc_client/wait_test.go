@@ -0,0 +1,147 @@
+package cc_client_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Wait", func() {
+	fastBackoff := Backoff{Initial: time.Millisecond, Max: 4 * time.Millisecond, Factor: 2}
+
+	sequence := func(statuses ...Status) (Operation, *[]time.Time) {
+		var polls []time.Time
+		return Operation{
+			Description: "some operation",
+			Poll: func() (Status, error) {
+				polls = append(polls, time.Now())
+				status := statuses[0]
+				if len(statuses) > 1 {
+					statuses = statuses[1:]
+				}
+				return status, nil
+			},
+		}, &polls
+	}
+
+	It("polls until the operation is done, backing off", func() {
+		operation, polls := sequence(
+			Status{State: "PROCESSING"},
+			Status{State: "PROCESSING"},
+			Status{State: "PROCESSING"},
+			Status{State: "PROCESSING"},
+			Status{State: "COMPLETE", Done: true},
+		)
+
+		Expect(Wait(operation, time.Second, fastBackoff)).To(Succeed())
+		Expect(*polls).To(HaveLen(5))
+
+		// 1ms, 2ms, 4ms, then capped at 4ms
+		Expect((*polls)[2].Sub((*polls)[1])).To(BeNumerically(">=", 2*time.Millisecond))
+		Expect((*polls)[4].Sub((*polls)[3])).To(BeNumerically(">=", 4*time.Millisecond))
+	})
+
+	It("surfaces the failure detail and warnings of failed operations", func() {
+		operation, _ := sequence(
+			Status{State: "PROCESSING"},
+			Status{State: "FAILED", Failed: true, Detail: "The service broker rejected the request", Warnings: []string{"the plan is deprecated"}},
+		)
+
+		err := Wait(operation, time.Second, fastBackoff)
+
+		var operationError *OperationError
+		Expect(errors.As(err, &operationError)).To(BeTrue())
+		Expect(operationError.TimedOut).To(BeFalse())
+		Expect(err).To(MatchError(MatchRegexp(`^some operation failed after \S+ in state FAILED: The service broker rejected the request \(warnings: the plan is deprecated\)$`)))
+	})
+
+	It("gives up after the timeout", func() {
+		operation, _ := sequence(Status{State: "PROCESSING"})
+
+		err := Wait(operation, 20*time.Millisecond, fastBackoff)
+
+		var operationError *OperationError
+		Expect(errors.As(err, &operationError)).To(BeTrue())
+		Expect(operationError.TimedOut).To(BeTrue())
+		Expect(err).To(MatchError("some operation did not complete within 20ms, last state: PROCESSING"))
+	})
+
+	It("returns errors polling the operation", func() {
+		err := Wait(Operation{
+			Description: "some operation",
+			Poll: func() (Status, error) {
+				return Status{}, errors.New("connection refused")
+			},
+		}, time.Second, fastBackoff)
+
+		Expect(err).To(MatchError("polling some operation: connection refused"))
+	})
+
+	Describe("operations of the Cloud Controller", func() {
+		var (
+			server *httptest.Server
+			client *Client
+		)
+
+		BeforeEach(func() {
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /v3/jobs/failed-job", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"state": "FAILED", "errors": [{"detail": "broker unreachable"}], "warnings": [{"detail": "retrying"}]}`)
+			})
+			mux.HandleFunc("GET /v3/service_instances", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("names") == "deleted-instance" {
+					fmt.Fprint(w, `{"pagination": {"next": null}, "resources": []}`)
+					return
+				}
+				fmt.Fprint(w, `{"pagination": {"next": null}, "resources": [{"last_operation": {"type": "create", "state": "failed", "description": "quota exceeded"}}]}`)
+			})
+			mux.HandleFunc("GET /v3/service_credential_bindings/deleted-binding", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			})
+			mux.HandleFunc("GET /v3/builds/build-guid", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"guid": "build-guid", "state": "STAGED", "droplet": {"guid": "droplet-guid"}}`)
+			})
+			mux.HandleFunc("GET /v3/deployments/deployment-guid", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"guid": "deployment-guid", "status": {"value": "FINALIZED", "reason": "SUPERSEDED"}}`)
+			})
+			server = httptest.NewServer(mux)
+
+			client = New(server.URL, "bearer some-token", false)
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("reports the errors and warnings of jobs", func() {
+			err := Wait(client.JobOperation("/v3/jobs/failed-job"), time.Second, fastBackoff)
+			Expect(err).To(MatchError(MatchRegexp(`^job /v3/jobs/failed-job failed after \S+ in state FAILED: broker unreachable \(warnings: retrying\)$`)))
+		})
+
+		It("reports the description of failed service instance operations", func() {
+			err := Wait(client.ServiceInstanceOperation("some-instance"), time.Second, fastBackoff)
+			Expect(err).To(MatchError(ContainSubstring("in state create failed: quota exceeded")))
+		})
+
+		It("treats deleted service instances and bindings as done", func() {
+			Expect(Wait(client.ServiceInstanceOperation("deleted-instance"), time.Second, fastBackoff)).To(Succeed())
+			Expect(Wait(client.ServiceCredentialBindingOperation("deleted-binding"), time.Second, fastBackoff)).To(Succeed())
+		})
+
+		It("waits for builds to stage", func() {
+			Expect(Wait(client.BuildOperation("build-guid"), time.Second, fastBackoff)).To(Succeed())
+		})
+
+		It("fails deployments finalized for another reason", func() {
+			err := Wait(client.DeploymentOperation("deployment-guid", "DEPLOYED"), time.Second, fastBackoff)
+			Expect(err).To(MatchError(ContainSubstring("in state FINALIZED (SUPERSEDED)")))
+		})
+	})
+})
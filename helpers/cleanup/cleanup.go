@@ -0,0 +1,166 @@
+package cleanup
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+)
+
+// Resource is something CATS created on the platform.
+type Resource struct {
+	Kind      string    `json:"kind"`
+	GUID      string    `json:"guid"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type kind struct {
+	name string
+	path string
+
+	// deleteQuery is appended to the path of the resource when deleting it.
+	deleteQuery string
+
+	// unlabeled kinds have no metadata, so they are never found with a label selector.
+	unlabeled bool
+}
+
+// kinds are listed in the order they have to be deleted in: service instances before the brokers
+// that provide them, spaces and orgs, whose contents are deleted with them, before the quotas,
+// security groups, isolation segments and domains they use, and users last.
+var kinds = []kind{
+	// purge, as the broker of an orphaned service instance is usually gone as well
+	{name: "service instance", path: "/v3/service_instances", deleteQuery: "?purge=true"},
+	{name: "service broker", path: "/v3/service_brokers"},
+	{name: "space", path: "/v3/spaces"},
+	{name: "organization", path: "/v3/organizations"},
+	{name: "organization quota", path: "/v3/organization_quotas", unlabeled: true},
+	{name: "security group", path: "/v3/security_groups", unlabeled: true},
+	{name: "isolation segment", path: "/v3/isolation_segments"},
+	{name: "domain", path: "/v3/domains"},
+	{name: "buildpack", path: "/v3/buildpacks"},
+	{name: "user", path: "/v3/users"},
+}
+
+// NamePattern matches the names generated by random_name.CATSRandomName and by the suite setup for
+// each of prefixes, e.g. "CATS-3-APP-0123456789abcdef", and domains whose first label is such a name.
+func NamePattern(prefixes ...string) *regexp.Regexp {
+	var quoted []string
+	for _, prefix := range prefixes {
+		quoted = append(quoted, regexp.QuoteMeta(prefix))
+	}
+	return regexp.MustCompile(`(?i)^(` + strings.Join(quoted, "|") + `)-\d+-[a-z0-9_-]+-[0-9a-f]{16}(\.|$)`)
+}
+
+// Filter selects the resources to find. Names and LabelSelector are ignored when nil or empty.
+type Filter struct {
+	Names *regexp.Regexp
+
+	// LabelSelector is a Cloud Controller label selector such as run_metadata.Selector(runID).
+	LabelSelector string
+
+	// CreatedBefore and CreatedAfter are ignored when zero.
+	CreatedBefore time.Time
+	CreatedAfter  time.Time
+}
+
+func (f Filter) matches(r Resource) bool {
+	if f.Names != nil && !f.Names.MatchString(r.Name) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !r.CreatedAt.Before(f.CreatedBefore) {
+		return false
+	}
+	if !f.CreatedAfter.IsZero() && r.CreatedAt.Before(f.CreatedAfter) {
+		return false
+	}
+	return true
+}
+
+type resource struct {
+	GUID      string    `json:"guid"`
+	Name      string    `json:"name"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Find lists the resources matching any of the filters, each once, in the order they have to be
+// deleted in. Kinds without metadata are not listed for filters with a label selector.
+func Find(client *cc_client.Client, filters ...Filter) ([]Resource, error) {
+	var found []Resource
+	for _, k := range kinds {
+		seen := map[string]bool{}
+		for _, filter := range filters {
+			path := k.path
+			if filter.LabelSelector != "" {
+				if k.unlabeled {
+					continue
+				}
+				path += "?label_selector=" + url.QueryEscape(filter.LabelSelector)
+			}
+
+			resources, err := cc_client.GetAll[resource](client, path)
+			if err != nil {
+				return nil, fmt.Errorf("could not list %ss: %w", k.name, err)
+			}
+
+			for _, r := range resources {
+				name := r.Name
+				if k.name == "user" {
+					name = r.Username
+				}
+
+				candidate := Resource{Kind: k.name, GUID: r.GUID, Name: name, CreatedAt: r.CreatedAt}
+				if !seen[r.GUID] && filter.matches(candidate) {
+					seen[r.GUID] = true
+					found = append(found, candidate)
+				}
+			}
+		}
+	}
+
+	return found, nil
+}
+
+// Delete deletes resources in the given order, which should be the one returned by Find. Users are
+// deleted from the UAA as well. It carries on after failures and returns all of them.
+func Delete(client *cc_client.Client, resources []Resource, deleted func(Resource)) error {
+	var errs error
+	for _, r := range resources {
+		err := deleteResource(client, r)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("could not delete %s %s: %w", r.Kind, r.Name, err))
+			continue
+		}
+
+		if deleted != nil {
+			deleted(r)
+		}
+	}
+	return errs
+}
+
+func deleteResource(client *cc_client.Client, r Resource) error {
+	for _, k := range kinds {
+		if k.name != r.Kind {
+			continue
+		}
+
+		err := client.Delete(k.path + "/" + r.GUID + k.deleteQuery)
+		if err != nil || r.Kind != "user" {
+			return err
+		}
+
+		uaaURL, err := client.UAAURL()
+		if err != nil {
+			return err
+		}
+		return client.Delete(uaaURL + "/Users/" + r.GUID)
+	}
+	return fmt.Errorf("unknown kind of resource '%s'", r.Kind)
+}
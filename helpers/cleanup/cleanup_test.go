@@ -0,0 +1,163 @@
+package cleanup_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/cleanup"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NamePattern", func() {
+	It("matches generated names for each of the prefixes", func() {
+		pattern := NamePattern("CATS", "MY-CATS")
+
+		Expect(pattern.MatchString("CATS-3-APP-0123456789abcdef")).To(BeTrue())
+		Expect(pattern.MatchString("MY-CATS-1-ORG-0123456789abcdef")).To(BeTrue())
+		Expect(pattern.MatchString("cats-1-user-0123456789abcdef")).To(BeTrue())
+		Expect(pattern.MatchString("CATS-2-DOMAIN-0123456789abcdef.example.com")).To(BeTrue())
+
+		Expect(pattern.MatchString("CATS-APP")).To(BeFalse())
+		Expect(pattern.MatchString("OTHER-1-ORG-0123456789abcdef")).To(BeFalse())
+		Expect(pattern.MatchString("example.com")).To(BeFalse())
+	})
+})
+
+var _ = Describe("Find and Delete", func() {
+	var (
+		server    *httptest.Server
+		client    *cc_client.Client
+		now       time.Time
+		deleted   []string
+		requested []string
+	)
+
+	BeforeEach(func() {
+		now = time.Now().UTC().Truncate(time.Second)
+		deleted = nil
+		requested = nil
+
+		createdAt := func(age time.Duration) string {
+			return now.Add(-age).Format(time.RFC3339)
+		}
+		listed := map[string]string{
+			"/v3/service_instances": fmt.Sprintf(`{"guid": "si-guid", "name": "CATS-1-SVCINS-0123456789abcdef", "created_at": %q}`, createdAt(48*time.Hour)),
+			"/v3/organizations": fmt.Sprintf(`{"guid": "old-org-guid", "name": "CATS-1-ORG-0123456789abcdef", "created_at": %q},
+				{"guid": "new-org-guid", "name": "CATS-2-ORG-fedcba9876543210", "created_at": %q},
+				{"guid": "other-org-guid", "name": "system", "created_at": %q}`, createdAt(48*time.Hour), createdAt(time.Minute), createdAt(48*time.Hour)),
+			"/v3/users": fmt.Sprintf(`{"guid": "user-guid", "username": "CATS-1-USER-0123456789abcdef", "created_at": %q}`, createdAt(48*time.Hour)),
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"links": {"uaa": {"href": "%s/uaa"}}}`, server.URL)
+		})
+		mux.HandleFunc("GET /v3/{kind}", func(w http.ResponseWriter, r *http.Request) {
+			requested = append(requested, r.URL.RequestURI())
+			fmt.Fprintf(w, `{"pagination": {"next": null}, "resources": [%s]}`, listed[r.URL.Path])
+		})
+		recordDeletion := func(w http.ResponseWriter, r *http.Request) {
+			deleted = append(deleted, r.URL.RequestURI())
+			w.WriteHeader(http.StatusNoContent)
+		}
+		mux.HandleFunc("DELETE /v3/{kind}/{guid}", recordDeletion)
+		mux.HandleFunc("DELETE /uaa/Users/{guid}", recordDeletion)
+		mux.HandleFunc("DELETE /v3/organizations/undeletable-org-guid", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"errors": [{"detail": "org is in use"}]}`)
+		})
+		server = httptest.NewServer(mux)
+
+		client = cc_client.New(server.URL, "bearer some-token", false)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("finds the resources with matching names created before a time, in deletion order", func() {
+		resources, err := Find(client, Filter{
+			Names:         NamePattern("CATS"),
+			CreatedBefore: now.Add(-24 * time.Hour),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(resources).To(Equal([]Resource{
+			{Kind: "service instance", GUID: "si-guid", Name: "CATS-1-SVCINS-0123456789abcdef", CreatedAt: now.Add(-48 * time.Hour)},
+			{Kind: "organization", GUID: "old-org-guid", Name: "CATS-1-ORG-0123456789abcdef", CreatedAt: now.Add(-48 * time.Hour)},
+			{Kind: "user", GUID: "user-guid", Name: "CATS-1-USER-0123456789abcdef", CreatedAt: now.Add(-48 * time.Hour)},
+		}))
+	})
+
+	It("finds the resources created after a time", func() {
+		resources, err := Find(client, Filter{
+			Names:        NamePattern("CATS"),
+			CreatedAfter: now.Add(-time.Hour),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(resources).To(HaveLen(1))
+		Expect(resources[0].GUID).To(Equal("new-org-guid"))
+	})
+
+	It("finds the resources with a label selector, whatever their names", func() {
+		resources, err := Find(client, Filter{LabelSelector: "cats.cloudfoundry.org/run-id=some-run"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(resources).To(HaveLen(5))
+		Expect(requested).To(ContainElement("/v3/organizations?label_selector=cats.cloudfoundry.org%2Frun-id%3Dsome-run"))
+		Expect(requested).NotTo(ContainElement(HavePrefix("/v3/organization_quotas")))
+		Expect(requested).NotTo(ContainElement(HavePrefix("/v3/security_groups")))
+	})
+
+	It("finds the resources matching any of the filters once, in deletion order", func() {
+		resources, err := Find(client,
+			Filter{LabelSelector: "cats.cloudfoundry.org/run-id=some-run"},
+			Filter{Names: NamePattern("CATS"), CreatedAfter: now.Add(-time.Hour)},
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		var guids []string
+		for _, r := range resources {
+			guids = append(guids, r.GUID)
+		}
+		Expect(guids).To(Equal([]string{"si-guid", "old-org-guid", "new-org-guid", "other-org-guid", "user-guid"}))
+		Expect(requested).To(ContainElement("/v3/security_groups"), "kinds without metadata are found by the other filters")
+	})
+
+	It("deletes the resources in order, purging service instances and deleting users from the UAA", func() {
+		resources, err := Find(client, Filter{Names: NamePattern("CATS"), CreatedBefore: now.Add(-24 * time.Hour)})
+		Expect(err).NotTo(HaveOccurred())
+
+		var reported []string
+		Expect(Delete(client, resources, func(r Resource) {
+			reported = append(reported, r.GUID)
+		})).To(Succeed())
+
+		Expect(deleted).To(Equal([]string{
+			"/v3/service_instances/si-guid?purge=true",
+			"/v3/organizations/old-org-guid",
+			"/v3/users/user-guid",
+			"/uaa/Users/user-guid",
+		}))
+		Expect(reported).To(Equal([]string{"si-guid", "old-org-guid", "user-guid"}))
+	})
+
+	It("carries on after failures and returns all of them", func() {
+		err := Delete(client, []Resource{
+			{Kind: "organization", GUID: "undeletable-org-guid", Name: "CATS-1-ORG-0123456789abcdef"},
+			{Kind: "bucket", GUID: "bucket-guid", Name: "CATS-1-BUCKET-0123456789abcdef"},
+			{Kind: "space", GUID: "space-guid", Name: "CATS-1-SPACE-0123456789abcdef"},
+		}, nil)
+
+		Expect(err).To(MatchError(ContainSubstring("could not delete organization CATS-1-ORG-0123456789abcdef")))
+		Expect(err).To(MatchError(ContainSubstring("org is in use")))
+		Expect(err).To(MatchError(ContainSubstring("unknown kind of resource 'bucket'")))
+		Expect(deleted).To(Equal([]string{"/v3/spaces/space-guid"}))
+	})
+})
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// AutoValue can be given for any include_* flag, and for credhub_mode, to have it resolved by
+// probing the platform when the suite starts (see the capabilities package). Until then,
+// include flags are false and credhub_mode is "".
+const AutoValue = "auto"
+
+func supportsAuto(key string) bool {
+	return strings.HasPrefix(key, "include_") || key == "credhub_mode"
+}
+
+func isAutoValue(value interface{}) bool {
+	s, ok := value.(string)
+	return ok && strings.EqualFold(strings.TrimSpace(s), AutoValue)
+}
+
+// extractAutoValues replaces the "auto" values in the merged config with the unresolved value
+// of the key and records them on config.
+func extractAutoValues(values map[string]interface{}, config *config) {
+	for key, value := range values {
+		if supportsAuto(key) && isAutoValue(value) {
+			values[key] = unresolvedAutoValue(key)
+			config.addAutoKey(key)
+		}
+	}
+}
+
+func unresolvedAutoValue(key string) interface{} {
+	if key == "credhub_mode" {
+		return ""
+	}
+	return false
+}
+
+func (c *config) addAutoKey(key string) {
+	if !slices.Contains(c.autoKeys, key) {
+		c.autoKeys = append(c.autoKeys, key)
+	}
+}
+
+func (c *config) removeAutoKey(key string) {
+	c.autoKeys = slices.DeleteFunc(c.autoKeys, func(k string) bool { return k == key })
+}
+
+// GetAutoKeys returns the config keys that were set to "auto" and have not been resolved yet.
+func (c *config) GetAutoKeys() []string {
+	keys := append([]string{}, c.autoKeys...)
+	slices.Sort(keys)
+	return keys
+}
+
+// ResolveAuto sets config keys that were "auto" to the given values, e.g. {"include_docker": true}.
+func (c *config) ResolveAuto(values map[string]interface{}) error {
+	configValue := reflect.ValueOf(c).Elem()
+	configType := configValue.Type()
+	for key, value := range values {
+		if !supportsAuto(key) {
+			return fmt.Errorf("'%s' cannot be resolved automatically", key)
+		}
+
+		field, ok := fieldForKey(configType, key)
+		if !ok {
+			return fmt.Errorf("unknown config key '%s'", key)
+		}
+
+		contents, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		resolved := reflect.New(field.Type.Elem())
+		err = json.Unmarshal(contents, resolved.Interface())
+		if err != nil {
+			return fmt.Errorf("invalid value for '%s': %w", key, err)
+		}
+
+		configValue.FieldByIndex(field.Index).Set(resolved)
+		c.removeAutoKey(key)
+	}
+
+	return nil
+}
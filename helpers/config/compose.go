@@ -0,0 +1,242 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// ExtendsKey lists the config files (relative to the extending file) that a config file is layered on top of.
+const ExtendsKey = "extends"
+
+const defaultSource = "default"
+
+// composeConfigFiles reads the colon separated list of JSON or YAML config files in paths, along with the files
+// they extend, and deep-merges them in order. It returns the merged values and, for every value,
+// the file that set it.
+func composeConfigFiles(paths string) (map[string]interface{}, map[string]string, error) {
+	merged := map[string]interface{}{}
+	sources := map[string]string{}
+
+	if paths == "" {
+		return nil, nil, fmt.Errorf("no config file given")
+	}
+
+	for _, path := range filepath.SplitList(paths) {
+		err := mergeConfigFile(path, merged, sources, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return merged, sources, nil
+}
+
+func mergeConfigFile(path string, merged map[string]interface{}, sources map[string]string, extendedBy []string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	for _, p := range extendedBy {
+		if p == absPath {
+			return fmt.Errorf("config file %s extends itself via %s", path, strings.Join(extendedBy, " -> "))
+		}
+	}
+
+	values, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	extends, err := extendedFiles(path, values[ExtendsKey])
+	if err != nil {
+		return err
+	}
+	delete(values, ExtendsKey)
+
+	for _, base := range extends {
+		err = mergeConfigFile(base, merged, sources, append(extendedBy, absPath))
+		if err != nil {
+			return err
+		}
+	}
+
+	deepMerge(merged, values, sources, path, "")
+	return nil
+}
+
+func isYAMLFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yml" || ext == ".yaml"
+}
+
+func readConfigFile(path string) (map[string]interface{}, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]interface{}
+	if isYAMLFile(path) {
+		err = yaml.Unmarshal(contents, &values)
+	} else {
+		// UseNumber keeps integers such as timeouts intact when the merged config is re-encoded.
+		decoder := json.NewDecoder(bytes.NewReader(contents))
+		decoder.UseNumber()
+		err = decoder.Decode(&values)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+
+	return values, nil
+}
+
+// extendedFiles accepts either a single path or a list of paths, resolving relative paths
+// against the directory of the file that declares them.
+func extendedFiles(path string, extends interface{}) ([]string, error) {
+	var paths []string
+	switch value := extends.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		paths = []string{value}
+	case []interface{}:
+		for _, item := range value {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: '%s' must be a path or a list of paths", path, ExtendsKey)
+			}
+			paths = append(paths, s)
+		}
+	default:
+		return nil, fmt.Errorf("%s: '%s' must be a path or a list of paths", path, ExtendsKey)
+	}
+
+	for i, p := range paths {
+		if !filepath.IsAbs(p) {
+			paths[i] = filepath.Join(filepath.Dir(path), p)
+		}
+	}
+
+	return paths, nil
+}
+
+// deepMerge copies src into dst, merging nested objects key by key rather than replacing them.
+func deepMerge(dst, src map[string]interface{}, sources map[string]string, source, prefix string) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		// Secret references are replaced rather than merged, see secretKeys.
+		if srcIsMap && dstIsMap && !slices.Contains(secretKeys, prefix+key) {
+			deepMerge(dstMap, srcMap, sources, source, prefix+key+".")
+			continue
+		}
+
+		dst[key] = value
+		clearSources(sources, prefix+key)
+		sources[prefix+key] = source
+	}
+}
+
+// clearSources forgets where the nested values of key came from once key itself is replaced.
+func clearSources(sources map[string]string, key string) {
+	for k := range sources {
+		if strings.HasPrefix(k, key+".") {
+			delete(sources, k)
+		}
+	}
+}
+
+// EffectiveValue is a single resolved config value and where it came from: "default",
+// the config file that set it, or the environment variable that overrode it.
+type EffectiveValue struct {
+	Key    string
+	Value  interface{}
+	Source string
+}
+
+// NewEffectiveConfig loads the config in the same way as NewCatsConfig and returns every resolved
+// value, with nested objects flattened to dotted keys (e.g. "reporter_config.honeycomb_dataset").
+// Secrets are replaced with RedactedValue.
+// Validation errors are returned alongside the values so that they can still be inspected.
+func NewEffectiveConfig(path string) ([]EffectiveValue, error) {
+	config, loadErr := NewConfig(path)
+
+	contents, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.UseNumber()
+	var values map[string]interface{}
+	err = decoder.Decode(&values)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range config.autoKeys {
+		values[key] = AutoValue
+	}
+
+	var effective []EffectiveValue
+	for _, key := range configKeys() {
+		effective = append(effective, flattenEffectiveValue(key, values[key], config.sources)...)
+	}
+
+	return effective, loadErr
+}
+
+func flattenEffectiveValue(key string, value interface{}, sources map[string]string) []EffectiveValue {
+	if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+		var effective []EffectiveValue
+		for _, nestedKey := range sortedMapKeys(nested) {
+			effective = append(effective, flattenEffectiveValue(key+"."+nestedKey, nested[nestedKey], sources)...)
+		}
+		return effective
+	}
+
+	if s, ok := value.(string); ok && s != "" && slices.Contains(redactedKeys, key) {
+		value = RedactedValue
+	}
+
+	return []EffectiveValue{{Key: key, Value: value, Source: sourceOf(key, sources)}}
+}
+
+// sourceOf returns the source of key, falling back to the source of the object containing it.
+func sourceOf(key string, sources map[string]string) string {
+	for {
+		if source, ok := sources[key]; ok {
+			return source
+		}
+
+		i := strings.LastIndex(key, ".")
+		if i < 0 {
+			return defaultSource
+		}
+		key = key[:i]
+	}
+}
+
+// configKeys returns the top level config keys in the order they are declared.
+func configKeys() []string {
+	var keys []string
+	configType := reflect.TypeOf(config{})
+	for i := 0; i < configType.NumField(); i++ {
+		if key := jsonKey(configType.Field(i)); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,161 @@
+package config_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	cfg "github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Composing config files", func() {
+	var configDir string
+
+	writeJSON := func(name string, values map[string]interface{}) string {
+		path := filepath.Join(configDir, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+
+		contents, err := json.Marshal(values)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(path, contents, 0644)).To(Succeed())
+		return path
+	}
+
+	BeforeEach(func() {
+		configDir = GinkgoT().TempDir()
+
+		writeJSON("shared/base.json", map[string]interface{}{
+			"admin_user":          "admin",
+			"admin_password":      "admin",
+			"skip_ssl_validation": true,
+			"use_http":            false,
+			"skip_dns_validation": true,
+			"include_apps":        true,
+			"default_timeout":     30,
+			"timeout_scale":       1,
+			"reporter_config": map[string]interface{}{
+				"honeycomb_dataset": "cats",
+				"custom_tags":       map[string]interface{}{"team": "some-team"},
+			},
+		})
+	})
+
+	It("layers a file on top of the file it extends", func() {
+		path := writeJSON("envs/foundation-1.json", map[string]interface{}{
+			"extends":         "../shared/base.json",
+			"api":             "api." + BoshLiteDomain,
+			"apps_domain":     "cf-app." + BoshLiteDomain,
+			"default_timeout": 60,
+		})
+
+		config, err := cfg.NewCatsConfig(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.GetApiEndpoint()).To(Equal("api." + BoshLiteDomain))
+		Expect(config.GetAdminUser()).To(Equal("admin"))
+		Expect(config.GetIncludeApps()).To(BeTrue())
+		Expect(config.DefaultTimeoutDuration()).To(Equal(60 * time.Second))
+	})
+
+	It("deep-merges nested objects", func() {
+		path := writeJSON("envs/foundation-1.json", map[string]interface{}{
+			"extends":     []string{"../shared/base.json"},
+			"api":         "api." + BoshLiteDomain,
+			"apps_domain": "cf-app." + BoshLiteDomain,
+			"reporter_config": map[string]interface{}{
+				"custom_tags": map[string]interface{}{"foundation": "foundation-1"},
+			},
+		})
+
+		config, err := cfg.NewCatsConfig(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.GetReporterConfig().HoneyCombDataset).To(Equal("cats"))
+		Expect(config.GetReporterConfig().CustomTags).To(Equal(map[string]interface{}{
+			"team":       "some-team",
+			"foundation": "foundation-1",
+		}))
+	})
+
+	It("merges a colon separated list of files in order", func() {
+		overlay := writeJSON("overlay.json", map[string]interface{}{
+			"api":          "api." + BoshLiteDomain,
+			"apps_domain":  "cf-app." + BoshLiteDomain,
+			"include_apps": false,
+		})
+
+		config, err := cfg.NewCatsConfig(strings.Join([]string{filepath.Join(configDir, "shared/base.json"), overlay}, ":"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.GetIncludeApps()).To(BeFalse())
+		Expect(config.GetAdminUser()).To(Equal("admin"))
+	})
+
+	It("loads YAML config files", func() {
+		path := filepath.Join(configDir, "envs/foundation-1.yml")
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(`
+extends: ../shared/base.json
+api: api.`+BoshLiteDomain+`
+apps_domain: cf-app.`+BoshLiteDomain+`
+default_timeout: 45
+stacks:
+  - cflinuxfs4
+  - cflinuxfs5
+reporter_config:
+  honeycomb_write_key: some-key
+`), 0644)).To(Succeed())
+
+		config, err := cfg.NewCatsConfig(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.GetApiEndpoint()).To(Equal("api." + BoshLiteDomain))
+		Expect(config.DefaultTimeoutDuration()).To(Equal(45 * time.Second))
+		Expect(config.GetStacks()).To(Equal([]string{"cflinuxfs4", "cflinuxfs5"}))
+		Expect(config.GetReporterConfig().HoneyCombWriteKey).To(Equal("some-key"))
+		Expect(config.GetReporterConfig().HoneyCombDataset).To(Equal("cats"))
+	})
+
+	It("validates the merged result", func() {
+		path := writeJSON("envs/foundation-1.json", map[string]interface{}{
+			"extends":     "../shared/base.json",
+			"apps_domain": "cf-app." + BoshLiteDomain,
+		})
+
+		_, err := cfg.NewCatsConfig(path)
+		Expect(err).To(MatchError("* 'api' must not be null"))
+	})
+
+	It("rejects files that extend themselves", func() {
+		writeJSON("a.json", map[string]interface{}{"extends": "b.json"})
+		path := writeJSON("b.json", map[string]interface{}{"extends": "a.json"})
+
+		_, err := cfg.NewCatsConfig(path)
+		Expect(err).To(MatchError(ContainSubstring("extends itself")))
+	})
+
+	Describe("NewEffectiveConfig", func() {
+		It("reports where each value came from", func() {
+			path := writeJSON("envs/foundation-1.json", map[string]interface{}{
+				"extends":     "../shared/base.json",
+				"api":         "api." + BoshLiteDomain,
+				"apps_domain": "cf-app." + BoshLiteDomain,
+			})
+			setEnv("CATS_INCLUDE_APPS", "false")
+
+			values, err := cfg.NewEffectiveConfig(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			sources := map[string]string{}
+			for _, value := range values {
+				sources[value.Key] = value.Source
+			}
+			Expect(sources).To(HaveKeyWithValue("api", path))
+			Expect(sources).To(HaveKeyWithValue("admin_user", filepath.Join(configDir, "envs/../shared/base.json")))
+			Expect(sources).To(HaveKeyWithValue("reporter_config.honeycomb_dataset", filepath.Join(configDir, "envs/../shared/base.json")))
+			Expect(sources).To(HaveKeyWithValue("include_apps", "$CATS_INCLUDE_APPS"))
+			Expect(sources).To(HaveKeyWithValue("include_ssh", "default"))
+		})
+	})
+})
@@ -17,6 +17,11 @@ type CatsConfig interface {
 	GetIncludeIPv6() bool
 	GetIncludeInternetDependent() bool
 	GetIncludePrivateDockerRegistry() bool
+	GetIncludeRevisions() bool
+	GetIncludeSidecars() bool
+	GetIncludeMetadata() bool
+	GetIncludeQuotas() bool
+	GetIncludeRoleMatrix() bool
 	GetIncludeRouteServices() bool
 	GetIncludeRouting() bool
 	GetIncludeZipkin() bool
@@ -68,6 +73,7 @@ type CatsConfig interface {
 	GetUserOrigin() string
 	GetExistingClient() string
 	GetExistingClientSecret() string
+	UsesClientCredentials() bool
 	GetGoBuildpackName() string
 	GetHwcBuildpackName() string
 	GetInternalCCAddress() string
@@ -90,6 +96,8 @@ type CatsConfig interface {
 	GetDynamicASGsEnabled() bool
 	GetCommaDelimitedASGsEnabled() bool
 	GetReadinessHealthChecksEnabled() bool
+	GetDryRun() bool
+	GetFailOnLeakedResources() bool
 	Protocol() string
 
 	GetStacks() []string
@@ -105,6 +113,10 @@ type CatsConfig interface {
 	GetVolumeServiceBrokerName() string
 
 	GetReporterConfig() reporterConfig
+	GetSecrets() []string
+
+	GetAutoKeys() []string
+	ResolveAuto(values map[string]interface{}) error
 
 	AsyncServiceOperationTimeoutDuration() time.Duration
 	BrokerStartTimeoutDuration() time.Duration
@@ -114,6 +126,8 @@ type CatsConfig interface {
 	GetScaledTimeout(time.Duration) time.Duration
 	LongCurlTimeoutDuration() time.Duration
 	SleepTimeoutDuration() time.Duration
+	GetTimeoutOverrides() map[string]map[string]int
+	ForSuite(suite string) CatsConfig
 
 	GetPublicDockerAppImage() string
 	GetCatnipDockerAppImage() string
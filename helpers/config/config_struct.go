@@ -6,8 +6,8 @@ import (
 	"fmt"
 	"net"
 	"net/url"
-	"os"
 	"path/filepath"
+	"reflect"
 	"time"
 )
 
@@ -25,6 +25,12 @@ type config struct {
 	AdminPassword *string `json:"admin_password"`
 	AdminUser     *string `json:"admin_user"`
 
+	AdminClient       *string `json:"admin_client"`
+	AdminClientSecret *string `json:"admin_client_secret"`
+
+	ExistingClient       *string `json:"existing_client"`
+	ExistingClientSecret *string `json:"existing_client_secret"`
+
 	ExistingUser         *string `json:"existing_user"`
 	ExistingUserPassword *string `json:"existing_user_password"`
 	ShouldKeepUser       *bool   `json:"keep_user_at_suite_end"`
@@ -54,6 +60,8 @@ type config struct {
 
 	TimeoutScale *float64 `json:"timeout_scale"`
 
+	TimeoutOverrides map[string]map[string]int `json:"timeout_overrides"`
+
 	BinaryBuildpackName     *string `json:"binary_buildpack_name"`
 	GoBuildpackName         *string `json:"go_buildpack_name"`
 	HwcBuildpackName        *string `json:"hwc_buildpack_name"`
@@ -85,6 +93,11 @@ type config struct {
 	IncludeInternetDependent                *bool `json:"include_internet_dependent"`
 	IncludeIsolationSegments                *bool `json:"include_isolation_segments"`
 	IncludePrivateDockerRegistry            *bool `json:"include_private_docker_registry"`
+	IncludeRevisions                        *bool `json:"include_revisions"`
+	IncludeSidecars                         *bool `json:"include_sidecars"`
+	IncludeMetadata                         *bool `json:"include_metadata"`
+	IncludeQuotas                           *bool `json:"include_quotas"`
+	IncludeRoleMatrix                       *bool `json:"include_role_matrix"`
 	IncludeRouteServices                    *bool `json:"include_route_services"`
 	IncludeRouting                          *bool `json:"include_routing"`
 	IncludeRoutingIsolationSegments         *bool `json:"include_routing_isolation_segments"`
@@ -133,11 +146,22 @@ type config struct {
 	NamePrefix *string `json:"name_prefix"`
 
 	ReporterConfig *reporterConfig `json:"reporter_config"`
+
+	StrictConfigDecoding *bool `json:"strict_config_decoding"`
+
+	DryRun                *bool `json:"dry_run"`
+	FailOnLeakedResources *bool `json:"fail_on_leaked_resources"`
+
+	envVars     map[string]string
+	sources     map[string]string
+	unknownKeys []string
+	autoKeys    []string
 }
 
 type reporterConfig struct {
 	HoneyCombWriteKey string                 `json:"honeycomb_write_key"`
 	HoneyCombDataset  string                 `json:"honeycomb_dataset"`
+	HoneyCombAPIHost  string                 `json:"honeycomb_api_host"`
 	CustomTags        map[string]interface{} `json:"custom_tags"`
 }
 
@@ -160,6 +184,13 @@ func ptrToFloat(f float64) *float64 {
 }
 
 func getDefaults() config {
+	defaults.AdminUser = ptrToString("")
+	defaults.AdminPassword = ptrToString("")
+	defaults.AdminClient = ptrToString("")
+	defaults.AdminClientSecret = ptrToString("")
+	defaults.ExistingClient = ptrToString("")
+	defaults.ExistingClientSecret = ptrToString("")
+
 	defaults.IsolationSegmentName = ptrToString("")
 	defaults.IsolationSegmentDomain = ptrToString("")
 
@@ -197,6 +228,11 @@ func getDefaults() config {
 	defaults.IncludeTCPIsolationSegments = ptrToBool(false)
 	defaults.IncludeRoutingIsolationSegments = ptrToBool(false)
 	defaults.IncludePrivateDockerRegistry = ptrToBool(false)
+	defaults.IncludeRevisions = ptrToBool(false)
+	defaults.IncludeSidecars = ptrToBool(false)
+	defaults.IncludeMetadata = ptrToBool(false)
+	defaults.IncludeQuotas = ptrToBool(false)
+	defaults.IncludeRoleMatrix = ptrToBool(false)
 	defaults.IncludeRouteServices = ptrToBool(false)
 	defaults.IncludeSSO = ptrToBool(false)
 	defaults.IncludeSecurityGroups = ptrToBool(false)
@@ -245,6 +281,8 @@ func getDefaults() config {
 
 	defaults.TimeoutScale = ptrToFloat(2.0)
 
+	defaults.TimeoutOverrides = map[string]map[string]int{}
+
 	defaults.ArtifactsDirectory = ptrToString(filepath.Join("..", "results"))
 
 	defaults.PrivateDockerRegistryImage = ptrToString("")
@@ -263,6 +301,11 @@ func getDefaults() config {
 
 	defaults.Stacks = &[]string{"cflinuxfs4"}
 
+	defaults.StrictConfigDecoding = ptrToBool(true)
+
+	defaults.DryRun = ptrToBool(false)
+	defaults.FailOnLeakedResources = ptrToBool(false)
+
 	return defaults
 }
 
@@ -286,6 +329,11 @@ func validateConfig(config *config) error {
 
 	}
 
+	err = validateClients(config)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+
 	err = validateApiEndpoint(config)
 	if err != nil {
 		errs = errors.Join(errs, err)
@@ -361,6 +409,16 @@ func validateConfig(config *config) error {
 		errs = errors.Join(errs, err)
 	}
 
+	err = validateTimeoutOverrides(config)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+
+	err = validateKnownKeys(config)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+
 	if config.UseHttp == nil {
 		errs = errors.Join(errs, fmt.Errorf("* 'use_http' must not be null"))
 	}
@@ -385,6 +443,12 @@ func validateConfig(config *config) error {
 	if config.ArtifactsDirectory == nil {
 		errs = errors.Join(errs, fmt.Errorf("* 'artifacts_directory' must not be null"))
 	}
+	if config.DryRun == nil {
+		errs = errors.Join(errs, fmt.Errorf("* 'dry_run' must not be null"))
+	}
+	if config.FailOnLeakedResources == nil {
+		errs = errors.Join(errs, fmt.Errorf("* 'fail_on_leaked_resources' must not be null"))
+	}
 	if config.AsyncServiceOperationTimeout == nil {
 		errs = errors.Join(errs, fmt.Errorf("* 'async_service_operation_timeout' must not be null"))
 	}
@@ -469,6 +533,21 @@ func validateConfig(config *config) error {
 	if config.IncludePrivateDockerRegistry == nil {
 		errs = errors.Join(errs, fmt.Errorf("* 'include_private_docker_registry' must not be null"))
 	}
+	if config.IncludeRevisions == nil {
+		errs = errors.Join(errs, fmt.Errorf("* 'include_revisions' must not be null"))
+	}
+	if config.IncludeSidecars == nil {
+		errs = errors.Join(errs, fmt.Errorf("* 'include_sidecars' must not be null"))
+	}
+	if config.IncludeMetadata == nil {
+		errs = errors.Join(errs, fmt.Errorf("* 'include_metadata' must not be null"))
+	}
+	if config.IncludeQuotas == nil {
+		errs = errors.Join(errs, fmt.Errorf("* 'include_quotas' must not be null"))
+	}
+	if config.IncludeRoleMatrix == nil {
+		errs = errors.Join(errs, fmt.Errorf("* 'include_role_matrix' must not be null"))
+	}
 	if config.IncludeRouteServices == nil {
 		errs = errors.Join(errs, fmt.Errorf("* 'include_route_services' must not be null"))
 	}
@@ -605,6 +684,10 @@ func validateAppsDomain(config *config) error {
 }
 
 func validateAdminUser(config *config) error {
+	if config.usesAdminClient() {
+		return nil
+	}
+
 	if config.AdminUser == nil {
 		return fmt.Errorf("* 'admin_user' must not be null")
 	}
@@ -617,6 +700,10 @@ func validateAdminUser(config *config) error {
 }
 
 func validateAdminPassword(config *config) error {
+	if config.usesAdminClient() {
+		return nil
+	}
+
 	if config.AdminPassword == nil {
 		return fmt.Errorf("* 'admin_password' must not be null")
 	}
@@ -628,6 +715,36 @@ func validateAdminPassword(config *config) error {
 	return nil
 }
 
+// validateClients checks that UAA clients are configured with their secrets, and that the specs
+// do not run as both an existing user and a client.
+func validateClients(config *config) error {
+	var errs error
+	for _, client := range []struct {
+		key, secretKey string
+		name, secret   *string
+	}{
+		{"admin_client", "admin_client_secret", config.AdminClient, config.AdminClientSecret},
+		{"existing_client", "existing_client_secret", config.ExistingClient, config.ExistingClientSecret},
+	} {
+		if client.name == nil || client.secret == nil {
+			errs = errors.Join(errs, fmt.Errorf("* '%s' and '%s' must not be null", client.key, client.secretKey))
+			continue
+		}
+		if (*client.name == "") != (*client.secret == "") {
+			errs = errors.Join(errs, fmt.Errorf("* Invalid configuration: '%s' and '%s' must be provided together", client.key, client.secretKey))
+		}
+	}
+	if errs != nil {
+		return errs
+	}
+
+	if config.UsesClientCredentials() && config.UseExistingUser != nil && *config.UseExistingUser {
+		return fmt.Errorf("* Invalid configuration: 'existing_client' cannot be used together with 'use_existing_user'")
+	}
+
+	return nil
+}
+
 func validatePublicDockerAppImage(config *config) error {
 	if config.PublicDockerAppImage == nil {
 		return fmt.Errorf("* 'public_docker_app_image' must not be null")
@@ -810,21 +927,47 @@ func validateTimeoutScale(config *config) error {
 func load(path string, config *config) error {
 	err := loadConfigFromPath(path, config)
 	if err != nil {
-		return fmt.Errorf("* Failed to unmarshal: %w", err)
+		return err
 	}
 
-	return validateConfig(config)
+	envErr := loadConfigFromEnv(config)
+	validationErr := attributeErrorsToEnvVars(validateConfig(config), config.envVars)
+
+	return errors.Join(envErr, validationErr)
 }
 
-func loadConfigFromPath(path string, config interface{}) error {
-	configFile, err := os.Open(path)
+func loadConfigFromPath(path string, config *config) error {
+	values, sources, err := composeConfigFiles(path)
+	if err != nil {
+		return fmt.Errorf("* Failed to unmarshal: %w", err)
+	}
+	config.sources = sources
+
+	err = resolveSecrets(values, sources)
 	if err != nil {
 		return err
 	}
-	defer configFile.Close()
 
-	decoder := json.NewDecoder(configFile)
-	return decoder.Decode(config)
+	extractAutoValues(values, config)
+
+	contents, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("* Failed to unmarshal: %w", err)
+	}
+
+	err = json.Unmarshal(contents, config)
+	if err != nil {
+		return fmt.Errorf("* Failed to unmarshal: %w", err)
+	}
+
+	var rawConfig map[string]json.RawMessage
+	err = json.Unmarshal(contents, &rawConfig)
+	if err != nil {
+		return fmt.Errorf("* Failed to unmarshal: %w", err)
+	}
+	config.unknownKeys = findUnknownKeys(rawConfig, reflect.TypeOf(*config), "")
+
+	return nil
 }
 
 func (c config) GetScaledTimeout(timeout time.Duration) time.Duration {
@@ -1016,6 +1159,26 @@ func (c *config) GetIncludeRouteServices() bool {
 	return *c.IncludeRouteServices
 }
 
+func (c *config) GetIncludeRevisions() bool {
+	return *c.IncludeRevisions
+}
+
+func (c *config) GetIncludeSidecars() bool {
+	return *c.IncludeSidecars
+}
+
+func (c *config) GetIncludeMetadata() bool {
+	return *c.IncludeMetadata
+}
+
+func (c *config) GetIncludeQuotas() bool {
+	return *c.IncludeQuotas
+}
+
+func (c *config) GetIncludeRoleMatrix() bool {
+	return *c.IncludeRoleMatrix
+}
+
 func (c *config) GetIncludeRouting() bool {
 	return *c.IncludeRouting
 }
@@ -1048,6 +1211,14 @@ func (c *config) GetReadinessHealthChecksEnabled() bool {
 	return *c.ReadinessHealthChecksEnabled
 }
 
+func (c *config) GetDryRun() bool {
+	return *c.DryRun
+}
+
+func (c *config) GetFailOnLeakedResources() bool {
+	return *c.FailOnLeakedResources
+}
+
 func (c *config) GetIncludeServices() bool {
 	return *c.IncludeServices
 }
@@ -1241,19 +1412,29 @@ func (c *config) GetVolumeServiceBrokerName() string {
 }
 
 func (c *config) GetAdminClient() string {
-	return ""
+	return *c.AdminClient
 }
 
 func (c *config) GetAdminClientSecret() string {
-	return ""
+	return *c.AdminClientSecret
 }
 
 func (c *config) GetExistingClient() string {
-	return ""
+	return *c.ExistingClient
 }
 
 func (c *config) GetExistingClientSecret() string {
-	return ""
+	return *c.ExistingClientSecret
+}
+
+// UsesClientCredentials reports whether the specs run as the UAA client existing_client rather than
+// as a user.
+func (c *config) UsesClientCredentials() bool {
+	return c.GetExistingClient() != "" && c.GetExistingClientSecret() != ""
+}
+
+func (c *config) usesAdminClient() bool {
+	return c.AdminClient != nil && c.AdminClientSecret != nil && *c.AdminClient != "" && *c.AdminClientSecret != ""
 }
 
 func (c *config) GetReporterConfig() reporterConfig {
@@ -39,6 +39,12 @@ type testConfig struct {
 	TimeoutScale *float64 `json:"timeout_scale,omitempty"`
 
 	// optional
+	AdminClient          *string `json:"admin_client,omitempty"`
+	AdminClientSecret    *string `json:"admin_client_secret,omitempty"`
+	ExistingClient       *string `json:"existing_client,omitempty"`
+	ExistingClientSecret *string `json:"existing_client_secret,omitempty"`
+	UseExistingUser      *bool   `json:"use_existing_user,omitempty"`
+
 	PrivateDockerRegistryImage    *string `json:"private_docker_registry_image,omitempty"`
 	PrivateDockerRegistryUsername *string `json:"private_docker_registry_username,omitempty"`
 	PrivateDockerRegistryPassword *string `json:"private_docker_registry_password,omitempty"`
@@ -71,6 +77,11 @@ type testConfig struct {
 	IncludeInternetDependent        *bool `json:"include_internet_dependent,omitempty"`
 	IncludeIsolationSegments        *bool `json:"include_isolation_segments,omitempty"`
 	IncludePrivateDockerRegistry    *bool `json:"include_private_docker_registry,omitempty"`
+	IncludeRevisions                *bool `json:"include_revisions,omitempty"`
+	IncludeSidecars                 *bool `json:"include_sidecars,omitempty"`
+	IncludeMetadata                 *bool `json:"include_metadata,omitempty"`
+	IncludeQuotas                   *bool `json:"include_quotas,omitempty"`
+	IncludeRoleMatrix               *bool `json:"include_role_matrix,omitempty"`
 	IncludeRouteServices            *bool `json:"include_route_services,omitempty"`
 	IncludeRouting                  *bool `json:"include_routing,omitempty"`
 	IncludeRoutingIsolationSegments *bool `json:"include_routing_isolation_segments,omitempty"`
@@ -159,6 +170,11 @@ type nullConfig struct {
 	IncludeFileBasedServiceBindings *bool `json:"include_file_based_service_bindings"`
 	IncludeInternetDependent        *bool `json:"include_internet_dependent"`
 	IncludePrivateDockerRegistry    *bool `json:"include_private_docker_registry"`
+	IncludeRevisions                *bool `json:"include_revisions"`
+	IncludeSidecars                 *bool `json:"include_sidecars"`
+	IncludeMetadata                 *bool `json:"include_metadata"`
+	IncludeQuotas                   *bool `json:"include_quotas"`
+	IncludeRoleMatrix               *bool `json:"include_role_matrix"`
 	IncludeRouteServices            *bool `json:"include_route_services"`
 	IncludeRouting                  *bool `json:"include_routing"`
 	IncludeSSO                      *bool `json:"include_sso"`
@@ -202,6 +218,7 @@ type nullConfig struct {
 type testReporterConfig struct {
 	HoneyCombWriteKey string                 `json:"honeycomb_write_key"`
 	HoneyCombDataset  string                 `json:"honeycomb_dataset"`
+	HoneyCombAPIHost  string                 `json:"honeycomb_api_host,omitempty"`
 	CustomTags        map[string]interface{} `json:"custom_tags"`
 }
 
@@ -288,6 +305,11 @@ var _ = Describe("Config", func() {
 		Expect(config.GetIncludeFileBasedServiceBindings()).To(BeFalse())
 		Expect(config.GetIncludeIPv6()).To(BeFalse())
 		Expect(config.GetIncludeInternetDependent()).To(BeFalse())
+		Expect(config.GetIncludeRevisions()).To(BeFalse())
+		Expect(config.GetIncludeSidecars()).To(BeFalse())
+		Expect(config.GetIncludeMetadata()).To(BeFalse())
+		Expect(config.GetIncludeQuotas()).To(BeFalse())
+		Expect(config.GetIncludeRoleMatrix()).To(BeFalse())
 		Expect(config.GetIncludeRouteServices()).To(BeFalse())
 		Expect(config.GetIncludeContainerNetworking()).To(BeFalse())
 		Expect(config.GetIncludeSecurityGroups()).To(BeFalse())
@@ -321,6 +343,9 @@ var _ = Describe("Config", func() {
 		Expect(testReporterConfig.HoneyCombWriteKey).To(Equal(""))
 
 		Expect(config.GetUseExistingUser()).To(Equal(false))
+		Expect(config.GetExistingClient()).To(Equal(""))
+		Expect(config.GetExistingClientSecret()).To(Equal(""))
+		Expect(config.UsesClientCredentials()).To(BeFalse())
 		Expect(config.GetConfigurableTestPassword()).To(Equal(""))
 		Expect(config.GetShouldKeepUser()).To(Equal(false))
 
@@ -421,6 +446,11 @@ var _ = Describe("Config", func() {
 			Expect(err.Error()).To(ContainSubstring("'include_file_based_service_bindings' must not be null"))
 			Expect(err.Error()).To(ContainSubstring("'include_internet_dependent' must not be null"))
 			Expect(err.Error()).To(ContainSubstring("'include_private_docker_registry' must not be null"))
+			Expect(err.Error()).To(ContainSubstring("'include_revisions' must not be null"))
+			Expect(err.Error()).To(ContainSubstring("'include_sidecars' must not be null"))
+			Expect(err.Error()).To(ContainSubstring("'include_metadata' must not be null"))
+			Expect(err.Error()).To(ContainSubstring("'include_quotas' must not be null"))
+			Expect(err.Error()).To(ContainSubstring("'include_role_matrix' must not be null"))
 			Expect(err.Error()).To(ContainSubstring("'include_route_services' must not be null"))
 			Expect(err.Error()).To(ContainSubstring("'include_routing' must not be null"))
 			Expect(err.Error()).To(ContainSubstring("'include_container_networking' must not be null"))
@@ -483,6 +513,11 @@ var _ = Describe("Config", func() {
 			testCfg.IncludeInternetDependent = ptrToBool(true)
 			testCfg.IncludeIsolationSegments = ptrToBool(true)
 			testCfg.IncludePrivateDockerRegistry = ptrToBool(true)
+			testCfg.IncludeRevisions = ptrToBool(true)
+			testCfg.IncludeSidecars = ptrToBool(true)
+			testCfg.IncludeMetadata = ptrToBool(true)
+			testCfg.IncludeQuotas = ptrToBool(true)
+			testCfg.IncludeRoleMatrix = ptrToBool(true)
 			testCfg.IncludeRouteServices = ptrToBool(true)
 			testCfg.IncludeRouting = ptrToBool(false)
 			testCfg.IncludeRoutingIsolationSegments = ptrToBool(true)
@@ -548,6 +583,11 @@ var _ = Describe("Config", func() {
 			Expect(config.GetIncludeInternetDependent()).To(BeTrue())
 			Expect(config.GetIncludeIsolationSegments()).To(BeTrue())
 			Expect(config.GetIncludePrivateDockerRegistry()).To(BeTrue())
+			Expect(config.GetIncludeRevisions()).To(BeTrue())
+			Expect(config.GetIncludeSidecars()).To(BeTrue())
+			Expect(config.GetIncludeMetadata()).To(BeTrue())
+			Expect(config.GetIncludeQuotas()).To(BeTrue())
+			Expect(config.GetIncludeRoleMatrix()).To(BeTrue())
 			Expect(config.GetIncludeRouteServices()).To(BeTrue())
 			Expect(config.GetIncludeRouting()).To(BeFalse())
 			Expect(config.GetIncludeRoutingIsolationSegments()).To(BeTrue())
@@ -781,6 +821,7 @@ var _ = Describe("Config", func() {
 			reporterConfig := &testReporterConfig{
 				HoneyCombWriteKey: "some-write-key",
 				HoneyCombDataset:  "some-dataset",
+				HoneyCombAPIHost:  "http://localhost:8080",
 			}
 			testCfg.ReporterConfig = reporterConfig
 		})
@@ -792,6 +833,7 @@ var _ = Describe("Config", func() {
 			testReporterConfig := config.GetReporterConfig()
 			Expect(testReporterConfig.HoneyCombWriteKey).To(Equal("some-write-key"))
 			Expect(testReporterConfig.HoneyCombDataset).To(Equal("some-dataset"))
+			Expect(testReporterConfig.HoneyCombAPIHost).To(Equal("http://localhost:8080"))
 		})
 		Context("when the reporter config includes custom tags", func() {
 			BeforeEach(func() {
@@ -1062,6 +1104,63 @@ var _ = Describe("Config", func() {
 		})
 	})
 
+	Describe("client credentials", func() {
+		BeforeEach(func() {
+			testCfg.AdminClient = ptrToString("admin-client")
+			testCfg.AdminClientSecret = ptrToString("admin-client-secret")
+			testCfg.ExistingClient = ptrToString("cats-client")
+			testCfg.ExistingClientSecret = ptrToString("cats-client-secret")
+			testCfg.SkipDNSValidation = ptrToBool(true)
+		})
+
+		It("runs the specs as the existing client", func() {
+			c, err := cfg.NewCatsConfig(tmpFilePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.GetExistingClient()).To(Equal("cats-client"))
+			Expect(c.GetExistingClientSecret()).To(Equal("cats-client-secret"))
+			Expect(c.UsesClientCredentials()).To(BeTrue())
+			Expect(c.GetSecrets()).To(ContainElements("admin-client-secret", "cats-client-secret"))
+		})
+
+		Context("when the admin user and password are blank", func() {
+			BeforeEach(func() {
+				testCfg.AdminUser = ptrToString("")
+				testCfg.AdminPassword = ptrToString("")
+			})
+
+			It("does not require them", func() {
+				_, err := cfg.NewCatsConfig(tmpFilePath)
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when a client is missing its secret", func() {
+			BeforeEach(func() {
+				testCfg.AdminClientSecret = nil
+				testCfg.ExistingClientSecret = nil
+			})
+
+			It("returns an error", func() {
+				_, err := cfg.NewCatsConfig(tmpFilePath)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("'admin_client' and 'admin_client_secret' must be provided together"))
+				Expect(err.Error()).To(ContainSubstring("'existing_client' and 'existing_client_secret' must be provided together"))
+			})
+		})
+
+		Context("when an existing user is used as well", func() {
+			BeforeEach(func() {
+				testCfg.UseExistingUser = ptrToBool(true)
+			})
+
+			It("returns an error", func() {
+				_, err := cfg.NewCatsConfig(tmpFilePath)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("'existing_client' cannot be used together with 'use_existing_user'"))
+			})
+		})
+	})
+
 	Describe("GetAdminPassword", func() {
 		It("returns the admin password", func() {
 			c, err := cfg.NewCatsConfig(tmpFilePath)
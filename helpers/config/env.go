@@ -0,0 +1,191 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const EnvVarPrefix = "CATS_"
+
+// EnvVarName returns the environment variable that overrides the given config key,
+// e.g. "timeout_scale" is overridden by CATS_TIMEOUT_SCALE.
+func EnvVarName(key string) string {
+	return EnvVarPrefix + strings.ToUpper(key)
+}
+
+// jsonKey returns the key a config struct field is read from, or "" if the field is not part of the config file.
+func jsonKey(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+
+	key := strings.Split(field.Tag.Get("json"), ",")[0]
+	if key == "-" {
+		return ""
+	}
+	return key
+}
+
+func loadConfigFromEnv(config *config) error {
+	var errs error
+
+	configValue := reflect.ValueOf(config).Elem()
+	configType := configValue.Type()
+	for i := 0; i < configType.NumField(); i++ {
+		key := jsonKey(configType.Field(i))
+		if key == "" {
+			continue
+		}
+
+		envVar := EnvVarName(key)
+		rawValue, ok := os.LookupEnv(envVar)
+		if !ok {
+			continue
+		}
+
+		if supportsAuto(key) && isAutoValue(rawValue) {
+			config.addAutoKey(key)
+			rawValue = fmt.Sprint(unresolvedAutoValue(key))
+		} else {
+			config.removeAutoKey(key)
+		}
+
+		err := setFromEnvValue(configValue.Field(i), rawValue)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("* Invalid value for '%s' in $%s: %s", key, envVar, err))
+			continue
+		}
+
+		if config.envVars == nil {
+			config.envVars = map[string]string{}
+		}
+		config.envVars[key] = envVar
+
+		if config.sources == nil {
+			config.sources = map[string]string{}
+		}
+		clearSources(config.sources, key)
+		config.sources[key] = "$" + envVar
+	}
+
+	return errs
+}
+
+func setFromEnvValue(field reflect.Value, rawValue string) error {
+	if field.Kind() == reflect.Map {
+		value := reflect.New(field.Type())
+		err := json.Unmarshal([]byte(rawValue), value.Interface())
+		if err != nil {
+			return fmt.Errorf("expected a JSON object: %s", err)
+		}
+		field.Set(value.Elem())
+		return nil
+	}
+
+	if field.Kind() != reflect.Ptr {
+		return fmt.Errorf("unsupported config field type %s", field.Type())
+	}
+
+	value := reflect.New(field.Type().Elem())
+	switch elem := value.Elem(); elem.Kind() {
+	case reflect.String:
+		elem.SetString(rawValue)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(rawValue))
+		if err != nil {
+			return fmt.Errorf("expected a boolean but got '%s'", rawValue)
+		}
+		elem.SetBool(b)
+	case reflect.Int:
+		i, err := strconv.Atoi(strings.TrimSpace(rawValue))
+		if err != nil {
+			return fmt.Errorf("expected an integer but got '%s'", rawValue)
+		}
+		elem.SetInt(int64(i))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(rawValue), 64)
+		if err != nil {
+			return fmt.Errorf("expected a number but got '%s'", rawValue)
+		}
+		elem.SetFloat(f)
+	case reflect.Slice:
+		if elem.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported config field type %s", field.Type())
+		}
+		elem.Set(reflect.ValueOf(parseStringList(rawValue)))
+	default:
+		err := json.Unmarshal([]byte(rawValue), value.Interface())
+		if err != nil {
+			return fmt.Errorf("expected a JSON object: %s", err)
+		}
+	}
+
+	field.Set(value)
+	return nil
+}
+
+// parseStringList accepts either a JSON array or a comma separated list, e.g. "cflinuxfs4,cflinuxfs5".
+func parseStringList(rawValue string) []string {
+	var list []string
+	if json.Unmarshal([]byte(rawValue), &list) == nil {
+		return list
+	}
+
+	list = []string{}
+	for _, item := range strings.Split(rawValue, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// attributeErrorsToEnvVars appends the environment variable to every validation error that
+// mentions a key whose value came from the environment rather than from the config file.
+func attributeErrorsToEnvVars(err error, envVars map[string]string) error {
+	if err == nil || len(envVars) == 0 {
+		return err
+	}
+
+	errs := flattenJoinedErrors(err)
+
+	keys := make([]string, 0, len(envVars))
+	for key := range envVars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var attributed error
+	for _, e := range errs {
+		for _, key := range keys {
+			if strings.Contains(e.Error(), "'"+key+"'") {
+				e = fmt.Errorf("%w (set by $%s)", e, envVars[key])
+			}
+		}
+		attributed = errors.Join(attributed, e)
+	}
+
+	return attributed
+}
+
+// flattenJoinedErrors returns the errors wrapped by err, which validateConfig builds by joining
+// each error to the errors before it.
+func flattenJoinedErrors(err error) []error {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, e := range joined.Unwrap() {
+		errs = append(errs, flattenJoinedErrors(e)...)
+	}
+	return errs
+}
@@ -0,0 +1,144 @@
+package config_test
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	cfg "github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func setEnv(name, value string) {
+	Expect(os.Setenv(name, value)).To(Succeed())
+	DeferCleanup(os.Unsetenv, name)
+}
+
+var _ = Describe("Environment variable overrides", func() {
+	var configPath string
+
+	BeforeEach(func() {
+		envCfg := testConfig{}
+		envCfg.ApiEndpoint = ptrToString("api." + BoshLiteDomain)
+		envCfg.AdminUser = ptrToString("admin")
+		envCfg.AdminPassword = ptrToString("admin")
+		envCfg.SkipSSLValidation = ptrToBool(true)
+		envCfg.AppsDomain = ptrToString("cf-app." + BoshLiteDomain)
+		envCfg.UseHttp = ptrToBool(false)
+		envCfg.SkipDNSValidation = ptrToBool(true)
+		envCfg.TimeoutScale = ptrToFloat(1.0)
+		envCfg.IncludeApps = ptrToBool(true)
+
+		configPath = writeConfigFile(&envCfg)
+		DeferCleanup(os.Remove, configPath)
+	})
+
+	It("returns the config file values when no overrides are set", func() {
+		config, err := cfg.NewCatsConfig(configPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.GetIncludeApps()).To(BeTrue())
+		Expect(config.GetScaledTimeout(time.Second)).To(Equal(time.Second))
+	})
+
+	It("overrides booleans", func() {
+		setEnv("CATS_INCLUDE_APPS", "false")
+		setEnv("CATS_INCLUDE_SERVICES", "true")
+
+		config, err := cfg.NewCatsConfig(configPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.GetIncludeApps()).To(BeFalse())
+		Expect(config.GetIncludeServices()).To(BeTrue())
+	})
+
+	It("overrides integers and floats", func() {
+		setEnv("CATS_CF_PUSH_TIMEOUT", "600")
+		setEnv("CATS_TIMEOUT_SCALE", "2.5")
+
+		config, err := cfg.NewCatsConfig(configPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.CfPushTimeoutDuration()).To(Equal(1500 * time.Second))
+	})
+
+	It("overrides strings", func() {
+		setEnv("CATS_ADMIN_USER", "other-admin")
+
+		config, err := cfg.NewCatsConfig(configPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.GetAdminUser()).To(Equal("other-admin"))
+	})
+
+	It("accepts comma separated string lists", func() {
+		setEnv("CATS_STACKS", "cflinuxfs4, cflinuxfs5")
+
+		config, err := cfg.NewCatsConfig(configPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.GetStacks()).To(Equal([]string{"cflinuxfs4", "cflinuxfs5"}))
+	})
+
+	It("accepts JSON string lists", func() {
+		setEnv("CATS_STACKS", `["cflinuxfs5"]`)
+
+		config, err := cfg.NewCatsConfig(configPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.GetStacks()).To(Equal([]string{"cflinuxfs5"}))
+	})
+
+	It("accepts JSON objects", func() {
+		setEnv("CATS_REPORTER_CONFIG", `{"honeycomb_dataset": "some-dataset"}`)
+
+		config, err := cfg.NewCatsConfig(configPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.GetReporterConfig().HoneyCombDataset).To(Equal("some-dataset"))
+	})
+
+	It("accepts 'auto' for include flags", func() {
+		setEnv("CATS_INCLUDE_APPS", "auto")
+		setEnv("CATS_INCLUDE_DOCKER", "auto")
+
+		config, err := cfg.NewCatsConfig(configPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.GetIncludeApps()).To(BeFalse())
+		Expect(config.GetAutoKeys()).To(Equal([]string{"include_apps", "include_docker"}))
+
+		Expect(config.ResolveAuto(map[string]interface{}{"include_apps": true, "include_docker": false})).To(Succeed())
+		Expect(config.GetIncludeApps()).To(BeTrue())
+		Expect(config.GetAutoKeys()).To(BeEmpty())
+	})
+
+	Context("when a value cannot be parsed", func() {
+		It("names the environment variable", func() {
+			setEnv("CATS_INCLUDE_APPS", "yes please")
+			setEnv("CATS_DEFAULT_TIMEOUT", "30s")
+
+			_, err := cfg.NewCatsConfig(configPath)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("* Invalid value for 'include_apps' in $CATS_INCLUDE_APPS: expected a boolean but got 'yes please'"))
+			Expect(err.Error()).To(ContainSubstring("* Invalid value for 'default_timeout' in $CATS_DEFAULT_TIMEOUT: expected an integer but got '30s'"))
+		})
+	})
+
+	Context("when an overridden value fails validation", func() {
+		It("names the environment variable", func() {
+			setEnv("CATS_TIMEOUT_SCALE", "-1")
+
+			_, err := cfg.NewCatsConfig(configPath)
+			Expect(err).To(MatchError("* 'timeout_scale' must be greater than zero (set by $CATS_TIMEOUT_SCALE)"))
+		})
+
+		It("names the environment variable on the line of each error", func() {
+			setEnv("CATS_ADMIN_USER", "")
+			setEnv("CATS_ADMIN_PASSWORD", "")
+			setEnv("CATS_TIMEOUT_SCALE", "-1")
+
+			_, err := cfg.NewCatsConfig(configPath)
+			Expect(err).To(HaveOccurred())
+			Expect(strings.Split(err.Error(), "\n")).To(ConsistOf(
+				"* Invalid configuration: 'admin_user' must be provided (set by $CATS_ADMIN_USER)",
+				"* Invalid configuration: 'admin_password' must be provided (set by $CATS_ADMIN_PASSWORD)",
+				"* 'timeout_scale' must be greater than zero (set by $CATS_TIMEOUT_SCALE)",
+			))
+		})
+	})
+})
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// requiredKeys have no default and must be provided by every config.
+var requiredKeys = []string{"api", "apps_domain"}
+
+// adminUserKeys must be provided by configs without adminClientKeys, see validateAdminUser and
+// validateAdminPassword.
+var (
+	adminUserKeys   = []string{"admin_user", "admin_password"}
+	adminClientKeys = []string{"admin_client", "admin_client_secret"}
+)
+
+// conditionalRequirement mirrors a validate* rule of the form "these keys must be provided if ...".
+type conditionalRequirement struct {
+	when     map[string]interface{}
+	required []string
+}
+
+var conditionalRequirements = []conditionalRequirement{
+	{when: flagEnabled("include_private_docker_registry"), required: []string{"private_docker_registry_image", "private_docker_registry_username", "private_docker_registry_password"}},
+	{when: flagEnabled("include_isolation_segments"), required: []string{"isolation_segment_name"}},
+	{when: flagEnabled("include_routing_isolation_segments"), required: []string{"isolation_segment_name", "isolation_segment_domain"}},
+	{when: flagEnabled("include_tcp_isolation_segments"), required: []string{"isolation_segment_name"}},
+	{when: flagEnabled("include_volume_services"), required: []string{"volume_service_name", "volume_service_plan_name"}},
+	{
+		when: map[string]interface{}{
+			"properties": map[string]interface{}{"credhub_mode": map[string]interface{}{"enum": []string{CredhubAssistedMode, CredhubNonAssistedMode}}},
+			"required":   []string{"credhub_mode"},
+		},
+		required: []string{"credhub_client", "credhub_secret"},
+	},
+}
+
+// JSONSchema returns a JSON Schema describing the config file, derived from the config struct,
+// its defaults and the validate* rules. It describes a config with strict_config_decoding enabled,
+// so unknown keys are not allowed.
+func JSONSchema() ([]byte, error) {
+	defaults, err := defaultValues()
+	if err != nil {
+		return nil, err
+	}
+
+	properties := map[string]interface{}{
+		ExtendsKey: map[string]interface{}{
+			"description": "Config files, relative to this one, that this config is layered on top of.",
+			"oneOf": []interface{}{
+				map[string]interface{}{"type": "string"},
+				map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			},
+		},
+	}
+
+	configType := reflect.TypeOf(config{})
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		key := jsonKey(field)
+		if key == "" {
+			continue
+		}
+
+		property, err := schemaForType(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+
+		defaultValue, hasDefault := defaults[key]
+		if hasDefault && defaultValue != nil {
+			property["default"] = defaultValue
+		} else if !slices.Contains(requiredKeys, key) {
+			// Keys without a default may be left null.
+			property["type"] = []interface{}{property["type"], "null"}
+		}
+
+		if strings.HasPrefix(key, "include_") {
+			property = orAuto(property)
+		}
+
+		properties[key] = property
+	}
+
+	for _, key := range requiredKeys {
+		properties[key].(map[string]interface{})["minLength"] = 1
+	}
+	properties["api"].(map[string]interface{})["not"] = map[string]interface{}{
+		"pattern": "^[a-zA-Z][a-zA-Z0-9+.-]*://",
+	}
+	for _, key := range secretKeys {
+		properties[key] = orSecretReference(properties[key].(map[string]interface{}))
+	}
+
+	properties["timeout_scale"].(map[string]interface{})["exclusiveMinimum"] = 0
+	properties["timeout_overrides"].(map[string]interface{})["additionalProperties"] = map[string]interface{}{
+		"type":                 "object",
+		"propertyNames":        map[string]interface{}{"enum": timeoutKeys},
+		"additionalProperties": map[string]interface{}{"type": "integer", "exclusiveMinimum": 0},
+	}
+	properties["credhub_mode"].(map[string]interface{})["enum"] = []interface{}{"", CredhubAssistedMode, CredhubNonAssistedMode, AutoValue}
+
+	var rules []interface{}
+	for _, requirement := range conditionalRequirements {
+		rules = append(rules, requirement.schemaRule())
+	}
+	rules = append(rules, map[string]interface{}{
+		"if":   flagEnabled("include_tcp_isolation_segments"),
+		"then": flagEnabled("include_isolation_segments"),
+	})
+	rules = append(rules, map[string]interface{}{
+		"if":   nonEmpty(adminClientKeys),
+		"else": nonEmpty(adminUserKeys),
+	})
+
+	schema := map[string]interface{}{
+		"$schema":              JSONSchemaDraft,
+		"title":                "CATS config",
+		"type":                 "object",
+		"properties":           properties,
+		"required":             requiredKeys,
+		"additionalProperties": false,
+		"allOf":                rules,
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func (r conditionalRequirement) schemaRule() interface{} {
+	return map[string]interface{}{
+		"if":   r.when,
+		"then": nonEmpty(r.required),
+	}
+}
+
+// nonEmpty requires the string keys to be provided and not empty.
+func nonEmpty(keys []string) map[string]interface{} {
+	properties := map[string]interface{}{}
+	for _, key := range keys {
+		properties[key] = map[string]interface{}{"type": "string", "minLength": 1}
+		if slices.Contains(secretKeys, key) {
+			properties[key] = orSecretReference(properties[key].(map[string]interface{}))
+		}
+	}
+
+	return map[string]interface{}{
+		"properties": properties,
+		"required":   keys,
+	}
+}
+
+// orAuto allows "auto" in place of the value described by property, see AutoValue.
+func orAuto(property map[string]interface{}) map[string]interface{} {
+	schema := map[string]interface{}{
+		"anyOf": []interface{}{property, map[string]interface{}{"const": AutoValue}},
+	}
+	if defaultValue, ok := property["default"]; ok {
+		schema["default"] = defaultValue
+	}
+	return schema
+}
+
+// orSecretReference allows a {"from_file"}, {"from_env"} or {"from_command"} reference in place of
+// the plaintext secret described by property.
+func orSecretReference(property map[string]interface{}) map[string]interface{} {
+	reference := func(key string, value map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{key: value},
+			"required":             []string{key},
+			"additionalProperties": false,
+		}
+	}
+
+	schema := map[string]interface{}{
+		"anyOf": []interface{}{
+			property,
+			reference("from_file", map[string]interface{}{"type": "string"}),
+			reference("from_env", map[string]interface{}{"type": "string"}),
+			reference("from_command", map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "minItems": 1}),
+		},
+	}
+	if defaultValue, ok := property["default"]; ok {
+		schema["default"] = defaultValue
+	}
+	return schema
+}
+
+func flagEnabled(flag string) map[string]interface{} {
+	return map[string]interface{}{
+		"properties": map[string]interface{}{flag: map[string]interface{}{"const": true}},
+		"required":   []string{flag},
+	}
+}
+
+func schemaForType(t reflect.Type) (map[string]interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case reflect.Int:
+		return map[string]interface{}{"type": "integer"}, nil
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+	case reflect.Slice:
+		items, err := schemaForType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": items}, nil
+	case reflect.Map:
+		schema := map[string]interface{}{"type": []interface{}{"object", "null"}}
+		if t.Elem().Kind() != reflect.Interface {
+			values, err := schemaForType(t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			schema["additionalProperties"] = values
+		}
+		return schema, nil
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			key := jsonKey(t.Field(i))
+			if key == "" {
+				continue
+			}
+
+			property, err := schemaForType(t.Field(i).Type)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			properties[key] = property
+		}
+		return map[string]interface{}{"type": "object", "properties": properties, "additionalProperties": false}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported config field type %s", t)
+}
+
+func defaultValues() (map[string]interface{}, error) {
+	contents, err := json.Marshal(getDefaults())
+	if err != nil {
+		return nil, err
+	}
+
+	var defaults map[string]interface{}
+	err = json.Unmarshal(contents, &defaults)
+	return defaults, err
+}
@@ -0,0 +1,154 @@
+package config_test
+
+import (
+	"encoding/json"
+
+	cfg "github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSONSchema", func() {
+	var (
+		schema     map[string]interface{}
+		properties map[string]interface{}
+	)
+
+	BeforeEach(func() {
+		contents, err := cfg.JSONSchema()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(json.Unmarshal(contents, &schema)).To(Succeed())
+
+		properties = schema["properties"].(map[string]interface{})
+	})
+
+	It("describes every config key and rejects unknown ones", func() {
+		Expect(schema).To(HaveKeyWithValue("additionalProperties", false))
+		Expect(properties).To(HaveKey("extends"))
+		Expect(properties).To(HaveKey("include_apps"))
+		Expect(properties).To(HaveKeyWithValue("reporter_config", HaveKeyWithValue("properties", HaveKey("honeycomb_dataset"))))
+	})
+
+	It("includes the defaults", func() {
+		Expect(properties).To(HaveKeyWithValue("include_apps", HaveKeyWithValue("default", true)))
+		Expect(properties).To(HaveKeyWithValue("stacks", HaveKeyWithValue("default", ConsistOf("cflinuxfs4"))))
+	})
+
+	It("requires the keys without defaults", func() {
+		Expect(schema["required"]).To(ConsistOf("api", "apps_domain"))
+		Expect(properties).To(HaveKeyWithValue("api", HaveKeyWithValue("minLength", BeNumerically("==", 1))))
+	})
+
+	It("requires the admin user unless an admin client is provided", func() {
+		// adminRule applies the if/else rule on admin_client to a config, as a validator would.
+		adminRule := func(config map[string]interface{}) bool {
+			for _, rule := range schema["allOf"].([]interface{}) {
+				rule := rule.(map[string]interface{})
+				if _, ok := rule["else"]; !ok {
+					continue
+				}
+				if satisfies(config, rule["if"].(map[string]interface{})) {
+					return true
+				}
+				return satisfies(config, rule["else"].(map[string]interface{}))
+			}
+			Fail("no rule requires the admin user")
+			return false
+		}
+
+		clientOnly := map[string]interface{}{"api": "api.example.com", "apps_domain": "example.com", "admin_client": "client", "admin_client_secret": map[string]interface{}{"from_env": "SECRET"}}
+		Expect(satisfies(clientOnly, schema)).To(BeTrue())
+		Expect(adminRule(clientOnly)).To(BeTrue(), "a config with only an admin client is valid")
+
+		userOnly := map[string]interface{}{"api": "api.example.com", "apps_domain": "example.com", "admin_user": "admin", "admin_password": "password"}
+		Expect(adminRule(userOnly)).To(BeTrue(), "a config with only an admin user is valid")
+
+		Expect(adminRule(map[string]interface{}{"admin_client": "client", "admin_client_secret": ""})).To(BeFalse(), "an admin client without a secret does not replace the admin user")
+		Expect(adminRule(map[string]interface{}{"admin_user": "admin", "admin_password": ""})).To(BeFalse())
+	})
+
+	It("encodes the value rules", func() {
+		Expect(properties).To(HaveKeyWithValue("timeout_scale", HaveKeyWithValue("exclusiveMinimum", BeNumerically("==", 0))))
+		Expect(properties).To(HaveKeyWithValue("credhub_mode", HaveKeyWithValue("enum", ConsistOf("", "assisted", "non-assisted", "auto"))))
+	})
+
+	It("requires the isolation segment fields together", func() {
+		Expect(schema["allOf"]).To(ContainElement(map[string]interface{}{
+			"if": map[string]interface{}{
+				"properties": map[string]interface{}{"include_routing_isolation_segments": map[string]interface{}{"const": true}},
+				"required":   []interface{}{"include_routing_isolation_segments"},
+			},
+			"then": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"isolation_segment_name":   map[string]interface{}{"type": "string", "minLength": float64(1)},
+					"isolation_segment_domain": map[string]interface{}{"type": "string", "minLength": float64(1)},
+				},
+				"required": []interface{}{"isolation_segment_name", "isolation_segment_domain"},
+			},
+		}))
+	})
+
+	It("only refers to known config keys in conditional rules", func() {
+		for _, rule := range schema["allOf"].([]interface{}) {
+			for _, clause := range []string{"if", "then", "else"} {
+				subschema, ok := rule.(map[string]interface{})[clause].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				for key := range subschema["properties"].(map[string]interface{}) {
+					Expect(properties).To(HaveKey(key))
+				}
+			}
+		}
+	})
+})
+
+// satisfies reports whether value satisfies the "required", "properties", "type", "minLength" and
+// "anyOf" keywords of schema, the subset the conditional rules of the config schema use.
+func satisfies(value interface{}, schema map[string]interface{}) bool {
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		for _, alternative := range anyOf {
+			if satisfies(value, alternative.(map[string]interface{})) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch schema["type"] {
+	case "string":
+		if _, ok := value.(string); !ok {
+			return false
+		}
+	case "object":
+		if _, ok := value.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	if minLength, ok := schema["minLength"].(float64); ok {
+		if s, ok := value.(string); ok && len(s) < int(minLength) {
+			return false
+		}
+	}
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return true
+	}
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, key := range required {
+			if _, ok := object[key.(string)]; !ok {
+				return false
+			}
+		}
+	}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for key, property := range properties {
+			if v, ok := object[key]; ok && !satisfies(v, property.(map[string]interface{})) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,226 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const RedactedValue = "[REDACTED]"
+
+// secretKeys may be given either as plaintext or as a reference to where the secret is kept:
+//
+//	{"from_file": "path/to/secret"}       relative to the config file that sets it
+//	{"from_env": "SOME_ENV_VAR"}
+//	{"from_command": ["vault", "read", "-field=password", "secret/cf/admin"]}
+var secretKeys = []string{
+	"admin_password",
+	"admin_client_secret",
+	"existing_user_password",
+	"existing_client_secret",
+	"credhub_secret",
+	"private_docker_registry_password",
+}
+
+// redactedKeys are never printed, whether or not they are resolved from a reference.
+var redactedKeys = append([]string{"reporter_config.honeycomb_write_key"}, secretKeys...)
+
+// resolveSecrets replaces every secret reference in the merged config with the secret itself.
+func resolveSecrets(values map[string]interface{}, sources map[string]string) error {
+	var errs error
+	for _, key := range secretKeys {
+		reference, ok := values[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		secret, err := resolveSecret(reference, filepath.Dir(sources[key]))
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("* Failed to resolve secret '%s': %w", key, err))
+			continue
+		}
+
+		values[key] = secret
+	}
+
+	return errs
+}
+
+func resolveSecret(reference map[string]interface{}, configDir string) (string, error) {
+	if len(reference) != 1 {
+		return "", fmt.Errorf("expected exactly one of 'from_file', 'from_env' or 'from_command'")
+	}
+
+	switch {
+	case reference["from_file"] != nil:
+		path, ok := reference["from_file"].(string)
+		if !ok {
+			return "", fmt.Errorf("'from_file' must be a path")
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(configDir, path)
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(contents), "\r\n"), nil
+
+	case reference["from_env"] != nil:
+		name, ok := reference["from_env"].(string)
+		if !ok {
+			return "", fmt.Errorf("'from_env' must be the name of an environment variable")
+		}
+
+		secret, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("$%s is not set", name)
+		}
+		return secret, nil
+
+	case reference["from_command"] != nil:
+		rawCommand, ok := reference["from_command"].([]interface{})
+		if !ok || len(rawCommand) == 0 {
+			return "", fmt.Errorf("'from_command' must be a non-empty list of arguments")
+		}
+
+		command := make([]string, len(rawCommand))
+		for i, arg := range rawCommand {
+			command[i] = fmt.Sprint(arg)
+		}
+
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command(command[0], command[1:]...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		if err != nil {
+			return "", fmt.Errorf("'%s' failed: %w: %s", strings.Join(command, " "), err, strings.TrimSpace(stderr.String()))
+		}
+		return strings.TrimRight(stdout.String(), "\r\n"), nil
+	}
+
+	return "", fmt.Errorf("expected exactly one of 'from_file', 'from_env' or 'from_command'")
+}
+
+// GetSecrets returns the resolved values of every configured secret, for redaction.
+func (c *config) GetSecrets() []string {
+	honeyCombWriteKey := c.GetReporterConfig().HoneyCombWriteKey
+
+	var secrets []string
+	for _, secret := range []*string{
+		c.AdminPassword,
+		c.AdminClientSecret,
+		c.ExistingUserPassword,
+		c.ExistingClientSecret,
+		c.CredhubClientSecret,
+		c.PrivateDockerRegistryPassword,
+		&honeyCombWriteKey,
+	} {
+		if secret != nil && *secret != "" {
+			secrets = append(secrets, *secret)
+		}
+	}
+	return secrets
+}
+
+// RedactSecrets replaces every occurrence of the given secrets in text.
+func RedactSecrets(text string, secrets []string) string {
+	// Replace longer secrets first so that a secret containing another is fully redacted.
+	sorted := append([]string{}, secrets...)
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+
+	for _, secret := range sorted {
+		if secret != "" {
+			text = strings.ReplaceAll(text, secret, RedactedValue)
+		}
+	}
+	return text
+}
+
+// RedactSecretsInFile rewrites the file at path, e.g. a CF_TRACE log, with the given secrets redacted.
+// A missing file is not an error.
+func RedactSecretsInFile(path string, secrets []string) error {
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	redacted := RedactSecrets(string(contents), secrets)
+	if redacted == string(contents) {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(redacted), info.Mode())
+}
+
+// RedactConfigJSON returns configJSON, the JSON of a whole config, indented and with the values of
+// the keys that are never printed redacted. Keys keep their order.
+func RedactConfigJSON(configJSON []byte) ([]byte, error) {
+	redacted, err := redactJSONObject(configJSON, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var indented bytes.Buffer
+	err = json.Indent(&indented, redacted, "", "  ")
+	return indented.Bytes(), err
+}
+
+func redactJSONObject(object []byte, prefix string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(object))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	var redacted bytes.Buffer
+	redacted.WriteByte('{')
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := token.(string)
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case slices.Contains(redactedKeys, prefix+key) && value[0] == '"' && string(value) != `""`:
+			value, _ = json.Marshal(RedactedValue)
+		case value[0] == '{':
+			value, err = redactJSONObject(value, prefix+key+".")
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if redacted.Len() > 1 {
+			redacted.WriteByte(',')
+		}
+		keyJSON, _ := json.Marshal(key)
+		redacted.Write(keyJSON)
+		redacted.WriteByte(':')
+		redacted.Write(value)
+	}
+	redacted.WriteByte('}')
+
+	return redacted.Bytes(), nil
+}
@@ -0,0 +1,139 @@
+package config_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	cfg "github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Secrets", func() {
+	var (
+		configDir string
+		rawConfig map[string]interface{}
+	)
+
+	writeConfig := func(name string, values map[string]interface{}) string {
+		contents, err := json.Marshal(values)
+		Expect(err).NotTo(HaveOccurred())
+
+		path := filepath.Join(configDir, name)
+		Expect(os.WriteFile(path, contents, 0644)).To(Succeed())
+		return path
+	}
+
+	BeforeEach(func() {
+		configDir = GinkgoT().TempDir()
+
+		rawConfig = map[string]interface{}{
+			"api":                 "api." + BoshLiteDomain,
+			"admin_user":          "admin",
+			"admin_password":      "admin",
+			"skip_ssl_validation": true,
+			"apps_domain":         "cf-app." + BoshLiteDomain,
+			"use_http":            false,
+			"skip_dns_validation": true,
+		}
+	})
+
+	It("reads secrets from files relative to the config file", func() {
+		Expect(os.WriteFile(filepath.Join(configDir, "admin-password"), []byte("file-password\n"), 0600)).To(Succeed())
+		rawConfig["admin_password"] = map[string]interface{}{"from_file": "admin-password"}
+
+		config, err := cfg.NewCatsConfig(writeConfig("config.json", rawConfig))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.GetAdminPassword()).To(Equal("file-password"))
+	})
+
+	It("reads secrets from environment variables", func() {
+		setEnv("SOME_CREDHUB_SECRET", "env-secret")
+		rawConfig["credhub_secret"] = map[string]interface{}{"from_env": "SOME_CREDHUB_SECRET"}
+
+		config, err := cfg.NewCatsConfig(writeConfig("config.json", rawConfig))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.GetCredHubBrokerClientSecret()).To(Equal("env-secret"))
+	})
+
+	It("reads secrets from the output of a command", func() {
+		rawConfig["admin_client"] = "admin-client"
+		rawConfig["admin_client_secret"] = map[string]interface{}{"from_command": []string{"echo", "command-secret"}}
+
+		config, err := cfg.NewCatsConfig(writeConfig("config.json", rawConfig))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.GetAdminClient()).To(Equal("admin-client"))
+		Expect(config.GetAdminClientSecret()).To(Equal("command-secret"))
+	})
+
+	It("replaces rather than merges references from extended config files", func() {
+		setEnv("SOME_ADMIN_PASSWORD", "env-password")
+		rawConfig["admin_password"] = map[string]interface{}{"from_file": "does-not-exist"}
+		writeConfig("base.json", rawConfig)
+
+		config, err := cfg.NewCatsConfig(writeConfig("config.json", map[string]interface{}{
+			"extends":        "base.json",
+			"admin_password": map[string]interface{}{"from_env": "SOME_ADMIN_PASSWORD"},
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.GetAdminPassword()).To(Equal("env-password"))
+	})
+
+	It("reports secrets that cannot be resolved", func() {
+		rawConfig["admin_password"] = map[string]interface{}{"from_env": "SOME_UNSET_ENV_VAR"}
+		rawConfig["existing_user_password"] = map[string]interface{}{"from_command": []string{"false"}}
+		rawConfig["private_docker_registry_password"] = map[string]interface{}{"from_vault": "secret/cf"}
+
+		_, err := cfg.NewCatsConfig(writeConfig("config.json", rawConfig))
+		Expect(err).To(MatchError(ContainSubstring("* Failed to resolve secret 'admin_password': $SOME_UNSET_ENV_VAR is not set")))
+		Expect(err).To(MatchError(ContainSubstring("* Failed to resolve secret 'existing_user_password': 'false' failed")))
+		Expect(err).To(MatchError(ContainSubstring("* Failed to resolve secret 'private_docker_registry_password': expected exactly one of 'from_file', 'from_env' or 'from_command'")))
+	})
+
+	It("redacts secrets from the effective config", func() {
+		rawConfig["admin_password"] = map[string]interface{}{"from_command": []string{"echo", "command-password"}}
+		path := writeConfig("config.json", rawConfig)
+
+		values, err := cfg.NewEffectiveConfig(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values).To(ContainElement(cfg.EffectiveValue{Key: "admin_password", Value: cfg.RedactedValue, Source: path}))
+		Expect(values).To(ContainElement(cfg.EffectiveValue{Key: "admin_user", Value: "admin", Source: path}))
+	})
+
+	It("redacts secrets from the JSON of the whole config, keeping the order of its keys", func() {
+		configJSON := []byte(`{"api":"api.example.com","admin_password":"admin","admin_client_secret":"","reporter_config":{"honeycomb_write_key":"write-key","honeycomb_dataset":"cats"}}`)
+
+		redacted, err := cfg.RedactConfigJSON(configJSON)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(redacted)).To(Equal(`{
+  "api": "api.example.com",
+  "admin_password": "[REDACTED]",
+  "admin_client_secret": "",
+  "reporter_config": {
+    "honeycomb_write_key": "[REDACTED]",
+    "honeycomb_dataset": "cats"
+  }
+}`))
+	})
+
+	Describe("RedactSecretsInFile", func() {
+		It("replaces every configured secret in the file", func() {
+			rawConfig["credhub_secret"] = "credhub-secret"
+			config, err := cfg.NewCatsConfig(writeConfig("config.json", rawConfig))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.GetSecrets()).To(ConsistOf("admin", "credhub-secret"))
+
+			tracePath := filepath.Join(configDir, "CATS-TRACE-CATS-1.txt")
+			Expect(os.WriteFile(tracePath, []byte("password=admin&secret=credhub-secret"), 0644)).To(Succeed())
+
+			Expect(cfg.RedactSecretsInFile(tracePath, config.GetSecrets())).To(Succeed())
+			Expect(os.ReadFile(tracePath)).To(BeEquivalentTo("password=[REDACTED]&secret=[REDACTED]"))
+		})
+
+		It("ignores missing files", func() {
+			Expect(cfg.RedactSecretsInFile(filepath.Join(configDir, "missing.txt"), []string{"secret"})).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+)
+
+// timeoutKeys can be overridden per suite in timeout_overrides, e.g.
+//
+//	"timeout_overrides": {"detect": {"cf_push_timeout": 900}}
+var timeoutKeys = []string{
+	"async_service_operation_timeout",
+	"broker_start_timeout",
+	"cf_push_timeout",
+	"default_timeout",
+	"detect_timeout",
+	"long_curl_timeout",
+	"sleep_timeout",
+}
+
+func validateTimeoutOverrides(config *config) error {
+	var errs error
+	for _, suite := range sortedMapKeys(config.TimeoutOverrides) {
+		overrides := config.TimeoutOverrides[suite]
+		for _, key := range sortedMapKeys(overrides) {
+			if !slices.Contains(timeoutKeys, key) {
+				if suggestion := suggestKey(key, timeoutKeys); suggestion != "" {
+					errs = errors.Join(errs, fmt.Errorf("* Unknown timeout '%s' in 'timeout_overrides.%s', did you mean '%s'?", key, suite, suggestion))
+				} else {
+					errs = errors.Join(errs, fmt.Errorf("* Unknown timeout '%s' in 'timeout_overrides.%s'", key, suite))
+				}
+				continue
+			}
+
+			if overrides[key] <= 0 {
+				errs = errors.Join(errs, fmt.Errorf("* 'timeout_overrides.%s.%s' must be greater than zero", suite, key))
+			}
+		}
+	}
+
+	return errs
+}
+
+// ValidateTimeoutOverrideSuites checks that every suite in timeout_overrides is one of suites,
+// the labels of the test groups (e.g. "http2_routing" for "[HTTP/2 routing]").
+func ValidateTimeoutOverrideSuites(c CatsConfig, suites []string) error {
+	var errs error
+	for _, suite := range sortedMapKeys(c.GetTimeoutOverrides()) {
+		if slices.Contains(suites, suite) {
+			continue
+		}
+
+		if suggestion := suggestKey(suite, suites); suggestion != "" {
+			errs = errors.Join(errs, fmt.Errorf("* Unknown suite '%s' in 'timeout_overrides', did you mean '%s'?", suite, suggestion))
+		} else {
+			errs = errors.Join(errs, fmt.Errorf("* Unknown suite '%s' in 'timeout_overrides'", suite))
+		}
+	}
+
+	return errs
+}
+
+func (c *config) GetTimeoutOverrides() map[string]map[string]int {
+	return c.TimeoutOverrides
+}
+
+// ForSuite returns the config with the timeout_overrides for suite, the label of a test group,
+// applied.
+func (c *config) ForSuite(suite string) CatsConfig {
+	overrides, ok := c.TimeoutOverrides[suite]
+	if !ok {
+		return c
+	}
+
+	suiteConfig := *c
+	configValue := reflect.ValueOf(&suiteConfig).Elem()
+	configType := configValue.Type()
+	for i := 0; i < configType.NumField(); i++ {
+		if seconds, ok := overrides[jsonKey(configType.Field(i))]; ok {
+			configValue.Field(i).Set(reflect.ValueOf(&seconds))
+		}
+	}
+
+	return &suiteConfig
+}
+
+func sortedMapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
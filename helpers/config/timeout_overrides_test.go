@@ -0,0 +1,99 @@
+package config_test
+
+import (
+	"os"
+	"time"
+
+	cfg "github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Timeout overrides", func() {
+	var rawConfig map[string]interface{}
+
+	BeforeEach(func() {
+		rawConfig = map[string]interface{}{
+			"api":                 "api." + BoshLiteDomain,
+			"admin_user":          "admin",
+			"admin_password":      "admin",
+			"skip_ssl_validation": true,
+			"apps_domain":         "cf-app." + BoshLiteDomain,
+			"use_http":            false,
+			"skip_dns_validation": true,
+			"timeout_scale":       1,
+			"cf_push_timeout":     240,
+			"timeout_overrides": map[string]interface{}{
+				"detect":  map[string]interface{}{"cf_push_timeout": 900, "default_timeout": 60},
+				"windows": map[string]interface{}{"cf_push_timeout": 1200},
+			},
+		}
+	})
+
+	loadConfig := func() (cfg.CatsConfig, error) {
+		configPath := writeConfigFile(rawConfig)
+		DeferCleanup(os.Remove, configPath)
+
+		return cfg.NewCatsConfig(configPath)
+	}
+
+	It("applies the overrides for a suite", func() {
+		config, err := loadConfig()
+		Expect(err).NotTo(HaveOccurred())
+
+		detectConfig := config.ForSuite("detect")
+		Expect(detectConfig.CfPushTimeoutDuration()).To(Equal(900 * time.Second))
+		Expect(detectConfig.DefaultTimeoutDuration()).To(Equal(60 * time.Second))
+		Expect(detectConfig.LongCurlTimeoutDuration()).To(Equal(config.LongCurlTimeoutDuration()))
+		Expect(config.ForSuite("windows").CfPushTimeoutDuration()).To(Equal(1200 * time.Second))
+	})
+
+	It("does not change the config for other suites", func() {
+		config, err := loadConfig()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(config.ForSuite("apps").CfPushTimeoutDuration()).To(Equal(240 * time.Second))
+		Expect(config.ForSuite("detect")).NotTo(BeIdenticalTo(config))
+		Expect(config.CfPushTimeoutDuration()).To(Equal(240 * time.Second))
+	})
+
+	It("scales the overrides", func() {
+		rawConfig["timeout_scale"] = 2
+
+		config, err := loadConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.ForSuite("detect").CfPushTimeoutDuration()).To(Equal(1800 * time.Second))
+	})
+
+	It("can be set from the environment", func() {
+		setEnv("CATS_TIMEOUT_OVERRIDES", `{"services": {"broker_start_timeout": 600}}`)
+
+		config, err := loadConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.ForSuite("services").BrokerStartTimeoutDuration()).To(Equal(600 * time.Second))
+		Expect(config.ForSuite("detect").CfPushTimeoutDuration()).To(Equal(240 * time.Second))
+	})
+
+	It("rejects unknown timeouts and non-positive values", func() {
+		rawConfig["timeout_overrides"] = map[string]interface{}{
+			"detect": map[string]interface{}{"cf_push_timout": 900, "default_timeout": 0},
+		}
+
+		_, err := loadConfig()
+		Expect(err).To(MatchError(ContainSubstring("* Unknown timeout 'cf_push_timout' in 'timeout_overrides.detect', did you mean 'cf_push_timeout'?")))
+		Expect(err).To(MatchError(ContainSubstring("* 'timeout_overrides.detect.default_timeout' must be greater than zero")))
+	})
+
+	Describe("ValidateTimeoutOverrideSuites", func() {
+		It("rejects suites that are not test groups", func() {
+			config, err := loadConfig()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cfg.ValidateTimeoutOverrideSuites(config, []string{"apps", "detect", "windows"})).To(Succeed())
+			Expect(cfg.ValidateTimeoutOverrideSuites(config, []string{"apps", "detects"})).To(MatchError(
+				"* Unknown suite 'detect' in 'timeout_overrides', did you mean 'detects'?\n* Unknown suite 'windows' in 'timeout_overrides'",
+			))
+		})
+	})
+})
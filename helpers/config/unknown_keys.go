@@ -0,0 +1,145 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// findUnknownKeys returns the keys in rawConfig, including those of nested objects such as
+// reporter_config, that do not correspond to a field of configType.
+func findUnknownKeys(rawConfig map[string]json.RawMessage, configType reflect.Type, prefix string) []string {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < configType.NumField(); i++ {
+		if key := jsonKey(configType.Field(i)); key != "" {
+			fields[key] = configType.Field(i).Type
+		}
+	}
+
+	var unknown []string
+	for key, rawValue := range rawConfig {
+		fieldType, ok := fields[key]
+		if !ok {
+			unknown = append(unknown, prefix+key)
+			continue
+		}
+
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() != reflect.Struct {
+			continue
+		}
+
+		var nested map[string]json.RawMessage
+		if json.Unmarshal(rawValue, &nested) == nil {
+			unknown = append(unknown, findUnknownKeys(nested, fieldType, prefix+key+".")...)
+		}
+	}
+
+	sort.Strings(unknown)
+	return unknown
+}
+
+func validateKnownKeys(config *config) error {
+	if config.StrictConfigDecoding == nil {
+		return fmt.Errorf("* 'strict_config_decoding' must not be null")
+	}
+	if !*config.StrictConfigDecoding {
+		return nil
+	}
+
+	var errs error
+	for _, key := range config.unknownKeys {
+		suggestion := suggestKey(key, knownKeys(reflect.TypeOf(*config), key))
+		if suggestion != "" {
+			errs = errors.Join(errs, fmt.Errorf("* Unknown key '%s' in config, did you mean '%s'?", key, suggestion))
+		} else {
+			errs = errors.Join(errs, fmt.Errorf("* Unknown key '%s' in config", key))
+		}
+	}
+
+	return errs
+}
+
+// knownKeys returns the valid keys at the same nesting level as the (dotted) unknown key.
+func knownKeys(configType reflect.Type, unknownKey string) []string {
+	parents := strings.Split(unknownKey, ".")
+	parents = parents[:len(parents)-1]
+
+	prefix := ""
+	for _, parent := range parents {
+		field, ok := fieldForKey(configType, parent)
+		if !ok {
+			return nil
+		}
+		configType = field.Type
+		for configType.Kind() == reflect.Ptr {
+			configType = configType.Elem()
+		}
+		prefix += parent + "."
+	}
+
+	var keys []string
+	for i := 0; i < configType.NumField(); i++ {
+		if key := jsonKey(configType.Field(i)); key != "" {
+			keys = append(keys, prefix+key)
+		}
+	}
+	return keys
+}
+
+func fieldForKey(configType reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < configType.NumField(); i++ {
+		if jsonKey(configType.Field(i)) == key {
+			return configType.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// suggestKey returns the candidate closest to key by edit distance, as long as it is close
+// enough to plausibly be a typo.
+func suggestKey(key string, candidates []string) string {
+	maxDistance := len(key) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	suggestion := ""
+	bestDistance := maxDistance + 1
+	for _, candidate := range candidates {
+		distance := editDistance(key, candidate)
+		if distance < bestDistance {
+			suggestion = candidate
+			bestDistance = distance
+		}
+	}
+
+	return suggestion
+}
+
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
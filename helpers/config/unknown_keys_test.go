@@ -0,0 +1,75 @@
+package config_test
+
+import (
+	"os"
+
+	cfg "github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Unknown config keys", func() {
+	var rawConfig map[string]interface{}
+
+	BeforeEach(func() {
+		rawConfig = map[string]interface{}{
+			"api":                 "api." + BoshLiteDomain,
+			"admin_user":          "admin",
+			"admin_password":      "admin",
+			"skip_ssl_validation": true,
+			"apps_domain":         "cf-app." + BoshLiteDomain,
+			"use_http":            false,
+			"skip_dns_validation": true,
+		}
+	})
+
+	loadConfig := func() error {
+		configPath := writeConfigFile(rawConfig)
+		DeferCleanup(os.Remove, configPath)
+
+		_, err := cfg.NewCatsConfig(configPath)
+		return err
+	}
+
+	It("accepts a config with only known keys", func() {
+		Expect(loadConfig()).To(Succeed())
+	})
+
+	It("suggests the closest known key for a misspelled key", func() {
+		rawConfig["cf_push_timout"] = 300
+
+		Expect(loadConfig()).To(MatchError("* Unknown key 'cf_push_timout' in config, did you mean 'cf_push_timeout'?"))
+	})
+
+	It("reports keys that are not close to any known key", func() {
+		rawConfig["something_completely_different"] = true
+
+		Expect(loadConfig()).To(MatchError("* Unknown key 'something_completely_different' in config"))
+	})
+
+	It("reports unknown keys in nested objects", func() {
+		rawConfig["reporter_config"] = map[string]interface{}{
+			"honeycomb_dataset":  "some-dataset",
+			"honeycomb_write_ky": "some-key",
+		}
+
+		Expect(loadConfig()).To(MatchError("* Unknown key 'reporter_config.honeycomb_write_ky' in config, did you mean 'reporter_config.honeycomb_write_key'?"))
+	})
+
+	Context("when strict_config_decoding is false", func() {
+		It("ignores unknown keys", func() {
+			rawConfig["cf_push_timout"] = 300
+			rawConfig["strict_config_decoding"] = false
+
+			Expect(loadConfig()).To(Succeed())
+		})
+
+		It("can be disabled from the environment", func() {
+			rawConfig["cf_push_timout"] = 300
+			setEnv("CATS_STRICT_CONFIG_DECODING", "false")
+
+			Expect(loadConfig()).To(Succeed())
+		})
+	})
+})
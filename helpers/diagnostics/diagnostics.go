@@ -0,0 +1,193 @@
+package diagnostics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/logs"
+	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// DirName is the directory in the artifacts directory that holds a directory per failed spec.
+const DirName = "diagnostics"
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// SpecDir returns the directory for the diagnostics of the spec, e.g.
+// "<artifactsDir>/diagnostics/apps_Getting_instance_information-1a2b3c4d". The hash of the full
+// spec text keeps specs whose texts only differ after the first 80 characters apart.
+func SpecDir(artifactsDir string, report ginkgo.SpecReport) string {
+	text := report.FullText()
+	slug := strings.Trim(unsafeChars.ReplaceAllString(text, "_"), "_")
+	if len(slug) > 80 {
+		slug = slug[:80]
+	}
+	hash := sha256.Sum256([]byte(text))
+	return filepath.Join(artifactsDir, DirName, slug+"-"+hex.EncodeToString(hash[:4]))
+}
+
+// Collect writes what is needed to debug a failure without a live environment to a directory per
+// app in the space into dir: the app, the stats of its processes, its recent audit events, log
+// envelopes and routes, and the security groups of the space. It runs as the current cf user and
+// carries on when something can't be fetched, returning all errors.
+func Collect(dir, spaceName string, cfg config.CatsConfig) error {
+	timeout := cfg.DefaultTimeoutDuration()
+
+	spaceGUID, err := cfOutput(timeout, "space", spaceName, "--guid")
+	if err != nil {
+		return err
+	}
+
+	var apps struct {
+		Resources []struct {
+			GUID string `json:"guid"`
+			Name string `json:"name"`
+		} `json:"resources"`
+	}
+	err = curlInto(timeout, "/v3/apps?per_page=5000&space_guids="+spaceGUID, &apps)
+	if err != nil {
+		return err
+	}
+
+	securityGroups, errs := securityGroupsOf(timeout, spaceGUID)
+
+	token, err := cfOutput(timeout, "oauth-token")
+	errs = errors.Join(errs, err)
+
+	for _, app := range apps.Resources {
+		appDir := filepath.Join(dir, unsafeChars.ReplaceAllString(app.Name, "_"))
+		err := os.MkdirAll(appDir, 0755)
+		if err != nil {
+			return err
+		}
+
+		files := map[string]func() (interface{}, error){
+			"app.json": func() (interface{}, error) {
+				return curl(timeout, "/v3/apps/"+app.GUID)
+			},
+			"process_stats.json": func() (interface{}, error) {
+				return processStats(timeout, app.GUID)
+			},
+			"audit_events.json": func() (interface{}, error) {
+				return curl(timeout, "/v3/audit_events?order_by=-created_at&per_page=100&target_guids="+app.GUID)
+			},
+			"routes.json": func() (interface{}, error) {
+				return curl(timeout, "/v3/apps/"+app.GUID+"/routes")
+			},
+			"security_groups.json": func() (interface{}, error) {
+				return securityGroups, nil
+			},
+			"logs.json": func() (interface{}, error) {
+				return recentEnvelopes(app.GUID, token, cfg)
+			},
+		}
+		for name, fetch := range files {
+			contents, err := fetch()
+			if err == nil {
+				err = writeJSON(filepath.Join(appDir, name), contents)
+			}
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("%s of app %s: %w", name, app.Name, err))
+			}
+		}
+	}
+
+	return errs
+}
+
+func securityGroupsOf(timeout time.Duration, spaceGUID string) (map[string]json.RawMessage, error) {
+	groups := map[string]json.RawMessage{}
+	var errs error
+	for _, lifecycle := range []string{"running", "staging"} {
+		contents, err := curl(timeout, fmt.Sprintf("/v3/security_groups?%s_space_guids=%s", lifecycle, spaceGUID))
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("%s security groups: %w", lifecycle, err))
+			continue
+		}
+		groups[lifecycle] = contents
+	}
+	return groups, errs
+}
+
+func processStats(timeout time.Duration, appGUID string) (map[string]json.RawMessage, error) {
+	var processes struct {
+		Resources []struct {
+			GUID string `json:"guid"`
+			Type string `json:"type"`
+		} `json:"resources"`
+	}
+	err := curlInto(timeout, "/v3/apps/"+appGUID+"/processes", &processes)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := map[string]json.RawMessage{}
+	for _, process := range processes.Resources {
+		stats[process.Type], err = curl(timeout, "/v3/processes/"+process.GUID+"/stats")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+func recentEnvelopes(appGUID, token string, cfg config.CatsConfig) (json.RawMessage, error) {
+	var contents []byte
+	var err error
+	failure := gomega.InterceptGomegaFailure(func() {
+		contents, err = protojson.Marshal(logs.RecentEnvelopes(appGUID, token, cfg))
+	})
+	if failure != nil {
+		return nil, failure
+	}
+	return contents, err
+}
+
+func curl(timeout time.Duration, path string) (json.RawMessage, error) {
+	session := cf.Cf("curl", path, "--fail").Wait(timeout)
+	if session.ExitCode() != 0 {
+		return nil, fmt.Errorf("cf curl %s exited with %d: %s", path, session.ExitCode(), strings.TrimSpace(string(session.Err.Contents())))
+	}
+
+	contents := session.Out.Contents()
+	if !json.Valid(contents) {
+		return nil, fmt.Errorf("cf curl %s returned invalid JSON", path)
+	}
+	return contents, nil
+}
+
+func curlInto(timeout time.Duration, path string, result interface{}) error {
+	contents, err := curl(timeout, path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(contents, result)
+}
+
+func cfOutput(timeout time.Duration, args ...string) (string, error) {
+	session := cf.CfSilent(args...).Wait(timeout)
+	if session.ExitCode() != 0 {
+		return "", fmt.Errorf("cf %s exited with %d", args[0], session.ExitCode())
+	}
+	return strings.TrimSpace(string(session.Out.Contents())), nil
+}
+
+func writeJSON(path string, contents interface{}) error {
+	encoded, err := json.MarshalIndent(contents, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(encoded, '\n'), 0644)
+}
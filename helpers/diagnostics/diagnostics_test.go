@@ -0,0 +1,34 @@
+package diagnostics_test
+
+import (
+	"path/filepath"
+	"strings"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/diagnostics"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SpecDir", func() {
+	report := func(texts ...string) types.SpecReport {
+		return types.SpecReport{ContainerHierarchyTexts: texts[:len(texts)-1], LeafNodeText: texts[len(texts)-1]}
+	}
+
+	It("names the directory after the spec", func() {
+		dir := SpecDir("/artifacts", report("[apps]", "Getting instance information", "returns the instance's IP/port"))
+
+		Expect(filepath.Dir(dir)).To(Equal("/artifacts/diagnostics"))
+		Expect(filepath.Base(dir)).To(MatchRegexp(`^apps_Getting_instance_information_returns_the_instance_s_IP_port-[0-9a-f]{8}$`))
+	})
+
+	It("keeps long spec texts that only differ at the end apart", func() {
+		long := strings.Repeat("very ", 30)
+		first := SpecDir("/artifacts", report("[apps]", long+"first"))
+		second := SpecDir("/artifacts", report("[apps]", long+"second"))
+
+		Expect(len(filepath.Base(first))).To(BeNumerically("<=", 89))
+		Expect(first).NotTo(Equal(second))
+	})
+})
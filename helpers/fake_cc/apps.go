@@ -0,0 +1,596 @@
+package fake_cc
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+)
+
+type app struct {
+	cc_client.App
+	spaceGUID   string
+	dropletGUID string
+	revisions   int
+}
+
+type process struct {
+	cc_client.Process
+	appGUID string
+}
+
+type pkg struct {
+	cc_client.Package
+	appGUID string
+}
+
+type build struct {
+	cc_client.Build
+	appGUID     string
+	packageGUID string
+	operation   *operation
+}
+
+type droplet struct {
+	cc_client.Droplet
+	appGUID string
+}
+
+type deployment struct {
+	cc_client.Deployment
+	appGUID   string
+	sequence  int
+	operation *operation
+}
+
+type job struct {
+	GUID      string            `json:"guid"`
+	Operation string            `json:"operation"`
+	State     string            `json:"state"`
+	Errors    []cc_client.Error `json:"errors"`
+	Warnings  []cc_client.Error `json:"warnings"`
+	operation *operation
+}
+
+// operation is the progress of something asynchronous, such as a job or a build.
+type operation struct {
+	state      string
+	pollsLeft  int
+	failure    string
+	onComplete func()
+}
+
+const (
+	inProgress = "in progress"
+	succeeded  = "succeeded"
+	failed     = "failed"
+	canceled   = "canceled"
+)
+
+// startOperation starts an operation for the request that completes after the polls set with
+// SetAsyncPolls, and fails if Fail was told to fail it.
+func (s *Server) startOperation(r *http.Request, onComplete func()) *operation {
+	return &operation{state: inProgress, pollsLeft: s.asyncPolls, failure: s.asyncFailure(r), onComplete: onComplete}
+}
+
+func (o *operation) poll() {
+	if o.state != inProgress {
+		return
+	}
+	if o.pollsLeft > 0 {
+		o.pollsLeft--
+		return
+	}
+	if o.failure != "" {
+		o.state = failed
+		return
+	}
+	o.state = succeeded
+	if o.onComplete != nil {
+		o.onComplete()
+	}
+}
+
+// writeJob responds with 202 and the location of a job that follows the operation.
+func (s *Server) writeJob(w http.ResponseWriter, name string, op *operation) {
+	j := &job{GUID: newGUID(), Operation: name, Errors: []cc_client.Error{}, Warnings: []cc_client.Error{}, operation: op}
+	s.jobs[j.GUID] = j
+	w.Header().Set("Location", s.server.URL+"/v3/jobs/"+j.GUID)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) routeApps() {
+	s.mux.HandleFunc("GET /v3/apps", s.listApps)
+	s.mux.HandleFunc("POST /v3/apps", s.createApp)
+	s.mux.HandleFunc("GET /v3/apps/{guid}", s.getApp)
+	s.mux.HandleFunc("PATCH /v3/apps/{guid}", s.updateApp)
+	s.mux.HandleFunc("DELETE /v3/apps/{guid}", s.deleteApp)
+	s.mux.HandleFunc("POST /v3/apps/{guid}/actions/start", s.setAppState("STARTED"))
+	s.mux.HandleFunc("POST /v3/apps/{guid}/actions/stop", s.setAppState("STOPPED"))
+	s.mux.HandleFunc("GET /v3/apps/{guid}/droplets/current", s.getCurrentDroplet)
+	s.mux.HandleFunc("PATCH /v3/apps/{guid}/relationships/current_droplet", s.setCurrentDroplet)
+	s.mux.HandleFunc("GET /v3/apps/{guid}/processes", s.listAppProcesses)
+	s.mux.HandleFunc("POST /v3/apps/{guid}/processes/{type}/actions/scale", s.scaleProcess)
+	s.mux.HandleFunc("GET /v3/processes/{guid}", s.getProcess)
+	s.mux.HandleFunc("GET /v3/processes/{guid}/stats", s.getProcessStats)
+	s.mux.HandleFunc("POST /v3/packages", s.createPackage)
+	s.mux.HandleFunc("GET /v3/packages/{guid}", s.getPackage)
+	s.mux.HandleFunc("POST /v3/packages/{guid}/upload", s.uploadPackage)
+	s.mux.HandleFunc("POST /v3/builds", s.createBuild)
+	s.mux.HandleFunc("GET /v3/builds/{guid}", s.getBuild)
+	s.mux.HandleFunc("GET /v3/droplets/{guid}", s.getDroplet)
+	s.mux.HandleFunc("GET /v3/deployments", s.listDeployments)
+	s.mux.HandleFunc("POST /v3/deployments", s.createDeployment)
+	s.mux.HandleFunc("GET /v3/deployments/{guid}", s.getDeployment)
+	s.mux.HandleFunc("POST /v3/deployments/{guid}/actions/cancel", s.cancelDeployment)
+	s.mux.HandleFunc("POST /v3/deployments/{guid}/actions/continue", s.continueDeployment)
+	s.mux.HandleFunc("GET /v3/jobs/{guid}", s.getJob)
+}
+
+func (s *Server) renderApp(a *app) interface{} {
+	a.Links = map[string]cc_client.Link{
+		"self":      s.link("/v3/apps/" + a.GUID),
+		"space":     s.link("/v3/spaces/" + a.spaceGUID),
+		"processes": s.link("/v3/apps/" + a.GUID + "/processes"),
+	}
+	return struct {
+		cc_client.App
+		Relationships map[string]cc_client.ToOne `json:"relationships"`
+	}{a.App, map[string]cc_client.ToOne{"space": cc_client.RelationshipTo(a.spaceGUID)}}
+}
+
+func (s *Server) listApps(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var resources []interface{}
+	for _, a := range sortedBy(s.apps, func(a *app) string { return a.GUID }) {
+		if matches(query, "names", a.Name) && matches(query, "guids", a.GUID) && matches(query, "space_guids", a.spaceGUID) && matchesLabels(query, a.Metadata) {
+			resources = append(resources, s.renderApp(a))
+		}
+	}
+	s.writeList(w, r, resources, len(resources))
+}
+
+func (s *Server) createApp(w http.ResponseWriter, r *http.Request) {
+	var request cc_client.CreateAppRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+	if request.Relationships.Space.Data == nil || s.spaces[request.Relationships.Space.Data.GUID] == nil {
+		writeUnprocessable(w, "Invalid space. Ensure that the space exists and you have access to it.")
+		return
+	}
+	spaceGUID := request.Relationships.Space.Data.GUID
+	for _, existing := range s.apps {
+		if existing.spaceGUID == spaceGUID && existing.Name == request.Name {
+			writeUnprocessable(w, "App with the name '"+request.Name+"' already exists.")
+			return
+		}
+	}
+
+	a := &app{spaceGUID: spaceGUID}
+	a.GUID = newGUID()
+	a.Name = request.Name
+	a.State = "STOPPED"
+	a.CreatedAt = now()
+	a.Lifecycle = cc_client.Lifecycle{Type: "buildpack"}
+	if request.Lifecycle != nil {
+		a.Lifecycle = *request.Lifecycle
+	}
+	mergeMetadata(&a.Metadata, request.Metadata)
+	s.apps[a.GUID] = a
+
+	p := &process{appGUID: a.GUID}
+	p.GUID = newGUID()
+	p.Type = "web"
+	p.Instances = 1
+	p.MemoryInMB = 1024
+	p.DiskInMB = 1024
+	s.processes[p.GUID] = p
+
+	writeJSON(w, http.StatusCreated, s.renderApp(a))
+}
+
+func (s *Server) getApp(w http.ResponseWriter, r *http.Request) {
+	a, ok := mustFind(w, s.apps, r.PathValue("guid"), "App")
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderApp(a))
+}
+
+func (s *Server) updateApp(w http.ResponseWriter, r *http.Request) {
+	a, ok := mustFind(w, s.apps, r.PathValue("guid"), "App")
+	if !ok {
+		return
+	}
+	var request struct {
+		Name     string                    `json:"name"`
+		Metadata *cc_client.MetadataUpdate `json:"metadata"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	if request.Name != "" {
+		a.Name = request.Name
+	}
+	updateMetadata(&a.Metadata, request.Metadata)
+	writeJSON(w, http.StatusOK, s.renderApp(a))
+}
+
+func (s *Server) deleteApp(w http.ResponseWriter, r *http.Request) {
+	guid := r.PathValue("guid")
+	if _, ok := mustFind(w, s.apps, guid, "App"); !ok {
+		return
+	}
+	s.writeJob(w, "app.delete", s.startOperation(r, func() {
+		delete(s.apps, guid)
+		for processGUID, p := range s.processes {
+			if p.appGUID == guid {
+				delete(s.processes, processGUID)
+			}
+		}
+	}))
+}
+
+func (s *Server) setAppState(state string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		a, ok := mustFind(w, s.apps, r.PathValue("guid"), "App")
+		if !ok {
+			return
+		}
+		if state == "STARTED" && a.dropletGUID == "" {
+			writeUnprocessable(w, "Assign a droplet before starting this app.")
+			return
+		}
+		a.State = state
+		writeJSON(w, http.StatusOK, s.renderApp(a))
+	}
+}
+
+func (s *Server) getCurrentDroplet(w http.ResponseWriter, r *http.Request) {
+	a, ok := mustFind(w, s.apps, r.PathValue("guid"), "App")
+	if !ok {
+		return
+	}
+	d, ok := mustFind(w, s.droplets, a.dropletGUID, "Droplet")
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, d.Droplet)
+}
+
+func (s *Server) setCurrentDroplet(w http.ResponseWriter, r *http.Request) {
+	a, ok := mustFind(w, s.apps, r.PathValue("guid"), "App")
+	if !ok {
+		return
+	}
+	var request cc_client.ToOne
+	if !readJSON(w, r, &request) {
+		return
+	}
+	if request.Data == nil || s.droplets[request.Data.GUID] == nil || s.droplets[request.Data.GUID].appGUID != a.GUID {
+		writeUnprocessable(w, "Unable to assign current droplet. Ensure the droplet exists and belongs to this app.")
+		return
+	}
+	a.dropletGUID = request.Data.GUID
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": request.Data})
+}
+
+func (s *Server) listAppProcesses(w http.ResponseWriter, r *http.Request) {
+	guid := r.PathValue("guid")
+	if _, ok := mustFind(w, s.apps, guid, "App"); !ok {
+		return
+	}
+	query := r.URL.Query()
+	var resources []interface{}
+	for _, p := range sortedBy(s.processes, func(p *process) string { return p.Type }) {
+		if p.appGUID == guid && matches(query, "types", p.Type) {
+			resources = append(resources, p.Process)
+		}
+	}
+	s.writeList(w, r, resources, len(resources))
+}
+
+func (s *Server) scaleProcess(w http.ResponseWriter, r *http.Request) {
+	var found *process
+	for _, p := range s.processes {
+		if p.appGUID == r.PathValue("guid") && p.Type == r.PathValue("type") {
+			found = p
+		}
+	}
+	if found == nil {
+		writeNotFound(w, "Process")
+		return
+	}
+	var request cc_client.ScaleRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+	if request.Instances != nil {
+		found.Instances = *request.Instances
+	}
+	if request.MemoryInMB != nil {
+		found.MemoryInMB = *request.MemoryInMB
+	}
+	if request.DiskInMB != nil {
+		found.DiskInMB = *request.DiskInMB
+	}
+	writeJSON(w, http.StatusAccepted, found.Process)
+}
+
+func (s *Server) getProcess(w http.ResponseWriter, r *http.Request) {
+	p, ok := mustFind(w, s.processes, r.PathValue("guid"), "Process")
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, p.Process)
+}
+
+// getProcessStats reports every instance of the process as running if its app is started.
+func (s *Server) getProcessStats(w http.ResponseWriter, r *http.Request) {
+	p, ok := mustFind(w, s.processes, r.PathValue("guid"), "Process")
+	if !ok {
+		return
+	}
+	state := "DOWN"
+	if s.apps[p.appGUID].State == "STARTED" {
+		state = "RUNNING"
+	}
+	instances := []cc_client.ProcessInstance{}
+	for i := 0; i < p.Instances; i++ {
+		instances = append(instances, cc_client.ProcessInstance{Index: i, Type: p.Type, State: state})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"resources": instances})
+}
+
+func (s *Server) createPackage(w http.ResponseWriter, r *http.Request) {
+	var request cc_client.CreatePackageRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+	if request.Relationships.App.Data == nil || s.apps[request.Relationships.App.Data.GUID] == nil {
+		writeUnprocessable(w, "App is invalid. Ensure it exists and you have access to it.")
+		return
+	}
+
+	p := &pkg{appGUID: request.Relationships.App.Data.GUID}
+	p.GUID = newGUID()
+	p.Type = request.Type
+	p.State = "AWAITING_UPLOAD"
+	if request.Type == "docker" {
+		p.State = "READY"
+	}
+	p.Links = map[string]cc_client.Link{
+		"self":   s.link("/v3/packages/" + p.GUID),
+		"upload": {Href: s.server.URL + "/v3/packages/" + p.GUID + "/upload", Method: "POST"},
+	}
+	s.packages[p.GUID] = p
+	writeJSON(w, http.StatusCreated, p.Package)
+}
+
+func (s *Server) getPackage(w http.ResponseWriter, r *http.Request) {
+	p, ok := mustFind(w, s.packages, r.PathValue("guid"), "Package")
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, p.Package)
+}
+
+func (s *Server) uploadPackage(w http.ResponseWriter, r *http.Request) {
+	p, ok := mustFind(w, s.packages, r.PathValue("guid"), "Package")
+	if !ok {
+		return
+	}
+	_, _ = io.Copy(io.Discard, r.Body)
+	p.State = "READY"
+	writeJSON(w, http.StatusOK, p.Package)
+}
+
+func (s *Server) createBuild(w http.ResponseWriter, r *http.Request) {
+	var request cc_client.CreateBuildRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+	p, exists := s.packages[request.Package.GUID]
+	if !exists || p.State != "READY" {
+		writeUnprocessable(w, "Unable to use package. Ensure that the package exists and you have access to it.")
+		return
+	}
+
+	b := &build{appGUID: p.appGUID, packageGUID: p.GUID}
+	b.GUID = newGUID()
+	b.State = "STAGING"
+	b.operation = s.startOperation(r, func() {
+		d := &droplet{appGUID: b.appGUID}
+		d.GUID = newGUID()
+		d.State = "STAGED"
+		s.droplets[d.GUID] = d
+		b.Droplet = &cc_client.Ref{GUID: d.GUID}
+	})
+	s.builds[b.GUID] = b
+	writeJSON(w, http.StatusCreated, b.Build)
+}
+
+func (s *Server) getBuild(w http.ResponseWriter, r *http.Request) {
+	b, ok := mustFind(w, s.builds, r.PathValue("guid"), "Build")
+	if !ok {
+		return
+	}
+	b.operation.poll()
+	switch b.operation.state {
+	case succeeded:
+		b.State = "STAGED"
+	case failed:
+		b.State = "FAILED"
+		b.Error = b.operation.failure
+	}
+	writeJSON(w, http.StatusOK, b.Build)
+}
+
+func (s *Server) getDroplet(w http.ResponseWriter, r *http.Request) {
+	d, ok := mustFind(w, s.droplets, r.PathValue("guid"), "Droplet")
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, d.Droplet)
+}
+
+func (s *Server) listDeployments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var resources []interface{}
+	deployments := sortedBy(s.deployments, func(d *deployment) string { return fmt.Sprintf("%09d", d.sequence) })
+	if query.Get("order_by") == "-created_at" {
+		slices.Reverse(deployments)
+	}
+	for _, d := range deployments {
+		d.poll()
+		if matches(query, "app_guids", d.appGUID) && matches(query, "status_values", d.Status.Value) && matches(query, "status_reasons", d.Status.Reason) {
+			resources = append(resources, d.Deployment)
+		}
+	}
+	s.writeList(w, r, resources, len(resources))
+}
+
+func (s *Server) createDeployment(w http.ResponseWriter, r *http.Request) {
+	var request cc_client.CreateDeploymentRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+	if request.Relationships.App.Data == nil || s.apps[request.Relationships.App.Data.GUID] == nil {
+		writeUnprocessable(w, "App is invalid. Ensure it exists and you have access to it.")
+		return
+	}
+	a := s.apps[request.Relationships.App.Data.GUID]
+
+	dropletGUID := a.dropletGUID
+	if request.Droplet != nil {
+		dropletGUID = request.Droplet.GUID
+	}
+	if _, exists := s.droplets[dropletGUID]; !exists {
+		writeUnprocessable(w, "Invalid droplet. Please specify a droplet in the request or set a current droplet for the app.")
+		return
+	}
+
+	for _, other := range s.deployments {
+		if other.appGUID == a.GUID && other.Status.Value == "ACTIVE" {
+			other.operation.state = canceled
+			other.Status.Value, other.Status.Reason = "FINALIZED", "SUPERSEDED"
+		}
+	}
+
+	a.revisions++
+	d := &deployment{appGUID: a.GUID, sequence: len(s.deployments)}
+	d.GUID = newGUID()
+	d.CreatedAt = now()
+	d.Strategy = request.Strategy
+	if d.Strategy == "" {
+		d.Strategy = "rolling"
+	}
+	if request.Options != nil {
+		d.Options = *request.Options
+	}
+	mergeMetadata(&d.Metadata, request.Metadata)
+	d.Droplet = cc_client.Ref{GUID: dropletGUID}
+	d.PreviousDroplet = cc_client.Ref{GUID: a.dropletGUID}
+	d.Revision = &cc_client.RevisionRef{GUID: newGUID(), Version: a.revisions}
+	d.Status.Value, d.Status.Reason = "ACTIVE", "DEPLOYING"
+	if d.Strategy == "canary" {
+		d.Status.Canary = &cc_client.CanaryStatus{Steps: cc_client.CanaryProgress{Total: max(1, len(d.InstanceWeights()))}}
+	}
+	d.operation = s.startOperation(r, s.advanceDeployment(d, a))
+	s.deployments[d.GUID] = d
+	writeJSON(w, http.StatusCreated, d.Deployment)
+}
+
+// advanceDeployment returns what happens when the deployment has rolled out its current step: a
+// canary deployment pauses at each of its steps, and is then deployed when it is continued again.
+func (s *Server) advanceDeployment(d *deployment, a *app) func() {
+	return func() {
+		if progress := d.CanaryStep(); d.Strategy == "canary" && progress.Current < progress.Total {
+			d.Status.Canary.Steps.Current++
+			d.Status.Reason = "PAUSED"
+			return
+		}
+		a.dropletGUID = d.Droplet.GUID
+		a.State = "STARTED"
+		d.Status.Value, d.Status.Reason = "FINALIZED", "DEPLOYED"
+	}
+}
+
+func (d *deployment) poll() {
+	d.operation.poll()
+	if d.operation.state == failed && d.Status.Value != "FINALIZED" {
+		d.Status.Value, d.Status.Reason = "FINALIZED", "DEGENERATE"
+		d.Status.Details.Error = d.operation.failure
+	}
+}
+
+func (s *Server) getDeployment(w http.ResponseWriter, r *http.Request) {
+	d, ok := mustFind(w, s.deployments, r.PathValue("guid"), "Deployment")
+	if !ok {
+		return
+	}
+	d.poll()
+	writeJSON(w, http.StatusOK, d.Deployment)
+}
+
+func (s *Server) continueDeployment(w http.ResponseWriter, r *http.Request) {
+	d, ok := mustFind(w, s.deployments, r.PathValue("guid"), "Deployment")
+	if !ok {
+		return
+	}
+	d.poll()
+	if d.Status.Reason != "PAUSED" {
+		writeUnprocessable(w, "Cannot continue a deployment with status: "+d.Status.Value+" and reason: "+d.Status.Reason)
+		return
+	}
+	d.Status.Reason = "DEPLOYING"
+	d.operation = s.startOperation(r, s.advanceDeployment(d, s.apps[d.appGUID]))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) cancelDeployment(w http.ResponseWriter, r *http.Request) {
+	d, ok := mustFind(w, s.deployments, r.PathValue("guid"), "Deployment")
+	if !ok {
+		return
+	}
+	d.poll()
+	if d.Status.Value == "FINALIZED" {
+		writeUnprocessable(w, "Cannot cancel a "+d.Status.Reason+" deployment")
+		return
+	}
+	d.operation.state = canceled
+	d.Status.Value, d.Status.Reason = "FINALIZED", "CANCELED"
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) getJob(w http.ResponseWriter, r *http.Request) {
+	j, ok := mustFind(w, s.jobs, r.PathValue("guid"), "Job")
+	if !ok {
+		return
+	}
+	j.operation.poll()
+	switch j.operation.state {
+	case inProgress:
+		j.State = "PROCESSING"
+	case succeeded:
+		j.State = "COMPLETE"
+	case failed:
+		j.State = "FAILED"
+		j.Errors = []cc_client.Error{{Code: 10008, Title: "CF-UnprocessableEntity", Detail: j.operation.failure}}
+	}
+	writeJSON(w, http.StatusOK, j)
+}
+
+// sortedBy returns the resources ordered by key, since map iteration is random.
+func sortedBy[T any](resources map[string]*T, key func(*T) string) []*T {
+	var sorted []*T
+	for _, resource := range resources {
+		sorted = append(sorted, resource)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return key(sorted[i]) < key(sorted[j]) })
+	return sorted
+}
@@ -0,0 +1,391 @@
+// Package fake_cc is an in-memory Cloud Controller and UAA for testing helpers without a
+// foundation. It implements the subset of the /v2/info, /v3 and /oauth/token endpoints that the
+// helpers use, keeps the resources that are created through it, and can be told to fail requests.
+//
+// It can back either a cc_client.Client or a cf CLI targeted at its URL:
+//
+//	cf api <fake.URL()> --skip-ssl-validation
+//	cf auth <username> <password>
+package fake_cc
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+)
+
+// Server is a fake Cloud Controller and UAA. Its methods are safe to call while requests are
+// being served.
+type Server struct {
+	server *httptest.Server
+	mux    *http.ServeMux
+
+	mu         sync.Mutex
+	requests   []string
+	failures   []*failure
+	asyncPolls int
+
+	users   map[string]string
+	clients map[string]string
+	tokens  map[string]tokenInfo
+
+	refreshTokens map[string]tokenInfo
+
+	orgs                      map[string]*organization
+	spaces                    map[string]*space
+	apps                      map[string]*app
+	processes                 map[string]*process
+	packages                  map[string]*pkg
+	builds                    map[string]*build
+	droplets                  map[string]*droplet
+	deployments               map[string]*deployment
+	domains                   map[string]*cc_client.Domain
+	routes                    map[string]*route
+	isolationSegments         map[string]*isolationSegment
+	orgQuotas                 map[string]*quota
+	spaceQuotas               map[string]*quota
+	serviceInstances          map[string]*serviceInstance
+	serviceCredentialBindings map[string]*serviceCredentialBinding
+	jobs                      map[string]*job
+}
+
+// Failure describes how Fail makes requests fail.
+type Failure struct {
+	// StatusCode and Errors are the status and the errors of the response, e.g.
+	// http.StatusUnprocessableEntity and a CF-UnprocessableEntity error.
+	StatusCode int
+	Errors     []cc_client.Error
+
+	// Async fails the job, build, deployment or service instance operation started by the
+	// request instead of the request itself, with the detail of the first error.
+	Async bool
+
+	// Times is how many requests fail before requests succeed again. Zero fails all of them.
+	Times int
+}
+
+type failure struct {
+	Failure
+	method, path string
+}
+
+// New starts a fake Cloud Controller over TLS with a self-signed certificate. Close it when done.
+func New() *Server {
+	s := &Server{
+		mux:                       http.NewServeMux(),
+		users:                     map[string]string{},
+		clients:                   map[string]string{},
+		tokens:                    map[string]tokenInfo{},
+		refreshTokens:             map[string]tokenInfo{},
+		orgs:                      map[string]*organization{},
+		spaces:                    map[string]*space{},
+		apps:                      map[string]*app{},
+		processes:                 map[string]*process{},
+		packages:                  map[string]*pkg{},
+		builds:                    map[string]*build{},
+		droplets:                  map[string]*droplet{},
+		deployments:               map[string]*deployment{},
+		domains:                   map[string]*cc_client.Domain{},
+		routes:                    map[string]*route{},
+		isolationSegments:         map[string]*isolationSegment{},
+		orgQuotas:                 map[string]*quota{},
+		spaceQuotas:               map[string]*quota{},
+		serviceInstances:          map[string]*serviceInstance{},
+		serviceCredentialBindings: map[string]*serviceCredentialBinding{},
+		jobs:                      map[string]*job{},
+	}
+	s.routeUAA()
+	s.routeCC()
+	s.server = httptest.NewTLSServer(s)
+	return s
+}
+
+// URL is the API endpoint of the fake, e.g. "https://127.0.0.1:34567".
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Close shuts the fake down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Fail makes the requests with method to path (without the query) fail as described by failure.
+// Later calls for the same request take precedence.
+func (s *Server) Fail(method, path string, f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &failure{Failure: f, method: method, path: path})
+}
+
+// SetAsyncPolls sets how many times the jobs, builds, deployments and service instance operations
+// started afterwards are reported as in progress before they complete. It is zero by default, so
+// that they complete the first time they are polled.
+func (s *Server) SetAsyncPolls(polls int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.asyncPolls = polls
+}
+
+// Requests returns the requests made so far, e.g. "POST /v3/apps" or "GET /v3/apps?names=foo".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+
+	if f := s.failureFor(r, false); f != nil {
+		writeJSON(w, f.StatusCode, map[string]interface{}{"errors": f.Errors})
+		return
+	}
+
+	if requiresToken(r.URL.Path) && !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, cc_client.Error{Code: 1000, Title: "CF-InvalidAuthToken", Detail: "Invalid Auth Token"})
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// failureFor returns the failure for the request and counts it, or nil if the request should not
+// fail. It must be called with s.mu held.
+func (s *Server) failureFor(r *http.Request, async bool) *failure {
+	for i := len(s.failures) - 1; i >= 0; i-- {
+		f := s.failures[i]
+		if f.method != r.Method || f.path != r.URL.Path || f.Async != async {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// asyncFailure returns the detail the operation started by the request should fail with, or "".
+func (s *Server) asyncFailure(r *http.Request) string {
+	f := s.failureFor(r, true)
+	if f == nil {
+		return ""
+	}
+	if len(f.Errors) == 0 {
+		return "the operation failed"
+	}
+	return f.Errors[0].Detail
+}
+
+func requiresToken(path string) bool {
+	return (strings.HasPrefix(path, "/v2/") && path != "/v2/info") || strings.HasPrefix(path, "/v3/")
+}
+
+func newGUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	h := hex.EncodeToString(b)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+func (s *Server) link(path string) cc_client.Link {
+	return cc_client.Link{Href: s.server.URL + path}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, ccError cc_client.Error) {
+	writeJSON(w, status, map[string]interface{}{"errors": []cc_client.Error{ccError}})
+}
+
+func writeNotFound(w http.ResponseWriter, resource string) {
+	writeError(w, http.StatusNotFound, cc_client.Error{Code: 10010, Title: "CF-ResourceNotFound", Detail: resource + " not found"})
+}
+
+func writeUnprocessable(w http.ResponseWriter, detail string) {
+	writeError(w, http.StatusUnprocessableEntity, cc_client.Error{Code: 10008, Title: "CF-UnprocessableEntity", Detail: detail})
+}
+
+// readJSON decodes the request body into body, and responds with a 422 if it is invalid.
+func readJSON(w http.ResponseWriter, r *http.Request, body interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(body)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, cc_client.Error{Code: 1001, Title: "CF-MessageParseError", Detail: "Request invalid due to parse error: " + err.Error()})
+		return false
+	}
+	return true
+}
+
+// writeList responds with all of the resources on a single page.
+func (s *Server) writeList(w http.ResponseWriter, r *http.Request, resources interface{}, count int) {
+	first := s.link(r.URL.RequestURI())
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"pagination": map[string]interface{}{
+			"total_results": count,
+			"total_pages":   1,
+			"first":         first,
+			"last":          first,
+			"next":          nil,
+			"previous":      nil,
+		},
+		"resources": resources,
+	})
+}
+
+// matches reports whether value is one of the comma-separated values of the query parameter key,
+// or the query does not filter by key.
+func matches(query url.Values, key, value string) bool {
+	filter := query.Get(key)
+	if filter == "" {
+		return true
+	}
+	for _, v := range strings.Split(filter, ",") {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesLabels supports the requirements of label selectors: "a=b", "a==b", "a!=b", "a in (b,c)",
+// "a notin (b,c)", "a" and "!a".
+func matchesLabels(query url.Values, metadata cc_client.Metadata) bool {
+	selector := query.Get("label_selector")
+	if selector == "" {
+		return true
+	}
+	for _, requirement := range splitSelector(selector) {
+		if !matchesRequirement(strings.TrimSpace(requirement), metadata.Labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitSelector splits a label selector on the commas between its requirements.
+func splitSelector(selector string) []string {
+	var requirements []string
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				requirements = append(requirements, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(requirements, selector[start:])
+}
+
+func matchesRequirement(requirement string, labels map[string]string) bool {
+	if key, values, found := cutSet(requirement, " notin "); found {
+		value, exists := labels[key]
+		return !exists || !slices.Contains(values, value)
+	}
+	if key, values, found := cutSet(requirement, " in "); found {
+		value, exists := labels[key]
+		return exists && slices.Contains(values, value)
+	}
+	if key, expected, found := strings.Cut(requirement, "!="); found {
+		value, exists := labels[key]
+		return !exists || value != expected
+	}
+	if key, expected, found := strings.Cut(strings.Replace(requirement, "==", "=", 1), "="); found {
+		value, exists := labels[key]
+		return exists && value == expected
+	}
+	if key, found := strings.CutPrefix(requirement, "!"); found {
+		_, exists := labels[key]
+		return !exists
+	}
+	_, exists := labels[requirement]
+	return exists
+}
+
+// cutSet splits a set based requirement, e.g. "a in (b,c)", into its key and values.
+func cutSet(requirement, operator string) (string, []string, bool) {
+	key, set, found := strings.Cut(requirement, operator)
+	if !found {
+		return "", nil, false
+	}
+	set = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(set), "("), ")")
+	var values []string
+	for _, value := range strings.Split(set, ",") {
+		values = append(values, strings.TrimSpace(value))
+	}
+	return strings.TrimSpace(key), values, true
+}
+
+func mergeMetadata(into *cc_client.Metadata, update *cc_client.Metadata) {
+	if update == nil {
+		return
+	}
+	merge := func(into *map[string]string, update map[string]string) {
+		for key, value := range update {
+			if *into == nil {
+				*into = map[string]string{}
+			}
+			(*into)[key] = value
+		}
+	}
+	merge(&into.Labels, update.Labels)
+	merge(&into.Annotations, update.Annotations)
+}
+
+// updateMetadata applies the metadata of a PATCH request, in which null removes a key.
+func updateMetadata(into *cc_client.Metadata, update *cc_client.MetadataUpdate) {
+	if update == nil {
+		return
+	}
+	apply := func(into *map[string]string, update map[string]*string) {
+		for key, value := range update {
+			if value == nil {
+				delete(*into, key)
+				continue
+			}
+			if *into == nil {
+				*into = map[string]string{}
+			}
+			(*into)[key] = *value
+		}
+	}
+	apply(&into.Labels, update.Labels)
+	apply(&into.Annotations, update.Annotations)
+}
+
+func mustFind[T any](w http.ResponseWriter, resources map[string]*T, guid, resource string) (*T, bool) {
+	found, ok := resources[guid]
+	if !ok {
+		writeNotFound(w, resource)
+	}
+	return found, ok
+}
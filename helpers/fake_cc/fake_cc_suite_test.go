@@ -0,0 +1,13 @@
+package fake_cc_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestFakeCC(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Fake CC Suite")
+}
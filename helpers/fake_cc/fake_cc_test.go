@@ -0,0 +1,228 @@
+package fake_cc_test
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/fake_cc"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		fake      *fake_cc.Server
+		client    *cc_client.Client
+		spaceGUID string
+	)
+
+	BeforeEach(func() {
+		fake = fake_cc.New()
+		fake.AddUser("admin", "admin-password")
+		spaceGUID = fake.AddSpace(fake.AddOrganization("some-org"), "some-space")
+
+		token, err := cc_client.UserToken(fake.URL(), "admin", "admin-password", true)
+		Expect(err).NotTo(HaveOccurred())
+		client = cc_client.New(fake.URL(), token, true).WithJobPolling(time.Second, cc_client.Backoff{Initial: time.Millisecond})
+	})
+
+	AfterEach(func() {
+		fake.Close()
+	})
+
+	createApp := func(name string) cc_client.App {
+		request := cc_client.CreateAppRequest{Name: name}
+		request.Relationships.Space = cc_client.RelationshipTo(spaceGUID)
+		app, err := client.CreateApp(request)
+		Expect(err).NotTo(HaveOccurred())
+		return app
+	}
+
+	Describe("authentication", func() {
+		It("rejects bad credentials", func() {
+			_, err := cc_client.UserToken(fake.URL(), "admin", "wrong-password", true)
+			Expect(err).To(MatchError(ContainSubstring("status 401")))
+		})
+
+		It("issues tokens to clients", func() {
+			fake.AddClient("some-client", "some-secret")
+
+			token, err := cc_client.ClientToken(fake.URL(), "some-client", "some-secret", true)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = cc_client.New(fake.URL(), token, true).ListApps(nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects requests without a valid token", func() {
+			_, err := cc_client.New(fake.URL(), "bearer not-a-token", true).ListApps(nil)
+
+			var responseError *cc_client.ResponseError
+			Expect(errors.As(err, &responseError)).To(BeTrue())
+			Expect(responseError.HasTitle("CF-InvalidAuthToken")).To(BeTrue())
+		})
+
+		It("accepts the tokens it hands out directly", func() {
+			_, err := cc_client.New(fake.URL(), fake.Token("some-user"), true).ListApps(nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	It("pushes docker apps", func() {
+		app := createApp("some-app")
+
+		packageRequest := cc_client.CreatePackageRequest{Type: "docker", Data: &cc_client.PackageData{Image: "cloudfoundry/diego-docker-app"}}
+		packageRequest.Relationships.App = cc_client.RelationshipTo(app.GUID)
+		pkg, err := client.CreatePackage(packageRequest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pkg.State).To(Equal("READY"))
+
+		build, err := client.CreateBuild(cc_client.CreateBuildRequest{Package: cc_client.Ref{GUID: pkg.GUID}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cc_client.Wait(client.BuildOperation(build.GUID), time.Second, cc_client.Backoff{Initial: time.Millisecond})).To(Succeed())
+
+		build, err = client.GetBuild(build.GUID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.SetCurrentDroplet(app.GUID, build.Droplet.GUID)).To(Succeed())
+
+		_, err = client.StartApp(app.GUID)
+		Expect(err).NotTo(HaveOccurred())
+
+		instances := 2
+		process, err := client.ScaleProcess(app.GUID, "web", cc_client.ScaleRequest{Instances: &instances})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.GetProcessStats(process.GUID)).To(ConsistOf(
+			HaveField("State", "RUNNING"),
+			HaveField("State", "RUNNING"),
+		))
+	})
+
+	It("refuses to start apps without a droplet", func() {
+		app := createApp("some-app")
+
+		_, err := client.StartApp(app.GUID)
+		Expect(err).To(MatchError(ContainSubstring("Assign a droplet before starting this app.")))
+	})
+
+	It("filters lists", func() {
+		createApp("some-app")
+		createApp("other-app")
+
+		apps, err := client.ListApps(url.Values{"names": {"other-app,missing-app"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(apps).To(ConsistOf(HaveField("Name", "other-app")))
+	})
+
+	It("selects resources with label selectors", func() {
+		labelApp := func(name, tier string) string {
+			request := cc_client.CreateAppRequest{Name: name}
+			if tier != "" {
+				request.Metadata = &cc_client.Metadata{Labels: map[string]string{"tier": tier}}
+			}
+			request.Relationships.Space = cc_client.RelationshipTo(spaceGUID)
+			app, err := client.CreateApp(request)
+			Expect(err).NotTo(HaveOccurred())
+			return app.GUID
+		}
+		frontend, backend, unlabelled := labelApp("frontend", "web"), labelApp("backend", "api"), labelApp("unlabelled", "")
+
+		selected := func(selector string) []string {
+			guids, err := client.ListGUIDs("apps", url.Values{"label_selector": {selector}})
+			Expect(err).NotTo(HaveOccurred())
+			return guids
+		}
+		Expect(selected("tier==web")).To(ConsistOf(frontend))
+		Expect(selected("tier!=web")).To(ConsistOf(backend, unlabelled))
+		Expect(selected("tier in (web,api),tier notin (api)")).To(ConsistOf(frontend))
+		Expect(selected("!tier")).To(ConsistOf(unlabelled))
+
+		value := "api"
+		_, err := client.UpdateMetadata("apps", frontend, cc_client.MetadataUpdate{Labels: map[string]*string{"tier": nil, "other": &value}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(selected("tier")).To(ConsistOf(backend))
+		Expect(client.GetMetadata("apps", frontend)).To(Equal(cc_client.Metadata{Labels: map[string]string{"other": "api"}}))
+	})
+
+	It("deletes resources asynchronously", func() {
+		fake.SetAsyncPolls(2)
+		app := createApp("some-app")
+
+		Expect(client.DeleteApp(app.GUID)).To(Succeed())
+
+		Expect(fake.Requests()).To(ContainElements(
+			"DELETE /v3/apps/"+app.GUID,
+			MatchRegexp(`^GET /v3/jobs/\S+$`),
+		))
+		_, err := client.GetApp(app.GUID)
+		Expect(err).To(MatchError(ContainSubstring("App not found")))
+	})
+
+	It("tracks the last operation of service instances", func() {
+		fake.SetAsyncPolls(1)
+		Expect(client.Post("/v3/service_instances", map[string]interface{}{
+			"type":          "managed",
+			"name":          "some-instance",
+			"relationships": map[string]interface{}{"space": cc_client.RelationshipTo(spaceGUID)},
+		}, nil)).To(Succeed())
+
+		instances, err := cc_client.GetAll[struct{ GUID string }](client, "/v3/service_instances?names=some-instance")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Delete("/v3/service_instances/" + instances[0].GUID)).To(Succeed())
+
+		Expect(cc_client.Wait(client.ServiceInstanceOperation("some-instance"), time.Second, cc_client.Backoff{Initial: time.Millisecond})).To(Succeed())
+		Expect(cc_client.GetAll[struct{ GUID string }](client, "/v3/service_instances?names=some-instance")).To(BeEmpty())
+	})
+
+	It("manages isolation segments", func() {
+		orgGUID := fake.AddOrganization("other-org")
+		isolationSegment, err := client.CreateIsolationSegment("some-segment")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(client.SetDefaultIsolationSegment(orgGUID, isolationSegment.GUID)).To(MatchError(ContainSubstring("Ensure it has been entitled")))
+
+		Expect(client.EntitleOrganizations(isolationSegment.GUID, orgGUID)).To(Succeed())
+		Expect(client.SetDefaultIsolationSegment(orgGUID, isolationSegment.GUID)).To(Succeed())
+		Expect(client.GetDefaultIsolationSegment(orgGUID)).To(Equal(isolationSegment.GUID))
+		Expect(client.ListIsolationSegments(url.Values{"organization_guids": {orgGUID}})).To(HaveLen(1))
+
+		Expect(client.SetDefaultIsolationSegment(orgGUID, "")).To(Succeed())
+		Expect(client.RevokeOrganization(isolationSegment.GUID, orgGUID)).To(Succeed())
+		Expect(client.DeleteIsolationSegment(isolationSegment.GUID)).To(Succeed())
+	})
+
+	Describe("scripted failures", func() {
+		It("fails requests the given number of times", func() {
+			fake.Fail(http.MethodPost, "/v3/apps", fake_cc.Failure{
+				StatusCode: http.StatusServiceUnavailable,
+				Errors:     []cc_client.Error{{Code: 10001, Title: "CF-ServiceUnavailable", Detail: "try again"}},
+				Times:      1,
+			})
+
+			request := cc_client.CreateAppRequest{Name: "some-app"}
+			request.Relationships.Space = cc_client.RelationshipTo(spaceGUID)
+			_, err := client.CreateApp(request)
+
+			var responseError *cc_client.ResponseError
+			Expect(errors.As(err, &responseError)).To(BeTrue())
+			Expect(responseError.StatusCode).To(Equal(http.StatusServiceUnavailable))
+			Expect(responseError.HasTitle("CF-ServiceUnavailable")).To(BeTrue())
+
+			createApp("some-app")
+		})
+
+		It("fails the asynchronous operations of requests", func() {
+			app := createApp("some-app")
+			fake.Fail(http.MethodDelete, "/v3/apps/"+app.GUID, fake_cc.Failure{
+				Async:  true,
+				Errors: []cc_client.Error{{Detail: "the app is locked"}},
+			})
+
+			err := client.DeleteApp(app.GUID)
+			Expect(err).To(MatchError(MatchRegexp(`job \S+ failed after \S+ in state FAILED: the app is locked`)))
+			Expect(client.GetApp(app.GUID)).To(HaveField("Name", "some-app"))
+		})
+	})
+})
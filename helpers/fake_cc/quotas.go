@@ -0,0 +1,202 @@
+package fake_cc
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+)
+
+// quota is an organization quota, or a space quota if orgGUID is set. The organizations and spaces
+// it is applied to refer to it by GUID.
+type quota struct {
+	limits  cc_client.QuotaLimits
+	guid    string
+	name    string
+	orgGUID string
+}
+
+func (s *Server) routeQuotas() {
+	s.mux.HandleFunc("POST /v3/organization_quotas", s.createOrganizationQuota)
+	s.mux.HandleFunc("GET /v3/organization_quotas/{guid}", s.getQuota(s.orgQuotas))
+	s.mux.HandleFunc("PATCH /v3/organization_quotas/{guid}", s.updateQuota(s.orgQuotas))
+	s.mux.HandleFunc("DELETE /v3/organization_quotas/{guid}", s.deleteQuota(s.orgQuotas, "organization_quota.delete"))
+	s.mux.HandleFunc("POST /v3/organization_quotas/{guid}/relationships/organizations", s.applyOrganizationQuota)
+	s.mux.HandleFunc("POST /v3/space_quotas", s.createSpaceQuota)
+	s.mux.HandleFunc("GET /v3/space_quotas/{guid}", s.getQuota(s.spaceQuotas))
+	s.mux.HandleFunc("PATCH /v3/space_quotas/{guid}", s.updateQuota(s.spaceQuotas))
+	s.mux.HandleFunc("DELETE /v3/space_quotas/{guid}", s.deleteQuota(s.spaceQuotas, "space_quota.delete"))
+	s.mux.HandleFunc("POST /v3/space_quotas/{guid}/relationships/spaces", s.applySpaceQuota)
+}
+
+func (s *Server) renderQuota(q *quota) cc_client.Quota {
+	rendered := cc_client.Quota{GUID: q.guid, Name: q.name, QuotaLimits: q.limits}
+	if q.orgGUID == "" {
+		rendered.Relationships.Organizations.Data = []cc_client.Ref{}
+		for _, org := range sortedBy(s.orgs, func(o *organization) string { return o.Name }) {
+			if org.quotaGUID == q.guid {
+				rendered.Relationships.Organizations.Data = append(rendered.Relationships.Organizations.Data, cc_client.Ref{GUID: org.GUID})
+			}
+		}
+		return rendered
+	}
+	rendered.Relationships.Organization = cc_client.RelationshipTo(q.orgGUID)
+	rendered.Relationships.Spaces.Data = []cc_client.Ref{}
+	for _, sp := range sortedBy(s.spaces, func(sp *space) string { return sp.Name }) {
+		if sp.quotaGUID == q.guid {
+			rendered.Relationships.Spaces.Data = append(rendered.Relationships.Spaces.Data, cc_client.Ref{GUID: sp.GUID})
+		}
+	}
+	return rendered
+}
+
+func (s *Server) createOrganizationQuota(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Name string `json:"name"`
+		cc_client.QuotaLimits
+		Relationships struct {
+			Organizations cc_client.ToMany `json:"organizations"`
+		} `json:"relationships"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	for _, existing := range s.orgQuotas {
+		if existing.name == request.Name {
+			writeUnprocessable(w, "Organization Quota '"+request.Name+"' already exists.")
+			return
+		}
+	}
+	if !s.orgsExist(w, request.Relationships.Organizations) {
+		return
+	}
+	q := &quota{guid: newGUID(), name: request.Name, limits: request.QuotaLimits}
+	s.orgQuotas[q.guid] = q
+	for _, org := range request.Relationships.Organizations.Data {
+		s.orgs[org.GUID].quotaGUID = q.guid
+	}
+	writeJSON(w, http.StatusCreated, s.renderQuota(q))
+}
+
+func (s *Server) createSpaceQuota(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Name string `json:"name"`
+		cc_client.QuotaLimits
+		Relationships struct {
+			Organization cc_client.ToOne  `json:"organization"`
+			Spaces       cc_client.ToMany `json:"spaces"`
+		} `json:"relationships"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	orgData := request.Relationships.Organization.Data
+	if orgData == nil || s.orgs[orgData.GUID] == nil {
+		writeUnprocessable(w, "Organization with guid does not exist, or you do not have access to it.")
+		return
+	}
+	for _, existing := range s.spaceQuotas {
+		if existing.orgGUID == orgData.GUID && existing.name == request.Name {
+			writeUnprocessable(w, "Space Quota '"+request.Name+"' already exists.")
+			return
+		}
+	}
+	q := &quota{guid: newGUID(), name: request.Name, limits: request.QuotaLimits, orgGUID: orgData.GUID}
+	if !s.spacesInOrg(w, q, request.Relationships.Spaces) {
+		return
+	}
+	s.spaceQuotas[q.guid] = q
+	for _, sp := range request.Relationships.Spaces.Data {
+		s.spaces[sp.GUID].quotaGUID = q.guid
+	}
+	writeJSON(w, http.StatusCreated, s.renderQuota(q))
+}
+
+func (s *Server) getQuota(quotas map[string]*quota) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q, ok := mustFind(w, quotas, r.PathValue("guid"), "Quota")
+		if !ok {
+			return
+		}
+		writeJSON(w, http.StatusOK, s.renderQuota(q))
+	}
+}
+
+func (s *Server) updateQuota(quotas map[string]*quota) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q, ok := mustFind(w, quotas, r.PathValue("guid"), "Quota")
+		if !ok {
+			return
+		}
+		var request cc_client.QuotaLimits
+		if !readJSON(w, r, &request) {
+			return
+		}
+		q.limits = request
+		writeJSON(w, http.StatusOK, s.renderQuota(q))
+	}
+}
+
+func (s *Server) deleteQuota(quotas map[string]*quota, jobName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q, ok := mustFind(w, quotas, r.PathValue("guid"), "Quota")
+		if !ok {
+			return
+		}
+		rendered := s.renderQuota(q)
+		if len(rendered.Relationships.Organizations.Data) > 0 || len(rendered.Relationships.Spaces.Data) > 0 {
+			writeUnprocessable(w, "This quota is applied to one or more organizations or spaces. Remove this quota from all of them before deleting.")
+			return
+		}
+		s.writeJob(w, jobName, s.startOperation(r, func() { delete(quotas, q.guid) }))
+	}
+}
+
+func (s *Server) applyOrganizationQuota(w http.ResponseWriter, r *http.Request) {
+	q, ok := mustFind(w, s.orgQuotas, r.PathValue("guid"), "Organization quota")
+	if !ok {
+		return
+	}
+	var request cc_client.ToMany
+	if !readJSON(w, r, &request) || !s.orgsExist(w, request) {
+		return
+	}
+	for _, org := range request.Data {
+		s.orgs[org.GUID].quotaGUID = q.guid
+	}
+	writeJSON(w, http.StatusOK, s.renderQuota(q).Relationships.Organizations)
+}
+
+func (s *Server) applySpaceQuota(w http.ResponseWriter, r *http.Request) {
+	q, ok := mustFind(w, s.spaceQuotas, r.PathValue("guid"), "Space quota")
+	if !ok {
+		return
+	}
+	var request cc_client.ToMany
+	if !readJSON(w, r, &request) || !s.spacesInOrg(w, q, request) {
+		return
+	}
+	for _, sp := range request.Data {
+		s.spaces[sp.GUID].quotaGUID = q.guid
+	}
+	writeJSON(w, http.StatusOK, s.renderQuota(q).Relationships.Spaces)
+}
+
+func (s *Server) orgsExist(w http.ResponseWriter, orgs cc_client.ToMany) bool {
+	for _, org := range orgs.Data {
+		if s.orgs[org.GUID] == nil {
+			writeUnprocessable(w, "Organizations with guids [\""+org.GUID+"\"] do not exist, or you do not have access to them.")
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) spacesInOrg(w http.ResponseWriter, q *quota, spaces cc_client.ToMany) bool {
+	for _, sp := range spaces.Data {
+		if existing := s.spaces[sp.GUID]; existing == nil || existing.orgGUID != q.orgGUID {
+			writeUnprocessable(w, "Spaces with guids [\""+sp.GUID+"\"] do not exist within the organization specified, or you do not have access to them.")
+			return false
+		}
+	}
+	return true
+}
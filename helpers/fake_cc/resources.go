@@ -0,0 +1,747 @@
+package fake_cc
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+)
+
+type organization struct {
+	GUID          string                     `json:"guid"`
+	Name          string                     `json:"name"`
+	CreatedAt     time.Time                  `json:"created_at"`
+	Metadata      cc_client.Metadata         `json:"metadata"`
+	Links         map[string]cc_client.Link  `json:"links"`
+	Relationships map[string]cc_client.ToOne `json:"relationships"`
+
+	defaultIsolationSegmentGUID string
+	quotaGUID                   string
+}
+
+type space struct {
+	GUID          string                     `json:"guid"`
+	Name          string                     `json:"name"`
+	CreatedAt     time.Time                  `json:"created_at"`
+	Metadata      cc_client.Metadata         `json:"metadata"`
+	Links         map[string]cc_client.Link  `json:"links"`
+	Relationships map[string]cc_client.ToOne `json:"relationships"`
+
+	orgGUID              string
+	isolationSegmentGUID string
+	quotaGUID            string
+}
+
+type route struct {
+	cc_client.Route
+	Metadata   cc_client.Metadata `json:"metadata"`
+	spaceGUID  string
+	domainGUID string
+}
+
+type isolationSegment struct {
+	cc_client.IsolationSegment
+	orgGUIDs map[string]bool
+}
+
+type lastOperation struct {
+	Type        string `json:"type"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+}
+
+type serviceInstance struct {
+	GUID          string             `json:"guid"`
+	Name          string             `json:"name"`
+	Type          string             `json:"type"`
+	Metadata      cc_client.Metadata `json:"metadata"`
+	LastOperation lastOperation      `json:"last_operation"`
+	spaceGUID     string
+	operation     *operation
+}
+
+type serviceCredentialBinding struct {
+	GUID          string        `json:"guid"`
+	Name          string        `json:"name"`
+	Type          string        `json:"type"`
+	LastOperation lastOperation `json:"last_operation"`
+	operation     *operation
+}
+
+// AddOrganization creates an organization and returns its GUID.
+func (s *Server) AddOrganization(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	org := &organization{GUID: newGUID(), Name: name, CreatedAt: now()}
+	s.orgs[org.GUID] = org
+	return org.GUID
+}
+
+// AddSpace creates a space in the organization and returns its GUID.
+func (s *Server) AddSpace(orgGUID, name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sp := &space{GUID: newGUID(), Name: name, CreatedAt: now(), orgGUID: orgGUID}
+	s.spaces[sp.GUID] = sp
+	return sp.GUID
+}
+
+// AddDomain creates a shared domain and returns its GUID.
+func (s *Server) AddDomain(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	domain := &cc_client.Domain{GUID: newGUID(), Name: name}
+	s.domains[domain.GUID] = domain
+	return domain.GUID
+}
+
+func (s *Server) routeCC() {
+	s.mux.HandleFunc("GET /v3/organizations", s.listOrganizations)
+	s.mux.HandleFunc("POST /v3/organizations", s.createOrganization)
+	s.mux.HandleFunc("GET /v3/organizations/{guid}", s.getOrganization)
+	s.mux.HandleFunc("PATCH /v3/organizations/{guid}", s.updateOrganization)
+	s.mux.HandleFunc("GET /v3/organizations/{guid}/relationships/default_isolation_segment", s.getDefaultIsolationSegment)
+	s.mux.HandleFunc("PATCH /v3/organizations/{guid}/relationships/default_isolation_segment", s.setDefaultIsolationSegment)
+	s.mux.HandleFunc("GET /v3/spaces", s.listSpaces)
+	s.mux.HandleFunc("POST /v3/spaces", s.createSpace)
+	s.mux.HandleFunc("GET /v3/spaces/{guid}", s.getSpace)
+	s.mux.HandleFunc("PATCH /v3/spaces/{guid}", s.updateSpace)
+	s.mux.HandleFunc("GET /v3/spaces/{guid}/relationships/isolation_segment", s.getSpaceIsolationSegment)
+	s.mux.HandleFunc("PATCH /v3/spaces/{guid}/relationships/isolation_segment", s.setSpaceIsolationSegment)
+	s.mux.HandleFunc("GET /v3/domains", s.listDomains)
+	s.mux.HandleFunc("GET /v3/routes", s.listRoutes)
+	s.mux.HandleFunc("POST /v3/routes", s.createRoute)
+	s.mux.HandleFunc("GET /v3/routes/{guid}", s.getRoute)
+	s.mux.HandleFunc("PATCH /v3/routes/{guid}", s.updateRoute)
+	s.mux.HandleFunc("POST /v3/routes/{guid}/destinations", s.insertRouteDestinations)
+	s.mux.HandleFunc("GET /v3/isolation_segments", s.listIsolationSegments)
+	s.mux.HandleFunc("POST /v3/isolation_segments", s.createIsolationSegment)
+	s.mux.HandleFunc("GET /v3/isolation_segments/{guid}", s.getIsolationSegment)
+	s.mux.HandleFunc("DELETE /v3/isolation_segments/{guid}", s.deleteIsolationSegment)
+	s.mux.HandleFunc("POST /v3/isolation_segments/{guid}/relationships/organizations", s.entitleOrganizations)
+	s.mux.HandleFunc("DELETE /v3/isolation_segments/{guid}/relationships/organizations/{org_guid}", s.revokeOrganization)
+	s.mux.HandleFunc("GET /v3/service_instances", s.listServiceInstances)
+	s.mux.HandleFunc("POST /v3/service_instances", s.createServiceInstance)
+	s.mux.HandleFunc("GET /v3/service_instances/{guid}", s.getServiceInstance)
+	s.mux.HandleFunc("PATCH /v3/service_instances/{guid}", s.updateServiceInstance)
+	s.mux.HandleFunc("DELETE /v3/service_instances/{guid}", s.deleteServiceInstance)
+	s.mux.HandleFunc("POST /v3/service_credential_bindings", s.createServiceCredentialBinding)
+	s.mux.HandleFunc("GET /v3/service_credential_bindings/{guid}", s.getServiceCredentialBinding)
+	s.mux.HandleFunc("DELETE /v3/service_credential_bindings/{guid}", s.deleteServiceCredentialBinding)
+	s.routeQuotas()
+	s.routeApps()
+}
+
+func (s *Server) renderOrganization(org *organization) *organization {
+	org.Links = map[string]cc_client.Link{"self": s.link("/v3/organizations/" + org.GUID)}
+	org.Relationships = map[string]cc_client.ToOne{"quota": cc_client.RelationshipTo(org.quotaGUID)}
+	return org
+}
+
+func (s *Server) listOrganizations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var resources []interface{}
+	for _, org := range sortedBy(s.orgs, func(o *organization) string { return o.Name }) {
+		if matches(query, "names", org.Name) && matches(query, "guids", org.GUID) && matchesLabels(query, org.Metadata) {
+			resources = append(resources, s.renderOrganization(org))
+		}
+	}
+	s.writeList(w, r, resources, len(resources))
+}
+
+func (s *Server) createOrganization(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Name     string              `json:"name"`
+		Metadata *cc_client.Metadata `json:"metadata"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	for _, existing := range s.orgs {
+		if existing.Name == request.Name {
+			writeUnprocessable(w, "Organization '"+request.Name+"' already exists.")
+			return
+		}
+	}
+	org := &organization{GUID: newGUID(), Name: request.Name, CreatedAt: now()}
+	mergeMetadata(&org.Metadata, request.Metadata)
+	s.orgs[org.GUID] = org
+	writeJSON(w, http.StatusCreated, s.renderOrganization(org))
+}
+
+func (s *Server) getOrganization(w http.ResponseWriter, r *http.Request) {
+	org, ok := mustFind(w, s.orgs, r.PathValue("guid"), "Organization")
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderOrganization(org))
+}
+
+func (s *Server) updateOrganization(w http.ResponseWriter, r *http.Request) {
+	org, ok := mustFind(w, s.orgs, r.PathValue("guid"), "Organization")
+	if !ok {
+		return
+	}
+	var request struct {
+		Metadata *cc_client.MetadataUpdate `json:"metadata"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	updateMetadata(&org.Metadata, request.Metadata)
+	writeJSON(w, http.StatusOK, s.renderOrganization(org))
+}
+
+func (s *Server) getDefaultIsolationSegment(w http.ResponseWriter, r *http.Request) {
+	org, ok := mustFind(w, s.orgs, r.PathValue("guid"), "Organization")
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, cc_client.RelationshipTo(org.defaultIsolationSegmentGUID))
+}
+
+func (s *Server) setDefaultIsolationSegment(w http.ResponseWriter, r *http.Request) {
+	org, ok := mustFind(w, s.orgs, r.PathValue("guid"), "Organization")
+	if !ok {
+		return
+	}
+	var request cc_client.ToOne
+	if !readJSON(w, r, &request) {
+		return
+	}
+	org.defaultIsolationSegmentGUID = ""
+	if request.Data != nil {
+		if !s.entitled(request.Data.GUID, org.GUID) {
+			writeUnprocessable(w, "Unable to assign isolation segment with guid '"+request.Data.GUID+"'. Ensure it has been entitled to the organization.")
+			return
+		}
+		org.defaultIsolationSegmentGUID = request.Data.GUID
+	}
+	writeJSON(w, http.StatusOK, cc_client.RelationshipTo(org.defaultIsolationSegmentGUID))
+}
+
+func (s *Server) renderSpace(sp *space) *space {
+	sp.Links = map[string]cc_client.Link{
+		"self":         s.link("/v3/spaces/" + sp.GUID),
+		"organization": s.link("/v3/organizations/" + sp.orgGUID),
+	}
+	sp.Relationships = map[string]cc_client.ToOne{
+		"organization": cc_client.RelationshipTo(sp.orgGUID),
+		"quota":        cc_client.RelationshipTo(sp.quotaGUID),
+	}
+	return sp
+}
+
+func (s *Server) listSpaces(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var resources []interface{}
+	for _, sp := range sortedBy(s.spaces, func(sp *space) string { return sp.Name }) {
+		if matches(query, "names", sp.Name) && matches(query, "guids", sp.GUID) && matches(query, "organization_guids", sp.orgGUID) && matchesLabels(query, sp.Metadata) {
+			resources = append(resources, s.renderSpace(sp))
+		}
+	}
+	s.writeList(w, r, resources, len(resources))
+}
+
+func (s *Server) createSpace(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Name          string `json:"name"`
+		Relationships struct {
+			Organization cc_client.ToOne `json:"organization"`
+		} `json:"relationships"`
+		Metadata *cc_client.Metadata `json:"metadata"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	orgData := request.Relationships.Organization.Data
+	if orgData == nil || s.orgs[orgData.GUID] == nil {
+		writeUnprocessable(w, "Invalid organization. Ensure the organization exists and you have access to it.")
+		return
+	}
+	sp := &space{GUID: newGUID(), Name: request.Name, CreatedAt: now(), orgGUID: orgData.GUID}
+	mergeMetadata(&sp.Metadata, request.Metadata)
+	s.spaces[sp.GUID] = sp
+	writeJSON(w, http.StatusCreated, s.renderSpace(sp))
+}
+
+func (s *Server) getSpace(w http.ResponseWriter, r *http.Request) {
+	sp, ok := mustFind(w, s.spaces, r.PathValue("guid"), "Space")
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderSpace(sp))
+}
+
+func (s *Server) updateSpace(w http.ResponseWriter, r *http.Request) {
+	sp, ok := mustFind(w, s.spaces, r.PathValue("guid"), "Space")
+	if !ok {
+		return
+	}
+	var request struct {
+		Metadata *cc_client.MetadataUpdate `json:"metadata"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	updateMetadata(&sp.Metadata, request.Metadata)
+	writeJSON(w, http.StatusOK, s.renderSpace(sp))
+}
+
+func (s *Server) getSpaceIsolationSegment(w http.ResponseWriter, r *http.Request) {
+	sp, ok := mustFind(w, s.spaces, r.PathValue("guid"), "Space")
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, cc_client.RelationshipTo(sp.isolationSegmentGUID))
+}
+
+func (s *Server) setSpaceIsolationSegment(w http.ResponseWriter, r *http.Request) {
+	sp, ok := mustFind(w, s.spaces, r.PathValue("guid"), "Space")
+	if !ok {
+		return
+	}
+	var request cc_client.ToOne
+	if !readJSON(w, r, &request) {
+		return
+	}
+	sp.isolationSegmentGUID = ""
+	if request.Data != nil {
+		if !s.entitled(request.Data.GUID, sp.orgGUID) {
+			writeUnprocessable(w, "Unable to assign isolation segment with guid '"+request.Data.GUID+"'. Ensure it has been entitled to the organization that this space belongs to.")
+			return
+		}
+		sp.isolationSegmentGUID = request.Data.GUID
+	}
+	writeJSON(w, http.StatusOK, cc_client.RelationshipTo(sp.isolationSegmentGUID))
+}
+
+func (s *Server) listDomains(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var resources []interface{}
+	for _, domain := range sortedBy(s.domains, func(d *cc_client.Domain) string { return d.Name }) {
+		if matches(query, "names", domain.Name) && matches(query, "guids", domain.GUID) {
+			resources = append(resources, domain)
+		}
+	}
+	s.writeList(w, r, resources, len(resources))
+}
+
+func (s *Server) renderRoute(rt *route) *route {
+	rt.URL = rt.Host + "." + s.domains[rt.domainGUID].Name + rt.Path
+	if rt.Host == "" {
+		rt.URL = s.domains[rt.domainGUID].Name + rt.Path
+	}
+	if rt.Destinations == nil {
+		rt.Destinations = []cc_client.Destination{}
+	}
+	return rt
+}
+
+func (s *Server) listRoutes(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var resources []interface{}
+	for _, rt := range sortedBy(s.routes, func(rt *route) string { return rt.GUID }) {
+		if matches(query, "hosts", rt.Host) && matches(query, "paths", rt.Path) && matches(query, "domain_guids", rt.domainGUID) &&
+			matches(query, "space_guids", rt.spaceGUID) && matches(query, "guids", rt.GUID) && matchesLabels(query, rt.Metadata) {
+			resources = append(resources, s.renderRoute(rt))
+		}
+	}
+	s.writeList(w, r, resources, len(resources))
+}
+
+func (s *Server) createRoute(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Host          string `json:"host"`
+		Path          string `json:"path"`
+		Port          *int   `json:"port"`
+		Relationships struct {
+			Space  cc_client.ToOne `json:"space"`
+			Domain cc_client.ToOne `json:"domain"`
+		} `json:"relationships"`
+		Metadata *cc_client.Metadata `json:"metadata"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	spaceData, domainData := request.Relationships.Space.Data, request.Relationships.Domain.Data
+	if spaceData == nil || s.spaces[spaceData.GUID] == nil {
+		writeUnprocessable(w, "Invalid space. Ensure that the space exists and you have access to it.")
+		return
+	}
+	if domainData == nil || s.domains[domainData.GUID] == nil {
+		writeUnprocessable(w, "Invalid domain. Ensure that the domain exists and you have access to it.")
+		return
+	}
+	for _, existing := range s.routes {
+		if existing.Host == request.Host && existing.Path == request.Path && existing.domainGUID == domainData.GUID {
+			writeUnprocessable(w, "Route already exists.")
+			return
+		}
+	}
+
+	rt := &route{spaceGUID: spaceData.GUID, domainGUID: domainData.GUID}
+	rt.GUID = newGUID()
+	rt.Host = request.Host
+	rt.Path = request.Path
+	rt.Port = request.Port
+	mergeMetadata(&rt.Metadata, request.Metadata)
+	s.routes[rt.GUID] = rt
+	writeJSON(w, http.StatusCreated, s.renderRoute(rt))
+}
+
+func (s *Server) getRoute(w http.ResponseWriter, r *http.Request) {
+	rt, ok := mustFind(w, s.routes, r.PathValue("guid"), "Route")
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderRoute(rt))
+}
+
+func (s *Server) updateRoute(w http.ResponseWriter, r *http.Request) {
+	rt, ok := mustFind(w, s.routes, r.PathValue("guid"), "Route")
+	if !ok {
+		return
+	}
+	var request struct {
+		Metadata *cc_client.MetadataUpdate `json:"metadata"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	updateMetadata(&rt.Metadata, request.Metadata)
+	writeJSON(w, http.StatusOK, s.renderRoute(rt))
+}
+
+func (s *Server) insertRouteDestinations(w http.ResponseWriter, r *http.Request) {
+	rt, ok := mustFind(w, s.routes, r.PathValue("guid"), "Route")
+	if !ok {
+		return
+	}
+	var request struct {
+		Destinations []cc_client.Destination `json:"destinations"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	for _, destination := range request.Destinations {
+		if s.apps[destination.App.GUID] == nil {
+			writeUnprocessable(w, "App(s) with guid(s) \""+destination.App.GUID+"\" do not exist or you do not have access.")
+			return
+		}
+	}
+	for _, destination := range request.Destinations {
+		destination.GUID = newGUID()
+		if destination.App.Process == nil {
+			destination.App.Process = &cc_client.DestinationProcess{Type: "web"}
+		}
+		if destination.Port == 0 {
+			destination.Port = 8080
+		}
+		if destination.Protocol == "" {
+			destination.Protocol = "http1"
+		}
+		rt.Destinations = append(rt.Destinations, destination)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"destinations": rt.Destinations})
+}
+
+func (s *Server) entitled(isolationSegmentGUID, orgGUID string) bool {
+	isolationSegment, exists := s.isolationSegments[isolationSegmentGUID]
+	return exists && isolationSegment.orgGUIDs[orgGUID]
+}
+
+func (s *Server) listIsolationSegments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var resources []interface{}
+	for _, isolationSegment := range sortedBy(s.isolationSegments, func(i *isolationSegment) string { return i.Name }) {
+		if !matches(query, "names", isolationSegment.Name) || !matches(query, "guids", isolationSegment.GUID) || !matchesLabels(query, isolationSegment.Metadata) {
+			continue
+		}
+		if orgGUIDs := query.Get("organization_guids"); orgGUIDs != "" {
+			entitled := false
+			for _, orgGUID := range strings.Split(orgGUIDs, ",") {
+				entitled = entitled || isolationSegment.orgGUIDs[orgGUID]
+			}
+			if !entitled {
+				continue
+			}
+		}
+		resources = append(resources, isolationSegment.IsolationSegment)
+	}
+	s.writeList(w, r, resources, len(resources))
+}
+
+func (s *Server) createIsolationSegment(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Name     string              `json:"name"`
+		Metadata *cc_client.Metadata `json:"metadata"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	for _, existing := range s.isolationSegments {
+		if strings.EqualFold(existing.Name, request.Name) {
+			writeUnprocessable(w, "Name must be unique")
+			return
+		}
+	}
+	isolationSegment := &isolationSegment{orgGUIDs: map[string]bool{}}
+	isolationSegment.GUID = newGUID()
+	isolationSegment.Name = request.Name
+	mergeMetadata(&isolationSegment.Metadata, request.Metadata)
+	s.isolationSegments[isolationSegment.GUID] = isolationSegment
+	writeJSON(w, http.StatusCreated, isolationSegment.IsolationSegment)
+}
+
+func (s *Server) getIsolationSegment(w http.ResponseWriter, r *http.Request) {
+	isolationSegment, ok := mustFind(w, s.isolationSegments, r.PathValue("guid"), "Isolation segment")
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, isolationSegment.IsolationSegment)
+}
+
+func (s *Server) deleteIsolationSegment(w http.ResponseWriter, r *http.Request) {
+	isolationSegment, ok := mustFind(w, s.isolationSegments, r.PathValue("guid"), "Isolation segment")
+	if !ok {
+		return
+	}
+	if len(isolationSegment.orgGUIDs) > 0 {
+		writeUnprocessable(w, "Revoke the Organization entitlements for your Isolation Segment.")
+		return
+	}
+	delete(s.isolationSegments, isolationSegment.GUID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) entitleOrganizations(w http.ResponseWriter, r *http.Request) {
+	isolationSegment, ok := mustFind(w, s.isolationSegments, r.PathValue("guid"), "Isolation segment")
+	if !ok {
+		return
+	}
+	var request cc_client.ToMany
+	if !readJSON(w, r, &request) {
+		return
+	}
+	for _, org := range request.Data {
+		if s.orgs[org.GUID] == nil {
+			writeUnprocessable(w, "Organization with guid '"+org.GUID+"' does not exist, or you do not have access to it.")
+			return
+		}
+	}
+	for _, org := range request.Data {
+		isolationSegment.orgGUIDs[org.GUID] = true
+	}
+
+	var entitled cc_client.ToMany
+	for _, orgGUID := range sortedKeys(isolationSegment.orgGUIDs) {
+		entitled.Data = append(entitled.Data, cc_client.Ref{GUID: orgGUID})
+	}
+	writeJSON(w, http.StatusOK, entitled)
+}
+
+func (s *Server) revokeOrganization(w http.ResponseWriter, r *http.Request) {
+	isolationSegment, ok := mustFind(w, s.isolationSegments, r.PathValue("guid"), "Isolation segment")
+	if !ok {
+		return
+	}
+	orgGUID := r.PathValue("org_guid")
+	if org := s.orgs[orgGUID]; org != nil && org.defaultIsolationSegmentGUID == isolationSegment.GUID {
+		writeUnprocessable(w, "Cannot remove the entitlement of the default isolation segment of an organization.")
+		return
+	}
+	delete(isolationSegment.orgGUIDs, orgGUID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serviceInstanceFor returns the service instance after polling its operation, or nil if the
+// instance was deleted.
+func (s *Server) serviceInstanceFor(guid string) *serviceInstance {
+	instance, exists := s.serviceInstances[guid]
+	if !exists {
+		return nil
+	}
+	instance.operation.poll()
+	instance.LastOperation.State = instance.operation.state
+	if instance.operation.state == failed {
+		instance.LastOperation.Description = instance.operation.failure
+	}
+	return s.serviceInstances[guid]
+}
+
+func (s *Server) listServiceInstances(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var resources []interface{}
+	for _, instance := range sortedBy(s.serviceInstances, func(i *serviceInstance) string { return i.Name }) {
+		instance = s.serviceInstanceFor(instance.GUID)
+		if instance != nil && matches(query, "names", instance.Name) && matches(query, "space_guids", instance.spaceGUID) && matchesLabels(query, instance.Metadata) {
+			resources = append(resources, instance)
+		}
+	}
+	s.writeList(w, r, resources, len(resources))
+}
+
+// createServiceInstance creates user-provided instances right away, and managed ones with a job.
+func (s *Server) createServiceInstance(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Type          string `json:"type"`
+		Name          string `json:"name"`
+		Relationships struct {
+			Space cc_client.ToOne `json:"space"`
+		} `json:"relationships"`
+		Metadata *cc_client.Metadata `json:"metadata"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	spaceData := request.Relationships.Space.Data
+	if spaceData == nil || s.spaces[spaceData.GUID] == nil {
+		writeUnprocessable(w, "Invalid space. Ensure that the space exists and you have access to it.")
+		return
+	}
+	for _, existing := range s.serviceInstances {
+		if existing.spaceGUID == spaceData.GUID && existing.Name == request.Name {
+			writeUnprocessable(w, "The service instance name is taken: "+request.Name+".")
+			return
+		}
+	}
+
+	instance := &serviceInstance{GUID: newGUID(), Name: request.Name, Type: request.Type, spaceGUID: spaceData.GUID}
+	instance.LastOperation.Type = "create"
+	mergeMetadata(&instance.Metadata, request.Metadata)
+	s.serviceInstances[instance.GUID] = instance
+
+	if request.Type == "user-provided" {
+		instance.operation = &operation{state: succeeded}
+		instance.LastOperation.State = succeeded
+		writeJSON(w, http.StatusCreated, instance)
+		return
+	}
+	instance.operation = s.startOperation(r, nil)
+	instance.LastOperation.State = inProgress
+	s.writeJob(w, "service_instance.create", instance.operation)
+}
+
+func (s *Server) getServiceInstance(w http.ResponseWriter, r *http.Request) {
+	instance := s.serviceInstanceFor(r.PathValue("guid"))
+	if instance == nil {
+		writeNotFound(w, "Service instance")
+		return
+	}
+	writeJSON(w, http.StatusOK, instance)
+}
+
+func (s *Server) updateServiceInstance(w http.ResponseWriter, r *http.Request) {
+	instance := s.serviceInstanceFor(r.PathValue("guid"))
+	if instance == nil {
+		writeNotFound(w, "Service instance")
+		return
+	}
+	var request struct {
+		Metadata *cc_client.MetadataUpdate `json:"metadata"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	updateMetadata(&instance.Metadata, request.Metadata)
+	writeJSON(w, http.StatusOK, instance)
+}
+
+func (s *Server) deleteServiceInstance(w http.ResponseWriter, r *http.Request) {
+	instance := s.serviceInstanceFor(r.PathValue("guid"))
+	if instance == nil {
+		writeNotFound(w, "Service instance")
+		return
+	}
+	if instance.operation.state == inProgress {
+		writeUnprocessable(w, "An operation for service instance "+instance.Name+" is in progress.")
+		return
+	}
+
+	guid := instance.GUID
+	if instance.Type == "user-provided" {
+		delete(s.serviceInstances, guid)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	instance.LastOperation = lastOperation{Type: "delete", State: inProgress}
+	instance.operation = s.startOperation(r, func() { delete(s.serviceInstances, guid) })
+	s.writeJob(w, "service_instance.delete", instance.operation)
+}
+
+// serviceCredentialBindingFor returns the binding after polling its operation, or nil if the
+// binding was deleted.
+func (s *Server) serviceCredentialBindingFor(guid string) *serviceCredentialBinding {
+	binding, exists := s.serviceCredentialBindings[guid]
+	if !exists {
+		return nil
+	}
+	binding.operation.poll()
+	binding.LastOperation.State = binding.operation.state
+	if binding.operation.state == failed {
+		binding.LastOperation.Description = binding.operation.failure
+	}
+	return s.serviceCredentialBindings[guid]
+}
+
+func (s *Server) createServiceCredentialBinding(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Type          string `json:"type"`
+		Name          string `json:"name"`
+		Relationships struct {
+			ServiceInstance cc_client.ToOne `json:"service_instance"`
+			App             cc_client.ToOne `json:"app"`
+		} `json:"relationships"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	instanceData := request.Relationships.ServiceInstance.Data
+	if instanceData == nil || s.serviceInstances[instanceData.GUID] == nil {
+		writeUnprocessable(w, "The service instance could not be found.")
+		return
+	}
+	if appData := request.Relationships.App.Data; request.Type == "app" && (appData == nil || s.apps[appData.GUID] == nil) {
+		writeUnprocessable(w, "The app could not be found.")
+		return
+	}
+
+	binding := &serviceCredentialBinding{GUID: newGUID(), Name: request.Name, Type: request.Type}
+	binding.LastOperation = lastOperation{Type: "create", State: inProgress}
+	binding.operation = s.startOperation(r, nil)
+	s.serviceCredentialBindings[binding.GUID] = binding
+	s.writeJob(w, "service_bindings.create", binding.operation)
+}
+
+func (s *Server) getServiceCredentialBinding(w http.ResponseWriter, r *http.Request) {
+	binding := s.serviceCredentialBindingFor(r.PathValue("guid"))
+	if binding == nil {
+		writeNotFound(w, "Service credential binding")
+		return
+	}
+	writeJSON(w, http.StatusOK, binding)
+}
+
+func (s *Server) deleteServiceCredentialBinding(w http.ResponseWriter, r *http.Request) {
+	binding := s.serviceCredentialBindingFor(r.PathValue("guid"))
+	if binding == nil {
+		writeNotFound(w, "Service credential binding")
+		return
+	}
+	guid := binding.GUID
+	binding.LastOperation = lastOperation{Type: "delete", State: inProgress}
+	binding.operation = s.startOperation(r, func() { delete(s.serviceCredentialBindings, guid) })
+	s.writeJob(w, "service_bindings.delete", binding.operation)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	var keys []string
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,204 @@
+package fake_cc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const tokenLifetime = time.Hour
+
+type tokenInfo struct {
+	UserName string
+	ClientID string
+}
+
+// AddUser lets the user authenticate with the password grant, e.g. with "cf auth".
+func (s *Server) AddUser(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[username] = password
+}
+
+// AddClient lets the client authenticate with the client credentials grant, e.g. with
+// "cf auth --client-credentials".
+func (s *Server) AddClient(clientID, secret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clients[clientID] = secret
+}
+
+// Token returns an Authorization header value for the user, as "cf oauth-token" would print it.
+// The user does not need to have been added.
+func (s *Server) Token(username string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return "bearer " + s.issueToken(tokenInfo{UserName: username, ClientID: "cf"})
+}
+
+func (s *Server) routeUAA() {
+	s.mux.HandleFunc("GET /{$}", s.getRoot)
+	s.mux.HandleFunc("GET /v2/info", s.getV2Info)
+	s.mux.HandleFunc("GET /v3", s.getV3Root)
+	s.mux.HandleFunc("GET /login", s.getLoginInfo)
+	s.mux.HandleFunc("POST /oauth/token", s.postToken)
+}
+
+func (s *Server) getRoot(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"links": map[string]interface{}{
+			"self":                s.link(""),
+			"cloud_controller_v2": map[string]interface{}{"href": s.server.URL + "/v2", "meta": map[string]string{"version": "2.260.0"}},
+			"cloud_controller_v3": map[string]interface{}{"href": s.server.URL + "/v3", "meta": map[string]string{"version": "3.195.0"}},
+			"network_policy_v0":   nil,
+			"network_policy_v1":   nil,
+			"login":               s.link(""),
+			"uaa":                 s.link(""),
+			"credhub":             nil,
+			"routing":             nil,
+			"logging":             nil,
+			"log_cache":           s.link(""),
+			"log_stream":          nil,
+			"app_ssh":             nil,
+		},
+	})
+}
+
+func (s *Server) getV2Info(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"name":                   "fake_cc",
+		"api_version":            "2.260.0",
+		"authorization_endpoint": s.server.URL,
+		"token_endpoint":         s.server.URL,
+		"min_cli_version":        nil,
+	})
+}
+
+func (s *Server) getV3Root(w http.ResponseWriter, r *http.Request) {
+	links := map[string]interface{}{"self": s.link("/v3")}
+	for _, resource := range []string{"apps", "builds", "deployments", "domains", "droplets", "isolation_segments", "organizations", "packages", "processes", "routes", "service_credential_bindings", "service_instances", "spaces"} {
+		links[resource] = s.link("/v3/" + resource)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"links": links})
+}
+
+func (s *Server) getLoginInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"app":   map[string]string{"version": "fake"},
+		"links": map[string]string{"uaa": s.server.URL, "login": s.server.URL},
+		"prompts": map[string][]string{
+			"username": {"text", "Email"},
+			"password": {"password", "Password"},
+		},
+	})
+}
+
+func (s *Server) postToken(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		writeTokenError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+
+	var info tokenInfo
+	switch r.PostForm.Get("grant_type") {
+	case "password":
+		username := r.PostForm.Get("username")
+		password, exists := s.users[username]
+		if !exists || password != r.PostForm.Get("password") {
+			writeTokenError(w, http.StatusUnauthorized, "unauthorized", "Bad credentials")
+			return
+		}
+		info = tokenInfo{UserName: username, ClientID: clientID}
+	case "client_credentials":
+		secret, exists := s.clients[clientID]
+		if !exists || secret != clientSecret {
+			writeTokenError(w, http.StatusUnauthorized, "invalid_client", "Bad credentials")
+			return
+		}
+		info = tokenInfo{ClientID: clientID}
+	case "refresh_token":
+		refreshed, exists := s.refreshTokens[r.PostForm.Get("refresh_token")]
+		if !exists {
+			writeTokenError(w, http.StatusUnauthorized, "invalid_token", "Invalid refresh token")
+			return
+		}
+		info = refreshed
+	default:
+		writeTokenError(w, http.StatusBadRequest, "unsupported_grant_type", "Unsupported grant type")
+		return
+	}
+
+	response := map[string]interface{}{
+		"access_token": s.issueToken(info),
+		"token_type":   "bearer",
+		"expires_in":   int(tokenLifetime.Seconds()),
+		"scope":        "cloud_controller.admin openid",
+		"jti":          newGUID(),
+	}
+	if info.UserName != "" {
+		refreshToken := newGUID() + "-r"
+		s.refreshTokens[refreshToken] = info
+		response["refresh_token"] = refreshToken
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func writeTokenError(w http.ResponseWriter, status int, code, description string) {
+	writeJSON(w, status, map[string]string{"error": code, "error_description": description})
+}
+
+// issueToken returns a new JWT for info, since the cf CLI reads the user and expiry from the
+// token. Its signature is never checked.
+func (s *Server) issueToken(info tokenInfo) string {
+	encode := func(v interface{}) string {
+		encoded, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(encoded)
+	}
+
+	issuedAt := time.Now()
+	claims := map[string]interface{}{
+		"jti":       newGUID(),
+		"client_id": info.ClientID,
+		"cid":       info.ClientID,
+		"scope":     []string{"cloud_controller.admin", "openid"},
+		"iat":       issuedAt.Unix(),
+		"exp":       issuedAt.Add(tokenLifetime).Unix(),
+		"iss":       s.server.URL + "/oauth/token",
+	}
+	if info.UserName != "" {
+		claims["user_name"] = info.UserName
+		claims["user_id"] = info.UserName
+		claims["origin"] = "uaa"
+		claims["grant_type"] = "password"
+	} else {
+		claims["sub"] = info.ClientID
+		claims["grant_type"] = "client_credentials"
+	}
+
+	unsigned := encode(map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + encode(claims)
+	mac := hmac.New(sha256.New, []byte("fake_cc"))
+	mac.Write([]byte(unsigned))
+	token := unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+
+	s.tokens[token] = info
+	return token
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "bearer") {
+		return false
+	}
+	_, exists := s.tokens[strings.TrimSpace(token)]
+	return exists
+}
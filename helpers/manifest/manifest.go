@@ -0,0 +1,207 @@
+// Package manifest builds app manifests for cf push -f and the manifest endpoints of the v3 API.
+package manifest
+
+import (
+	"os"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Manifest is a manifest for one or more apps in a space.
+type Manifest struct {
+	Applications []Application `yaml:"applications"`
+}
+
+// Application is an app in a manifest. Fields left empty are left out of the manifest, so that
+// pushing or applying it keeps their current values. The fields shared with Process configure
+// the web process of the app.
+type Application struct {
+	Name       string            `yaml:"name"`
+	Path       string            `yaml:"path,omitempty"`
+	Buildpacks []string          `yaml:"buildpacks,omitempty"`
+	Stack      string            `yaml:"stack,omitempty"`
+	Docker     *Docker           `yaml:"docker,omitempty"`
+	Env        map[string]string `yaml:"env,omitempty"`
+	Features   map[string]bool   `yaml:"features,omitempty"`
+	Metadata   *Metadata         `yaml:"metadata,omitempty"`
+
+	Command               string `yaml:"command,omitempty"`
+	Memory                string `yaml:"memory,omitempty"`
+	DiskQuota             string `yaml:"disk_quota,omitempty"`
+	Instances             *int   `yaml:"instances,omitempty"`
+	LogRateLimitPerSecond string `yaml:"log-rate-limit-per-second,omitempty"`
+
+	HealthCheckType              string `yaml:"health-check-type,omitempty"`
+	HealthCheckHTTPEndpoint      string `yaml:"health-check-http-endpoint,omitempty"`
+	HealthCheckInvocationTimeout int    `yaml:"health-check-invocation-timeout,omitempty"`
+	HealthCheckInterval          int    `yaml:"health-check-interval,omitempty"`
+	// Timeout is the number of seconds the process has to pass its health check when it starts.
+	Timeout int `yaml:"timeout,omitempty"`
+
+	ReadinessHealthCheckType              string `yaml:"readiness-health-check-type,omitempty"`
+	ReadinessHealthCheckHTTPEndpoint      string `yaml:"readiness-health-check-http-endpoint,omitempty"`
+	ReadinessHealthCheckInvocationTimeout int    `yaml:"readiness-health-check-invocation-timeout,omitempty"`
+	ReadinessHealthCheckInterval          int    `yaml:"readiness-health-check-interval,omitempty"`
+
+	Routes      []Route `yaml:"routes,omitempty"`
+	NoRoute     bool    `yaml:"no-route,omitempty"`
+	RandomRoute bool    `yaml:"random-route,omitempty"`
+
+	Processes []Process `yaml:"processes,omitempty"`
+	Sidecars  []Sidecar `yaml:"sidecars,omitempty"`
+	Services  []Service `yaml:"services,omitempty"`
+}
+
+// Docker is the image of an app that is pushed as a Docker image rather than from its bits.
+type Docker struct {
+	Image    string `yaml:"image"`
+	Username string `yaml:"username,omitempty"`
+}
+
+type Metadata struct {
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// Route is a route of an app, e.g. "app.example.com/path" or "tcp.example.com:1024".
+type Route struct {
+	Route    string        `yaml:"route"`
+	Protocol string        `yaml:"protocol,omitempty"`
+	Options  *RouteOptions `yaml:"options,omitempty"`
+}
+
+// RouteOptions are the per-route options of a route, such as its load balancing algorithm.
+type RouteOptions struct {
+	LoadBalancing string `yaml:"loadbalancing,omitempty"`
+	HashHeader    string `yaml:"hash_header,omitempty"`
+	HashBalance   string `yaml:"hash_balance,omitempty"`
+}
+
+// Process is a process type of an app, such as "web" or "worker".
+type Process struct {
+	Type                  string `yaml:"type"`
+	Command               string `yaml:"command,omitempty"`
+	Memory                string `yaml:"memory,omitempty"`
+	DiskQuota             string `yaml:"disk_quota,omitempty"`
+	Instances             *int   `yaml:"instances,omitempty"`
+	LogRateLimitPerSecond string `yaml:"log-rate-limit-per-second,omitempty"`
+
+	HealthCheckType              string `yaml:"health-check-type,omitempty"`
+	HealthCheckHTTPEndpoint      string `yaml:"health-check-http-endpoint,omitempty"`
+	HealthCheckInvocationTimeout int    `yaml:"health-check-invocation-timeout,omitempty"`
+	HealthCheckInterval          int    `yaml:"health-check-interval,omitempty"`
+	// Timeout is the number of seconds the process has to pass its health check when it starts.
+	Timeout int `yaml:"timeout,omitempty"`
+
+	ReadinessHealthCheckType              string `yaml:"readiness-health-check-type,omitempty"`
+	ReadinessHealthCheckHTTPEndpoint      string `yaml:"readiness-health-check-http-endpoint,omitempty"`
+	ReadinessHealthCheckInvocationTimeout int    `yaml:"readiness-health-check-invocation-timeout,omitempty"`
+	ReadinessHealthCheckInterval          int    `yaml:"readiness-health-check-interval,omitempty"`
+}
+
+// Sidecar is an additional command run in the containers of the processes with its ProcessTypes.
+type Sidecar struct {
+	Name         string   `yaml:"name"`
+	ProcessTypes []string `yaml:"process_types"`
+	Command      string   `yaml:"command"`
+	Memory       string   `yaml:"memory,omitempty"`
+}
+
+// Service is a service instance the app is bound to, with the name and arbitrary parameters of
+// the binding.
+type Service struct {
+	Name        string                 `yaml:"name"`
+	BindingName string                 `yaml:"binding_name,omitempty"`
+	Parameters  map[string]interface{} `yaml:"parameters,omitempty"`
+}
+
+// Instances returns a number of instances for an Application or Process, which can be 0.
+func Instances(n int) *int {
+	return &n
+}
+
+// WithLabels returns a copy of the manifest in which every application also has the given metadata
+// labels. Labels the application already sets are kept.
+func (m Manifest) WithLabels(labels map[string]string) Manifest {
+	labelled := Manifest{Applications: make([]Application, len(m.Applications))}
+	for i, app := range m.Applications {
+		metadata := Metadata{Labels: map[string]string{}}
+		if app.Metadata != nil {
+			metadata.Annotations = app.Metadata.Annotations
+			for key, value := range app.Metadata.Labels {
+				metadata.Labels[key] = value
+			}
+		}
+		for key, value := range labels {
+			if _, ok := metadata.Labels[key]; !ok {
+				metadata.Labels[key] = value
+			}
+		}
+
+		app.Metadata = &metadata
+		labelled.Applications[i] = app
+	}
+	return labelled
+}
+
+// LabelYAML adds labels to every application of a manifest in YAML, like WithLabels, for manifests
+// that are not built as a Manifest. The rest of the manifest is kept as it is.
+func LabelYAML(contents []byte, labels map[string]string) ([]byte, error) {
+	var m map[string]interface{}
+	err := yaml.Unmarshal(contents, &m)
+	if err != nil {
+		return nil, err
+	}
+
+	applications, _ := m["applications"].([]interface{})
+	for _, application := range applications {
+		app, ok := application.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		metadata, _ := app["metadata"].(map[string]interface{})
+		if metadata == nil {
+			metadata = map[string]interface{}{}
+		}
+		appLabels, _ := metadata["labels"].(map[string]interface{})
+		if appLabels == nil {
+			appLabels = map[string]interface{}{}
+		}
+		for key, value := range labels {
+			if _, ok := appLabels[key]; !ok {
+				appLabels[key] = value
+			}
+		}
+		metadata["labels"] = appLabels
+		app["metadata"] = metadata
+	}
+
+	return yaml.Marshal(m)
+}
+
+// Marshal returns the manifest as YAML.
+func (m Manifest) Marshal() ([]byte, error) {
+	return yaml.Marshal(m)
+}
+
+// WriteTempFile writes the manifest to a new file in the temporary directory and returns its
+// path, which the caller removes.
+func (m Manifest) WriteTempFile() (string, error) {
+	contents, err := m.Marshal()
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.CreateTemp("", "manifest-*.yml")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	_, err = file.Write(contents)
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
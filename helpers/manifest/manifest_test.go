@@ -0,0 +1,164 @@
+package manifest_test
+
+import (
+	"os"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/manifest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Manifest", func() {
+	manifest := Manifest{Applications: []Application{
+		{
+			Name:       "web-app",
+			Path:       "/assets/catnip",
+			Buildpacks: []string{"binary_buildpack"},
+			Env:        map[string]string{"FOO": "bar"},
+			Routes:     []Route{{Route: "web-app.example.com"}},
+			Processes: []Process{
+				{Type: "web", Command: "./catnip", Memory: "256M"},
+				{Type: "worker", Command: "sleep infinity", Instances: Instances(0), HealthCheckType: "process"},
+			},
+			Sidecars: []Sidecar{{Name: "sidecar", ProcessTypes: []string{"web"}, Command: "sleep infinity"}},
+			Services: []Service{{Name: "some-service"}},
+		},
+		{Name: "other-app"},
+	}}
+
+	It("marshals to the YAML of cf push, leaving out empty fields", func() {
+		Expect(manifest.Marshal()).To(MatchYAML(`
+applications:
+- name: web-app
+  path: /assets/catnip
+  buildpacks: [binary_buildpack]
+  env: {FOO: bar}
+  routes:
+  - route: web-app.example.com
+  processes:
+  - type: web
+    command: ./catnip
+    memory: 256M
+  - type: worker
+    command: sleep infinity
+    instances: 0
+    health-check-type: process
+  sidecars:
+  - name: sidecar
+    process_types: [web]
+    command: sleep infinity
+  services:
+  - name: some-service
+- name: other-app
+`))
+	})
+
+	It("marshals route options, health checks, bindings and metadata", func() {
+		manifest := Manifest{Applications: []Application{{
+			Name:                    "dora",
+			Features:                map[string]bool{"ssh": false},
+			Metadata:                &Metadata{Labels: map[string]string{"tier": "web"}, Annotations: map[string]string{"owner": "cats"}},
+			Memory:                  "256M",
+			Instances:               Instances(2),
+			HealthCheckType:         "http",
+			HealthCheckHTTPEndpoint: "/health",
+			Timeout:                 60,
+			Routes: []Route{
+				{Route: "dora.example.com", Options: &RouteOptions{LoadBalancing: "hash", HashHeader: "X-Hash-Header"}},
+				{Route: "tcp.example.com:1024", Protocol: "tcp"},
+			},
+			Processes: []Process{{
+				Type:                             "worker",
+				DiskQuota:                        "1G",
+				LogRateLimitPerSecond:            "16K",
+				HealthCheckType:                  "process",
+				ReadinessHealthCheckType:         "http",
+				ReadinessHealthCheckHTTPEndpoint: "/ready",
+				ReadinessHealthCheckInterval:     1,
+			}},
+			Services: []Service{{Name: "some-service", BindingName: "binding", Parameters: map[string]interface{}{"key": "value"}}},
+		}}}
+
+		Expect(manifest.Marshal()).To(MatchYAML(`
+applications:
+- name: dora
+  features: {ssh: false}
+  metadata:
+    labels: {tier: web}
+    annotations: {owner: cats}
+  memory: 256M
+  instances: 2
+  health-check-type: http
+  health-check-http-endpoint: /health
+  timeout: 60
+  routes:
+  - route: dora.example.com
+    options: {loadbalancing: hash, hash_header: X-Hash-Header}
+  - route: tcp.example.com:1024
+    protocol: tcp
+  processes:
+  - type: worker
+    disk_quota: 1G
+    log-rate-limit-per-second: 16K
+    health-check-type: process
+    readiness-health-check-type: http
+    readiness-health-check-http-endpoint: /ready
+    readiness-health-check-interval: 1
+  services:
+  - name: some-service
+    binding_name: binding
+    parameters: {key: value}
+`))
+	})
+
+	It("adds labels to every application, keeping the labels it sets", func() {
+		labelled := manifest.WithLabels(map[string]string{"run": "1", "tier": "ignored"})
+
+		Expect(labelled.Applications).To(HaveLen(2))
+		Expect(labelled.Applications[0].Metadata).To(Equal(&Metadata{Labels: map[string]string{"run": "1", "tier": "ignored"}}))
+
+		app := Application{Name: "app", Metadata: &Metadata{Labels: map[string]string{"tier": "web"}, Annotations: map[string]string{"owner": "cats"}}}
+		labelled = Manifest{Applications: []Application{app}}.WithLabels(map[string]string{"run": "1", "tier": "ignored"})
+		Expect(labelled.Applications[0].Metadata).To(Equal(&Metadata{
+			Labels:      map[string]string{"run": "1", "tier": "web"},
+			Annotations: map[string]string{"owner": "cats"},
+		}))
+		Expect(app.Metadata.Labels).To(Equal(map[string]string{"tier": "web"}), "the original manifest is not changed")
+	})
+
+	It("adds labels to every application of a manifest in YAML, keeping the rest of it", func() {
+		labelled, err := LabelYAML([]byte(`
+applications:
+- name: web-app
+  random-route: true
+  metadata:
+    labels: {tier: web}
+    annotations: {owner: cats}
+- name: other-app
+`), map[string]string{"run": "1", "tier": "ignored"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(labelled).To(MatchYAML(`
+applications:
+- name: web-app
+  random-route: true
+  metadata:
+    labels: {run: "1", tier: web}
+    annotations: {owner: cats}
+- name: other-app
+  metadata:
+    labels: {run: "1", tier: ignored}
+`))
+	})
+
+	It("writes to a temporary file", func() {
+		path, err := manifest.WriteTempFile()
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(os.Remove, path)
+
+		expected, err := manifest.Marshal()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.ReadFile(path)).To(Equal(expected))
+	})
+})
@@ -2,6 +2,14 @@ package random_name
 
 import "github.com/cloudfoundry/cf-test-helpers/v2/generator"
 
+var prefix = "CATS"
+
+// SetPrefix makes CATSRandomName generate names starting with prefix, i.e. the configured
+// name_prefix, instead of "CATS".
+func SetPrefix(namePrefix string) {
+	prefix = namePrefix
+}
+
 func CATSRandomName(resource string) string {
-	return generator.PrefixedRandomName("CATS", resource)
+	return generator.PrefixedRandomName(prefix, resource)
 }
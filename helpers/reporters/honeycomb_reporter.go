@@ -0,0 +1,121 @@
+package reporters
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+const DefaultHoneyCombAPIHost = "https://api.honeycomb.io"
+
+type HoneyCombReporter struct {
+	apiHost  string
+	writeKey string
+	dataset  string
+	client   *http.Client
+
+	tagsLock   sync.RWMutex
+	globalTags map[string]interface{}
+}
+
+// NewHoneyCombReporter returns a reporter that sends one event per spec to the
+// Honeycomb events API. An empty apiHost falls back to DefaultHoneyCombAPIHost.
+func NewHoneyCombReporter(apiHost, writeKey, dataset string, customTags map[string]interface{}) *HoneyCombReporter {
+	if apiHost == "" {
+		apiHost = DefaultHoneyCombAPIHost
+	}
+
+	globalTags := map[string]interface{}{}
+	for k, v := range customTags {
+		globalTags[k] = v
+	}
+
+	return &HoneyCombReporter{
+		apiHost:    strings.TrimSuffix(apiHost, "/"),
+		writeKey:   writeKey,
+		dataset:    dataset,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		globalTags: globalTags,
+	}
+}
+
+// SetGlobalTag adds a field that is sent along with every subsequent event.
+func (r *HoneyCombReporter) SetGlobalTag(key string, value interface{}) {
+	r.tagsLock.Lock()
+	defer r.tagsLock.Unlock()
+	r.globalTags[key] = value
+}
+
+func (r *HoneyCombReporter) ReportSpec(report types.SpecReport) error {
+	body, err := json.Marshal(r.eventForSpec(report))
+	if err != nil {
+		return err
+	}
+
+	eventURL := fmt.Sprintf("%s/1/events/%s", r.apiHost, url.PathEscape(r.dataset))
+	req, err := http.NewRequest(http.MethodPost, eventURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Honeycomb-Team", r.writeKey)
+	req.Header.Set("X-Honeycomb-Event-Time", report.EndTime.UTC().Format(time.RFC3339Nano))
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("honeycomb responded with status %d for spec %q", resp.StatusCode, report.FullText())
+	}
+
+	return nil
+}
+
+func (r *HoneyCombReporter) eventForSpec(report types.SpecReport) map[string]interface{} {
+	event := map[string]interface{}{}
+
+	r.tagsLock.RLock()
+	for k, v := range r.globalTags {
+		event[k] = v
+	}
+	r.tagsLock.RUnlock()
+
+	event["suite"] = SuiteTag(report)
+	event["spec"] = report.FullText()
+	event["state"] = report.State.String()
+	event["duration_seconds"] = report.RunTime.Seconds()
+	event["parallel_process"] = report.ParallelProcess
+	event["num_attempts"] = report.NumAttempts
+
+	if report.Failed() {
+		event["failure_location"] = report.Failure.Location.String()
+		event["failure_message"] = report.Failure.Message
+	}
+
+	return event
+}
+
+// SuiteTag returns the bracketed test group prefix (e.g. "[apps]") that the
+// cats_suite_helpers Describe wrappers put at the top of every spec.
+func SuiteTag(report types.SpecReport) string {
+	if len(report.ContainerHierarchyTexts) == 0 {
+		return ""
+	}
+
+	tag := report.ContainerHierarchyTexts[0]
+	if !strings.HasPrefix(tag, "[") || !strings.HasSuffix(tag, "]") {
+		return ""
+	}
+
+	return tag
+}
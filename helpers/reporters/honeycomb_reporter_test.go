@@ -0,0 +1,130 @@
+package reporters_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/reporters"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+	. "github.com/onsi/gomega"
+)
+
+type receivedEvent struct {
+	path    string
+	headers http.Header
+	body    map[string]interface{}
+}
+
+var _ = Describe("HoneyCombReporter", func() {
+	var (
+		server     *httptest.Server
+		events     chan receivedEvent
+		statusCode int
+		reporter   *HoneyCombReporter
+		specReport types.SpecReport
+	)
+
+	BeforeEach(func() {
+		events = make(chan receivedEvent, 10)
+		statusCode = http.StatusOK
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			bytes, err := io.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+
+			var body map[string]interface{}
+			Expect(json.Unmarshal(bytes, &body)).To(Succeed())
+			events <- receivedEvent{path: r.URL.Path, headers: r.Header, body: body}
+
+			w.WriteHeader(statusCode)
+		}))
+
+		reporter = NewHoneyCombReporter(server.URL, "some-write-key", "some-dataset", map[string]interface{}{
+			"environment": "some-env",
+		})
+
+		specReport = types.SpecReport{
+			ContainerHierarchyTexts: []string{"[apps]", "Application Lifecycle"},
+			LeafNodeText:            "pushes an app",
+			State:                   types.SpecStatePassed,
+			RunTime:                 90 * time.Second,
+			EndTime:                 time.Now(),
+			ParallelProcess:         3,
+			NumAttempts:             1,
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("posts the spec to the dataset with the write key", func() {
+		Expect(reporter.ReportSpec(specReport)).To(Succeed())
+
+		var event receivedEvent
+		Eventually(events).Should(Receive(&event))
+		Expect(event.path).To(Equal("/1/events/some-dataset"))
+		Expect(event.headers.Get("X-Honeycomb-Team")).To(Equal("some-write-key"))
+		Expect(event.headers.Get("Content-Type")).To(Equal("application/json"))
+	})
+
+	It("describes the spec and includes the custom tags", func() {
+		reporter.SetGlobalTag("cf_cli_version", "8.14.0")
+		Expect(reporter.ReportSpec(specReport)).To(Succeed())
+
+		var event receivedEvent
+		Eventually(events).Should(Receive(&event))
+		Expect(event.body).To(HaveKeyWithValue("suite", "[apps]"))
+		Expect(event.body).To(HaveKeyWithValue("spec", "[apps] Application Lifecycle pushes an app"))
+		Expect(event.body).To(HaveKeyWithValue("state", "passed"))
+		Expect(event.body).To(HaveKeyWithValue("duration_seconds", BeNumerically("==", 90)))
+		Expect(event.body).To(HaveKeyWithValue("parallel_process", BeNumerically("==", 3)))
+		Expect(event.body).To(HaveKeyWithValue("cf_cli_version", "8.14.0"))
+		Expect(event.body).To(HaveKeyWithValue("environment", "some-env"))
+		Expect(event.body).NotTo(HaveKey("failure_message"))
+	})
+
+	Context("when the spec failed", func() {
+		BeforeEach(func() {
+			specReport.State = types.SpecStateFailed
+			specReport.Failure = types.Failure{
+				Message:  "Expected app to be started",
+				Location: types.NewCodeLocation(0),
+			}
+		})
+
+		It("includes the failure location and message", func() {
+			Expect(reporter.ReportSpec(specReport)).To(Succeed())
+
+			var event receivedEvent
+			Eventually(events).Should(Receive(&event))
+			Expect(event.body).To(HaveKeyWithValue("state", "failed"))
+			Expect(event.body).To(HaveKeyWithValue("failure_message", "Expected app to be started"))
+			Expect(event.body).To(HaveKeyWithValue("failure_location", ContainSubstring("honeycomb_reporter_test.go")))
+		})
+	})
+
+	Context("when honeycomb rejects the event", func() {
+		BeforeEach(func() {
+			statusCode = http.StatusUnauthorized
+		})
+
+		It("returns an error", func() {
+			err := reporter.ReportSpec(specReport)
+			Expect(err).To(MatchError(ContainSubstring("status 401")))
+		})
+	})
+
+	Describe("SuiteTag", func() {
+		It("returns an empty tag for specs outside of a test group wrapper", func() {
+			specReport.ContainerHierarchyTexts = []string{"Application Lifecycle"}
+			Expect(SuiteTag(specReport)).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package reporters_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestReporters(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Reporters Suite")
+}
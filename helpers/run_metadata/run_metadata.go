@@ -0,0 +1,54 @@
+package run_metadata
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// The labels CATS puts in the metadata of the resources it creates.
+const (
+	RunIDLabel = "cats.cloudfoundry.org/run-id"
+	SuiteLabel = "cats.cloudfoundry.org/suite"
+)
+
+const runIDTimeFormat = "20060102-150405"
+
+// NewRunID returns an ID for a CATS run, e.g. "20261018-153000-1a2b3c4d", which sorts by the time
+// the run started and is a valid label value.
+func NewRunID() string {
+	b := make([]byte, 4)
+	_, err := rand.Read(b)
+	if err != nil {
+		panic(err)
+	}
+	return time.Now().UTC().Format(runIDTimeFormat) + "-" + hex.EncodeToString(b)
+}
+
+// StartedAt returns the time the run with the given ID started, to the second, or false if the ID
+// was not returned by NewRunID.
+func StartedAt(runID string) (time.Time, bool) {
+	if len(runID) < len(runIDTimeFormat) {
+		return time.Time{}, false
+	}
+	startedAt, err := time.Parse(runIDTimeFormat, runID[:len(runIDTimeFormat)])
+	return startedAt, err == nil
+}
+
+// LabelsFor returns the labels of a resource created in the run by a spec of the test group suite.
+// Empty values are left out.
+func LabelsFor(runID, suite string) map[string]string {
+	labels := map[string]string{}
+	if runID != "" {
+		labels[RunIDLabel] = runID
+	}
+	if suite != "" {
+		labels[SuiteLabel] = suite
+	}
+	return labels
+}
+
+// Selector returns a label selector for the resources created in the run.
+func Selector(runID string) string {
+	return RunIDLabel + "=" + runID
+}
@@ -0,0 +1,13 @@
+package run_metadata_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRunMetadata(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Run Metadata Suite")
+}
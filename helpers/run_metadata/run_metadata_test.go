@@ -0,0 +1,40 @@
+package run_metadata_test
+
+import (
+	"time"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/run_metadata"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RunMetadata", func() {
+	It("generates unique run IDs that are valid label values", func() {
+		first, second := NewRunID(), NewRunID()
+
+		Expect(first).To(MatchRegexp(`^\d{8}-\d{6}-[0-9a-f]{8}$`))
+		Expect(first).NotTo(Equal(second))
+	})
+
+	It("recovers the time a run started from its ID", func() {
+		startedAt, ok := StartedAt("20261018-153000-1a2b3c4d")
+		Expect(ok).To(BeTrue())
+		Expect(startedAt).To(Equal(time.Date(2026, 10, 18, 15, 30, 0, 0, time.UTC)))
+
+		_, ok = StartedAt("some-run")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("labels resources with the run ID and test group", func() {
+		Expect(LabelsFor("some-run", "apps")).To(Equal(map[string]string{
+			"cats.cloudfoundry.org/run-id": "some-run",
+			"cats.cloudfoundry.org/suite":  "apps",
+		}))
+		Expect(LabelsFor("some-run", "")).To(Equal(map[string]string{"cats.cloudfoundry.org/run-id": "some-run"}))
+	})
+
+	It("selects the resources of a run", func() {
+		Expect(Selector("some-run")).To(Equal("cats.cloudfoundry.org/run-id=some-run"))
+	})
+})
@@ -112,6 +112,7 @@ func (b ServiceBroker) Push(config cats_config.CatsConfig) {
 		"--health-check-type", "http",
 		"--endpoint", "/v2/catalog",
 	).Wait(Config.BrokerStartTimeoutDuration())).To(Exit(0))
+	LabelResourcesNamed("apps", b.Name)
 }
 
 func (b ServiceBroker) PushWithBuildpackAndManifest(config cats_config.CatsConfig, buildpackName string) {
@@ -124,6 +125,7 @@ func (b ServiceBroker) PushWithBuildpackAndManifest(config cats_config.CatsConfi
 		"--health-check-type", "http",
 		"--endpoint", "/v2/catalog",
 	).Wait(Config.BrokerStartTimeoutDuration())).To(Exit(0))
+	LabelResourcesNamed("apps", b.Name)
 }
 
 func (b ServiceBroker) GetApiInfoUrl() string {
@@ -156,6 +158,7 @@ func (b ServiceBroker) Create() {
 	workflowhelpers.AsUser(b.TestSetup.AdminUserContext(), Config.DefaultTimeoutDuration(), func() {
 		Expect(cf.Cf("create-service-broker", b.Name, "username", "password", helpers.AppUri(b.Name, "", Config)).Wait()).To(Exit(0))
 		Expect(cf.Cf("service-brokers").Wait()).To(Say(b.Name))
+		LabelResourcesNamed("service_brokers", b.Name)
 	})
 }
 
@@ -163,6 +166,7 @@ func (b ServiceBroker) CreateSpaceScoped() {
 	workflowhelpers.AsUser(b.TestSetup.RegularUserContext(), Config.DefaultTimeoutDuration(), func() {
 		Expect(cf.Cf("create-service-broker", b.Name, "username", "password", helpers.AppUri(b.Name, "", Config), "--space-scoped").Wait()).To(Exit(0))
 		Expect(cf.Cf("service-brokers").Wait()).To(Say(b.Name))
+		LabelResourcesNamed("service_brokers", b.Name)
 	})
 }
 
@@ -260,6 +264,7 @@ func (b ServiceBroker) CreateServiceInstance(instanceName string) string {
 	curl := cf.Cf("curl", url).Wait()
 	Expect(curl).To(Exit(0))
 	json.Unmarshal(curl.Out.Contents(), &serviceInstance)
+	LabelResource("/v3/service_instances/" + serviceInstance.Resources[0].Guid)
 	return serviceInstance.Resources[0].Guid
 }
 
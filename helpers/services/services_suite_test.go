@@ -0,0 +1,22 @@
+package services_test
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestServices(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Services Suite")
+}
+
+var _ = BeforeSuite(func() {
+	// The helpers read assets relative to the root of the repository, like the suites do.
+	wd, err := os.Getwd()
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(os.Chdir, wd)
+	Expect(os.Chdir("../..")).To(Succeed())
+})
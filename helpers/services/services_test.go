@@ -0,0 +1,74 @@
+package services_test
+
+import (
+	"encoding/json"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/services"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("services", func() {
+	Describe("ServiceBroker", func() {
+		var broker ServiceBroker
+
+		BeforeEach(func() {
+			broker = NewServiceBroker("broker", "assets/service_broker", nil)
+		})
+
+		It("generates a service and plans with unique names", func() {
+			Expect(broker.Service.Name).To(HavePrefix("CATS-"))
+			Expect(broker.Plans()).To(HaveLen(5))
+
+			names := map[string]bool{broker.Service.Name: true}
+			for _, plan := range broker.Plans() {
+				names[plan.Name] = true
+				Expect(broker.HasPlan(plan.Name)).To(BeTrue())
+			}
+			Expect(names).To(HaveLen(6))
+			Expect(broker.HasPlan("other-plan")).To(BeFalse())
+		})
+
+		It("configures the broker app to serve a catalog of its service and plans", func() {
+			var configuration struct {
+				Behaviors struct {
+					Catalog struct {
+						Body struct {
+							Services []struct {
+								Name  string `json:"name"`
+								ID    string `json:"id"`
+								Plans []struct {
+									Name string `json:"name"`
+									ID   string `json:"id"`
+								} `json:"plans"`
+							} `json:"services"`
+						} `json:"body"`
+					} `json:"catalog"`
+				} `json:"behaviors"`
+			}
+			Expect(json.Unmarshal([]byte(broker.ToJSON()), &configuration)).To(Succeed())
+
+			services := configuration.Behaviors.Catalog.Body.Services
+			Expect(services).To(HaveLen(1))
+			Expect(services[0].Name).To(Equal(broker.Service.Name))
+			Expect(services[0].ID).To(Equal(broker.Service.ID))
+
+			var expected []interface{}
+			for _, plan := range broker.Plans() {
+				expected = append(expected, SatisfyAll(HaveField("Name", plan.Name), HaveField("ID", plan.ID)))
+			}
+			Expect(services[0].Plans).To(ConsistOf(expected...))
+		})
+	})
+
+	It("reads the user-provided services of VCAP_SERVICES", func() {
+		var file VCAPServicesFile
+		Expect(file.ReadFromString(`{"user-provided": [{"name": "ups", "instance_guid": "some-guid", "credentials": {"username": "user"}}]}`)).To(Succeed())
+
+		Expect(file.UserProvided).To(HaveLen(1))
+		Expect(file.UserProvided[0].Name).To(Equal("ups"))
+		Expect(file.UserProvided[0].InstanceGUID).To(Equal("some-guid"))
+		Expect(file.UserProvided[0].Credentials.Username).To(Equal("user"))
+	})
+})
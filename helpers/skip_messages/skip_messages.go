@@ -25,6 +25,11 @@ const SkipAssistedCredhubMessage = `Skipping this test because config.CredhubMod
 NOTE: Ensure instance identity credential is turned on and CredHub is deployed before enabling this test`
 const SkipNonAssistedCredhubMessage = `Skipping this test because config.CredhubMode is not set to 'non-assisted'.
 NOTE: Ensure instance identity credential is turned on and CredHub is deployed before enabling this test`
+const SkipRevisionsMessage = `Skipping this test because config.IncludeRevisions is set to 'false'.`
+const SkipSidecarsMessage = `Skipping this test because config.IncludeSidecars is set to 'false'.`
+const SkipMetadataMessage = `Skipping this test because config.IncludeMetadata is set to 'false'.`
+const SkipQuotasMessage = `Skipping this test because config.IncludeQuotas is set to 'false'.`
+const SkipRoleMatrixMessage = `Skipping this test because config.IncludeRoleMatrix is set to 'false'.`
 const SkipRouteServicesMessage = `Skipping this test because config.IncludeRouteServices is set to 'false'.
 NOTE: Ensure that route services are enabled on your platform before running this test.`
 const SkipRoutingMessage = `Skipping this test because config.IncludeRouting is set to 'false'.`
@@ -38,6 +43,8 @@ const SkipServicesMessage = `Skipping this test because config.IncludeServices i
 const SkipUserProvidedServicesMessage = `Skipping this test because config.IncludeUserProvidedServices is set to 'false'.`
 const SkipSSHMessage = `Skipping this test because config.IncludeSsh is set to 'false'.
 NOTE: Ensure that your platform is deployed with a Diego SSH proxy in order to run this test.`
+const SkipClientCredentialsMessage = `Skipping this test because config.ExistingClient is set and this test needs to run as a user.
+NOTE: Unset config.ExistingClient to run the tests that log a user in to UAA or create users.`
 const SkipSSOMessage = `Skipping this test because config.IncludeSSO is not set to 'true'.
 NOTE: Ensure that your platform is running UAA with SSO enabled before enabling this test.`
 const SkipTasksMessage = `Skipping this test because config.IncludeTasks is set to 'false'.
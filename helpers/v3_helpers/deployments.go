@@ -0,0 +1,104 @@
+package v3_helpers
+
+import (
+	"maps"
+	"net/url"
+
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+)
+
+// DeploymentOptions configure CreateDeploymentWithOptions. Zero values are left for the Cloud
+// Controller to default, e.g. to the current droplet of the app and a rolling strategy.
+type DeploymentOptions struct {
+	Strategy     string
+	DropletGuid  string
+	RevisionGuid string
+	MaxInFlight  int
+
+	// CanarySteps are the percentages of instances running the new droplet at each step of a
+	// canary deployment, e.g. []int{25, 50, 75}.
+	CanarySteps []int
+
+	// WebInstances, MemoryInMB and DiskInMB override the web process of the deployment.
+	WebInstances int
+	MemoryInMB   int
+	DiskInMB     int
+
+	// Labels are added to the run labels of the deployment.
+	Labels map[string]string
+}
+
+func CreateDeploymentWithOptions(appGuid string, options DeploymentOptions) string {
+	labels := RunLabels()
+	maps.Copy(labels, options.Labels)
+
+	request := cc_client.CreateDeploymentRequest{
+		Strategy: options.Strategy,
+		Options: &cc_client.DeploymentOptions{
+			MaxInFlight:  options.MaxInFlight,
+			WebInstances: options.WebInstances,
+			MemoryInMB:   options.MemoryInMB,
+			DiskInMB:     options.DiskInMB,
+		},
+		Metadata: &cc_client.Metadata{Labels: labels},
+	}
+	if options.DropletGuid != "" {
+		request.Droplet = &cc_client.Ref{GUID: options.DropletGuid}
+	}
+	if options.RevisionGuid != "" {
+		request.Revision = &cc_client.Ref{GUID: options.RevisionGuid}
+	}
+	if len(options.CanarySteps) > 0 {
+		request.Options.Canary = cc_client.NewCanarySteps(options.CanarySteps...)
+	}
+	request.Relationships.App = cc_client.RelationshipTo(appGuid)
+
+	deployment, err := CCClient().CreateDeployment(request)
+	Expect(err).NotTo(HaveOccurred())
+	return deployment.GUID
+}
+
+func GetDeployment(deploymentGuid string) cc_client.Deployment {
+	deployment, err := CCClient().GetDeployment(deploymentGuid)
+	Expect(err).NotTo(HaveOccurred())
+	return deployment
+}
+
+// GetLatestDeploymentGuid returns the GUID of the most recent deployment of the app, e.g. the one
+// started by "cf push --strategy rolling --no-wait".
+func GetLatestDeploymentGuid(appGuid string) string {
+	deployments, err := CCClient().ListDeployments(url.Values{"app_guids": {appGuid}, "order_by": {"-created_at"}, "per_page": {"1"}})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(deployments).NotTo(BeEmpty(), "app %s has no deployments", appGuid)
+	return deployments[0].GUID
+}
+
+// ContinueDeployment moves a paused canary deployment on to its next step.
+func ContinueDeployment(deploymentGuid string) {
+	Expect(CCClient().ContinueDeployment(deploymentGuid)).To(Succeed())
+}
+
+// WaitForDeploymentStatus waits until the status of the deployment has value and reason, e.g.
+// "ACTIVE" and "DEPLOYING", and returns the deployment. It fails if the deployment is finalized
+// with another reason.
+func WaitForDeploymentStatus(deploymentGuid, value, reason string) cc_client.Deployment {
+	client := CCClient()
+	WaitFor(client.DeploymentStatusOperation(deploymentGuid, value, reason), Config.CfPushTimeoutDuration())
+	return GetDeployment(deploymentGuid)
+}
+
+// WaitForCanaryStep waits until the canary deployment is paused at step, counting from 1, and
+// returns the deployment.
+func WaitForCanaryStep(deploymentGuid string, step int) cc_client.Deployment {
+	WaitFor(CCClient().CanaryStepOperation(deploymentGuid, step), Config.CfPushTimeoutDuration())
+	return GetDeployment(deploymentGuid)
+}
+
+// WaitForDeploymentToComplete waits until the deployment is deployed, and returns it. Its Droplet
+// and Revision are the droplet and revision the app converged to.
+func WaitForDeploymentToComplete(deploymentGuid string) cc_client.Deployment {
+	return WaitForDeploymentStatus(deploymentGuid, "FINALIZED", "DEPLOYED")
+}
@@ -1,12 +1,9 @@
 package v3_helpers
 
 import (
-	"encoding/json"
-	"fmt"
-
-	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
 	. "github.com/onsi/gomega"
-	. "github.com/onsi/gomega/gexec"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
 )
 
 type DestinationProcess struct {
@@ -26,22 +23,21 @@ type Destinations struct {
 }
 
 func InsertDestinations(routeGUID string, destinations []Destination) []string {
-	destinationsJSON, err := json.Marshal(Destinations{Destinations: destinations})
-	Expect(err).ToNot(HaveOccurred())
-
-	session := cf.Cf("curl", "-f",
-		fmt.Sprintf("/v3/routes/%s/destinations", routeGUID),
-		"-X", "POST", "-d", string(destinationsJSON))
-
-	Expect(session.Wait()).To(Exit(0))
-	response := session.Out.Contents()
+	var requested []cc_client.Destination
+	for _, dst := range destinations {
+		destination := cc_client.Destination{Port: dst.Port, Protocol: dst.Protocol, Weight: dst.Weight}
+		destination.App.GUID = dst.App.GUID
+		if dst.App.Process != nil {
+			destination.App.Process = &cc_client.DestinationProcess{Type: dst.App.Process.Type}
+		}
+		requested = append(requested, destination)
+	}
 
-	var responseDestinations Destinations
-	err = json.Unmarshal(response, &responseDestinations)
+	inserted, err := CCClient().InsertRouteDestinations(routeGUID, requested)
 	Expect(err).ToNot(HaveOccurred())
 
-	listDstGUIDs := make([]string, 0, len(responseDestinations.Destinations))
-	for _, dst := range responseDestinations.Destinations {
+	listDstGUIDs := make([]string, 0, len(inserted))
+	for _, dst := range inserted {
 		listDstGUIDs = append(listDstGUIDs, dst.GUID)
 	}
 	return listDstGUIDs
@@ -0,0 +1,42 @@
+package v3_helpers
+
+import (
+	"net/url"
+
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/manifest"
+)
+
+// DiffSpaceManifest returns the changes that applying the manifest to the space would make. Like
+// ApplySpaceManifest, it labels the apps with the run labels.
+func DiffSpaceManifest(spaceGuid string, m manifest.Manifest) []cc_client.ManifestDiffOperation {
+	diff, err := CCClient().DiffSpaceManifest(spaceGuid, marshalWithRunLabels(m))
+	Expect(err).NotTo(HaveOccurred())
+	return diff
+}
+
+// ApplySpaceManifest applies the manifest to the apps in the space, without restarting them. The
+// apps it creates are labeled with the run labels.
+func ApplySpaceManifest(spaceGuid string, m manifest.Manifest) {
+	Expect(CCClient().ApplySpaceManifest(spaceGuid, marshalWithRunLabels(m))).To(Succeed())
+}
+
+func marshalWithRunLabels(m manifest.Manifest) []byte {
+	contents, err := m.WithLabels(RunLabels()).Marshal()
+	Expect(err).NotTo(HaveOccurred())
+	return contents
+}
+
+// GetBoundServiceInstanceGuids returns the GUIDs of the service instances bound to the app.
+func GetBoundServiceInstanceGuids(appGuid string) []string {
+	bindings, err := CCClient().ListServiceCredentialBindings(url.Values{"app_guids": {appGuid}, "type": {"app"}})
+	Expect(err).NotTo(HaveOccurred())
+	var guids []string
+	for _, binding := range bindings {
+		guids = append(guids, binding.Relationships.ServiceInstance.Data.GUID)
+	}
+	return guids
+}
@@ -0,0 +1,107 @@
+package v3_helpers
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+)
+
+// Requirements of label selectors, which LabelSelector combines into a label_selector query, e.g.
+// LabelSelector(LabelIn("env", "dev", "prod"), LabelDoesNotExist("deprecated")).
+func LabelEquals(key, value string) string {
+	return key + "=" + value
+}
+
+func LabelNotEquals(key, value string) string {
+	return key + "!=" + value
+}
+
+func LabelIn(key string, values ...string) string {
+	return fmt.Sprintf("%s in (%s)", key, strings.Join(values, ","))
+}
+
+func LabelNotIn(key string, values ...string) string {
+	return fmt.Sprintf("%s notin (%s)", key, strings.Join(values, ","))
+}
+
+func LabelExists(key string) string {
+	return key
+}
+
+func LabelDoesNotExist(key string) string {
+	return "!" + key
+}
+
+// LabelSelector returns the selector matching resources that meet all of the requirements.
+func LabelSelector(requirements ...string) string {
+	return strings.Join(requirements, ",")
+}
+
+// GetGuidsMatchingLabelSelector returns the GUIDs of the resources in a v3 collection, e.g.
+// "apps", that match the selector and the rest of query, which may be nil.
+func GetGuidsMatchingLabelSelector(collection, selector string, query url.Values) []string {
+	values := url.Values{"label_selector": {selector}}
+	for key, value := range query {
+		values[key] = value
+	}
+	guids, err := CCClient().ListGUIDs(collection, values)
+	Expect(err).NotTo(HaveOccurred())
+	return guids
+}
+
+func GetMetadata(collection, guid string) cc_client.Metadata {
+	metadata, err := CCClient().GetMetadata(collection, guid)
+	Expect(err).NotTo(HaveOccurred())
+	return metadata
+}
+
+// SetLabels adds labels to the resource with guid in a v3 collection, e.g. "apps", keeping its
+// other labels.
+func SetLabels(collection, guid string, labels map[string]string) {
+	updateMetadata(collection, guid, cc_client.MetadataUpdate{Labels: toUpdate(labels)})
+}
+
+func SetAnnotations(collection, guid string, annotations map[string]string) {
+	updateMetadata(collection, guid, cc_client.MetadataUpdate{Annotations: toUpdate(annotations)})
+}
+
+func RemoveLabels(collection, guid string, keys ...string) {
+	update := cc_client.MetadataUpdate{Labels: map[string]*string{}}
+	for _, key := range keys {
+		update.Labels[key] = nil
+	}
+	updateMetadata(collection, guid, update)
+}
+
+func RemoveAnnotations(collection, guid string, keys ...string) {
+	update := cc_client.MetadataUpdate{Annotations: map[string]*string{}}
+	for _, key := range keys {
+		update.Annotations[key] = nil
+	}
+	updateMetadata(collection, guid, update)
+}
+
+func updateMetadata(collection, guid string, update cc_client.MetadataUpdate) {
+	_, err := CCClient().UpdateMetadata(collection, guid, update)
+	Expect(err).NotTo(HaveOccurred())
+}
+
+func toUpdate(values map[string]string) map[string]*string {
+	update := map[string]*string{}
+	for key, value := range values {
+		update[key] = &value
+	}
+	return update
+}
+
+// GetGuidByName returns the GUID of the resource named name in a v3 collection, e.g. "stacks".
+func GetGuidByName(collection, name string) string {
+	guids, err := CCClient().ListGUIDs(collection, url.Values{"names": {name}})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(guids).NotTo(BeEmpty(), "no %s named %s", collection, name)
+	return guids[0]
+}
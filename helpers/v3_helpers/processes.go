@@ -1,10 +1,10 @@
 package v3_helpers
 
 import (
-	"encoding/json"
-	"fmt"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
 
-	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
 )
 
 type ProcessList struct {
@@ -19,18 +19,14 @@ type Process struct {
 }
 
 func GetProcesses(appGuid, appName string) []Process {
-	processesURL := fmt.Sprintf("/v3/apps/%s/processes", appGuid)
-	session := cf.Cf("curl", processesURL)
-	bytes := session.Wait().Out.Contents()
+	processes, err := CCClient().ListAppProcesses(appGuid, nil)
+	Expect(err).NotTo(HaveOccurred())
 
-	processes := ProcessList{}
-	json.Unmarshal(bytes, &processes)
-
-	for i, _ := range processes.Processes {
-		processes.Processes[i].Name = appName
+	var result []Process
+	for _, process := range processes {
+		result = append(result, Process{Guid: process.GUID, Type: process.Type, Command: process.Command, Name: appName})
 	}
-
-	return processes.Processes
+	return result
 }
 
 func GetProcessByType(processes []Process, processType string) Process {
@@ -42,13 +38,38 @@ func GetProcessByType(processes []Process, processType string) Process {
 	return Process{}
 }
 
-func GetProcessByGuid(processGuid string) Process {
-	processURL := fmt.Sprintf("/v3/processes/%s", processGuid)
-	session := cf.Cf("curl", processURL)
-	bytes := session.Wait().Out.Contents()
+func SetProcessCommand(processGuid, command string) {
+	_, err := CCClient().SetProcessCommand(processGuid, command)
+	Expect(err).NotTo(HaveOccurred())
+}
+
+func GetAppSidecars(appGuid string) []cc_client.Sidecar {
+	sidecars, err := CCClient().ListAppSidecars(appGuid)
+	Expect(err).NotTo(HaveOccurred())
+	return sidecars
+}
+
+func GetProcessSidecars(processGuid string) []cc_client.Sidecar {
+	sidecars, err := CCClient().ListProcessSidecars(processGuid)
+	Expect(err).NotTo(HaveOccurred())
+	return sidecars
+}
 
-	var process Process
-	json.Unmarshal(bytes, &process)
+func GetProcessByGuid(processGuid string) Process {
+	process, err := CCClient().GetProcess(processGuid)
+	Expect(err).NotTo(HaveOccurred())
+	return Process{Guid: process.GUID, Type: process.Type, Command: process.Command}
+}
 
-	return process
+// GetAppProcess returns the process of the app with the type, including its scale.
+func GetAppProcess(appGuid, processType string) cc_client.Process {
+	processes, err := CCClient().ListAppProcesses(appGuid, nil)
+	Expect(err).NotTo(HaveOccurred())
+	for _, process := range processes {
+		if process.Type == processType {
+			return process
+		}
+	}
+	Fail("app " + appGuid + " has no " + processType + " process")
+	return cc_client.Process{}
 }
@@ -0,0 +1,55 @@
+package v3_helpers
+
+import (
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+)
+
+const (
+	OrganizationQuotas = "organization_quotas"
+	SpaceQuotas        = "space_quotas"
+)
+
+// CreateOrganizationQuota creates an organization quota with the limits, applies it to the
+// organizations and returns its GUID.
+func CreateOrganizationQuota(name string, limits cc_client.QuotaLimits, orgGuids ...string) string {
+	quota, err := CCClient().CreateOrganizationQuota(name, limits, orgGuids...)
+	Expect(err).NotTo(HaveOccurred())
+	return quota.GUID
+}
+
+// CreateSpaceQuota creates a space quota with the limits in the organization, applies it to the
+// spaces and returns its GUID.
+func CreateSpaceQuota(name string, limits cc_client.QuotaLimits, orgGuid string, spaceGuids ...string) string {
+	quota, err := CCClient().CreateSpaceQuota(name, limits, orgGuid, spaceGuids...)
+	Expect(err).NotTo(HaveOccurred())
+	return quota.GUID
+}
+
+// GetQuota returns a quota of kind OrganizationQuotas or SpaceQuotas.
+func GetQuota(kind, quotaGuid string) cc_client.Quota {
+	quota, err := CCClient().GetQuota(kind, quotaGuid)
+	Expect(err).NotTo(HaveOccurred())
+	return quota
+}
+
+// UpdateQuota replaces all of the limits of a quota of kind OrganizationQuotas or SpaceQuotas.
+func UpdateQuota(kind, quotaGuid string, limits cc_client.QuotaLimits) {
+	_, err := CCClient().UpdateQuota(kind, quotaGuid, limits)
+	Expect(err).NotTo(HaveOccurred())
+}
+
+// DeleteQuota deletes a quota of kind OrganizationQuotas or SpaceQuotas, which must not be
+// applied to any organization or space.
+func DeleteQuota(kind, quotaGuid string) {
+	Expect(CCClient().DeleteQuota(kind, quotaGuid)).To(Succeed())
+}
+
+func ApplyOrganizationQuota(quotaGuid string, orgGuids ...string) {
+	Expect(CCClient().ApplyOrganizationQuota(quotaGuid, orgGuids...)).To(Succeed())
+}
+
+func ApplySpaceQuota(quotaGuid string, spaceGuids ...string) {
+	Expect(CCClient().ApplySpaceQuota(quotaGuid, spaceGuids...)).To(Succeed())
+}
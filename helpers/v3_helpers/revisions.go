@@ -0,0 +1,48 @@
+package v3_helpers
+
+import (
+	"net/url"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+)
+
+// GetRevisions returns the revisions of the app, oldest first.
+func GetRevisions(appGuid string) []cc_client.Revision {
+	revisions, err := CCClient().ListAppRevisions(appGuid, url.Values{"order_by": {"created_at"}})
+	Expect(err).NotTo(HaveOccurred())
+	return revisions
+}
+
+// GetLatestRevision returns the revision with the highest version.
+func GetLatestRevision(appGuid string) cc_client.Revision {
+	revisions, err := CCClient().ListAppRevisions(appGuid, url.Values{"order_by": {"-created_at"}, "per_page": {"1"}})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(revisions).NotTo(BeEmpty(), "app %s has no revisions", appGuid)
+	return revisions[0]
+}
+
+func GetDeployedRevisions(appGuid string) []cc_client.Revision {
+	revisions, err := CCClient().ListDeployedRevisions(appGuid)
+	Expect(err).NotTo(HaveOccurred())
+	return revisions
+}
+
+func GetRevisionEnvironmentVariables(revisionGuid string) map[string]string {
+	env, err := CCClient().GetRevisionEnvironmentVariables(revisionGuid)
+	Expect(err).NotTo(HaveOccurred())
+	return env
+}
+
+func GetAppEnvironmentVariables(appGuid string) map[string]string {
+	env, err := CCClient().GetAppEnvironmentVariables(appGuid)
+	Expect(err).NotTo(HaveOccurred())
+	return env
+}
+
+// RollbackToRevision deploys the droplet, environment variables and commands of the revision and
+// returns the deployment once it is complete.
+func RollbackToRevision(appGuid, revisionGuid string) cc_client.Deployment {
+	return WaitForDeploymentToComplete(CreateDeploymentWithOptions(appGuid, DeploymentOptions{RevisionGuid: revisionGuid}))
+}
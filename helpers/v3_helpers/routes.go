@@ -1,26 +1,38 @@
 package v3_helpers
 
 import (
-	"encoding/json"
-	"fmt"
+	"net/url"
 
-	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
 	. "github.com/onsi/gomega"
-	. "github.com/onsi/gomega/gexec"
 )
 
 func GetRouteGuid(hostname string) string {
-	routeQuery := fmt.Sprintf("/v3/routes?hosts=%s", hostname)
-	getRoutesCurl := cf.Cf("curl", routeQuery)
-	Expect(getRoutesCurl.Wait()).To(Exit(0))
-	routesJSON := struct {
-		Resources []struct {
-			Guid string `json:"guid"`
-		} `json:"resources"`
-	}{}
-	bytes := getRoutesCurl.Out.Contents()
+	routes, err := CCClient().ListRoutes(url.Values{"hosts": {hostname}})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(routes).NotTo(BeEmpty(), "no route with host %s", hostname)
+	return routes[0].GUID
+}
 
-	json.Unmarshal(bytes, &routesJSON)
+// GetRoutePorts returns the ports of the TCP routes of the domain in the space.
+func GetRoutePorts(spaceGuid, domainGuid string) []int {
+	routes, err := CCClient().ListRoutes(url.Values{"space_guids": {spaceGuid}, "domain_guids": {domainGuid}})
+	Expect(err).NotTo(HaveOccurred())
+	var ports []int
+	for _, route := range routes {
+		if route.Port != nil {
+			ports = append(ports, *route.Port)
+		}
+	}
+	return ports
+}
 
-	return routesJSON.Resources[0].Guid
+// GetAppRouteUrls returns the URLs of the routes mapped to the app, e.g. "host.example.com".
+func GetAppRouteUrls(appGuid string) []string {
+	routes, err := CCClient().ListRoutes(url.Values{"app_guids": {appGuid}})
+	Expect(err).NotTo(HaveOccurred())
+	var urls []string
+	for _, route := range routes {
+		urls = append(urls, route.URL)
+	}
+	return urls
 }
@@ -2,19 +2,24 @@ package v3_helpers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
 	"github.com/cloudfoundry/cf-test-helpers/v2/helpers"
 	"github.com/cloudfoundry/cf-test-helpers/v2/workflowhelpers"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	. "github.com/onsi/gomega/gbytes"
 	. "github.com/onsi/gomega/gexec"
 
 	. "github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
 )
 
 const (
@@ -34,38 +39,23 @@ type App struct {
 }
 
 func CreateDeployment(appGuid string) string {
-	deploymentPath := "/v3/deployments"
-	deploymentRequestBody := fmt.Sprintf(`{"relationships": {"app": {"data": {"guid": "%s"}}}}`, appGuid)
-	session := cf.Cf("curl", deploymentPath, "-X", "POST", "-d", deploymentRequestBody).Wait()
-	Expect(session).To(Exit(0))
-	var deployment struct {
-		Guid string `json:"guid"`
-	}
-
-	bytes := session.Wait().Out.Contents()
-	json.Unmarshal(bytes, &deployment)
-	return deployment.Guid
+	var request cc_client.CreateDeploymentRequest
+	request.Relationships.App = cc_client.RelationshipTo(appGuid)
+	deployment, err := CCClient().CreateDeployment(request)
+	Expect(err).NotTo(HaveOccurred())
+	return deployment.GUID
 }
 
 func CreateDeploymentForDroplet(appGuid, dropletGuid string) string {
-	deploymentPath := "/v3/deployments"
-	deploymentRequestBody := fmt.Sprintf(`{"droplet": {"guid": "%s"}, "relationships": {"app": {"data": {"guid": "%s"}}}}`, dropletGuid, appGuid)
-	session := cf.Cf("curl", deploymentPath, "-X", "POST", "-d", deploymentRequestBody).Wait()
-	Expect(session).To(Exit(0))
-	var deployment struct {
-		Guid string `json:"guid"`
-	}
-
-	bytes := session.Wait().Out.Contents()
-	json.Unmarshal(bytes, &deployment)
-	return deployment.Guid
+	request := cc_client.CreateDeploymentRequest{Droplet: &cc_client.Ref{GUID: dropletGuid}}
+	request.Relationships.App = cc_client.RelationshipTo(appGuid)
+	deployment, err := CCClient().CreateDeployment(request)
+	Expect(err).NotTo(HaveOccurred())
+	return deployment.GUID
 }
 
 func CancelDeployment(deploymentGuid string) {
-	deploymentPath := fmt.Sprintf("/v3/deployments/%s/actions/cancel", deploymentGuid)
-	session := cf.Cf("curl", deploymentPath, "-X", "POST", "-i").Wait()
-	Expect(string(session.Out.Contents())).To(ContainSubstring("200 OK"))
-	Expect(session).To(Exit(0))
+	Expect(CCClient().CancelDeployment(deploymentGuid)).To(Succeed())
 }
 
 type AppsResponse struct {
@@ -73,33 +63,26 @@ type AppsResponse struct {
 }
 
 func GetApp(appName string) App {
-	var appsResponse AppsResponse
-	cfResponse := cf.Cf("curl", fmt.Sprintf("/v3/apps?names=%s", appName)).Wait().Out.Contents()
-	json.Unmarshal(cfResponse, &appsResponse)
-	return appsResponse.Resources[0]
+	apps, err := CCClient().ListApps(url.Values{"names": {appName}})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(apps).NotTo(BeEmpty(), "no app named %s", appName)
+
+	app := App{GUID: apps[0].GUID, Name: apps[0].Name}
+	app.Links.Self.Href = apps[0].Links["self"].Href
+	return app
 }
 
 func ScaleApp(appGuid string, instances int) {
-	scalePath := fmt.Sprintf("/v3/apps/%s/processes/web/actions/scale", appGuid)
-	scaleBody := fmt.Sprintf(`{"instances": "%d"}`, instances)
-	Expect(cf.Cf("curl", scalePath, "-X", "POST", "-d", scaleBody).Wait()).To(Exit(0))
+	_, err := CCClient().ScaleProcess(appGuid, "web", cc_client.ScaleRequest{Instances: &instances})
+	Expect(err).NotTo(HaveOccurred())
 }
 
 func GetRunningInstancesStats(processGuid string) int {
-	processPath := fmt.Sprintf("/v3/processes/%s/stats", processGuid)
-	session := cf.Cf("curl", processPath).Wait()
-	instancesJson := struct {
-		Resources []struct {
-			Type  string `json:"type"`
-			State string `json:"state"`
-		} `json:"resources"`
-	}{}
+	instances, err := CCClient().GetProcessStats(processGuid)
+	Expect(err).NotTo(HaveOccurred())
 
-	bytes := session.Wait().Out.Contents()
-	json.Unmarshal(bytes, &instancesJson)
 	numRunning := 0
-
-	for _, instance := range instancesJson.Resources {
+	for _, instance := range instances {
 		if instance.State == "RUNNING" {
 			numRunning += 1
 		}
@@ -108,47 +91,30 @@ func GetRunningInstancesStats(processGuid string) int {
 }
 
 func GetProcessGuidForType(appGuid string, processType string) string {
-	return GetProcessGuidsForType(appGuid, processType)[0]
+	guids := GetProcessGuidsForType(appGuid, processType)
+	Expect(guids).NotTo(BeEmpty(), "app %s has no %s process", appGuid, processType)
+	return guids[0]
 }
 
 func GetProcessGuidsForType(appGuid string, processType string) []string {
-	processesPath := fmt.Sprintf("/v3/apps/%s/processes?types=%s", appGuid, processType)
-	session := cf.Cf("curl", processesPath).Wait()
-	processesJSON := struct {
-		Resources []struct {
-			Guid string `json:"guid"`
-		} `json:"resources"`
-	}{}
-	bytes := session.Wait().Out.Contents()
-	err := json.Unmarshal(bytes, &processesJSON)
+	processes, err := CCClient().ListAppProcesses(appGuid, url.Values{"types": {processType}})
+	Expect(err).NotTo(HaveOccurred())
+
 	var guids []string
-	if err != nil || len(processesJSON.Resources) == 0 {
-		return guids
-	}
-	for _, r := range processesJSON.Resources {
-		guids = append(guids, r.Guid)
+	for _, process := range processes {
+		guids = append(guids, process.GUID)
 	}
 	return guids
 }
 
 func GetCurrentDropletGuidFromApp(appGuid string) string {
-	session := cf.Cf("curl", fmt.Sprintf("/v3/apps/%s/droplets/current", appGuid))
-	bytes := session.Wait().Out.Contents()
-
-	type Droplet struct {
-		Guid string `json:"guid"`
-	}
-	var droplet Droplet
-	err := json.Unmarshal(bytes, &droplet)
-	Expect(err).ToNot(HaveOccurred())
-
-	return droplet.Guid
+	droplet, err := CCClient().GetCurrentDroplet(appGuid)
+	Expect(err).NotTo(HaveOccurred())
+	return droplet.GUID
 }
 
 func AssignDropletToApp(appGuid, dropletGuid string) {
-	appUpdatePath := fmt.Sprintf("/v3/apps/%s/relationships/current_droplet", appGuid)
-	appUpdateBody := fmt.Sprintf(`{"data": {"guid":"%s"}}`, dropletGuid)
-	Expect(cf.Cf("curl", appUpdatePath, "-X", "PATCH", "-d", appUpdateBody).Wait()).To(Exit(0))
+	Expect(CCClient().SetCurrentDroplet(appGuid, dropletGuid)).To(Succeed())
 
 	for _, process := range GetProcesses(appGuid, "") {
 		ScaleProcess(appGuid, process.Type, V3_DEFAULT_MEMORY_LIMIT)
@@ -156,71 +122,59 @@ func AssignDropletToApp(appGuid, dropletGuid string) {
 }
 
 func AssignIsolationSegmentToSpace(spaceGuid, isoSegGuid string) {
-	Eventually(cf.Cf("curl", fmt.Sprintf("/v3/spaces/%s/relationships/isolation_segment", spaceGuid),
-		"-X",
-		"PATCH",
-		"-d",
-		fmt.Sprintf(`{"data":{"guid":"%s"}}`, isoSegGuid)),
-	).Should(Exit(0))
+	Expect(CCClient().SetSpaceIsolationSegment(spaceGuid, isoSegGuid)).To(Succeed())
 }
 
 func CreateAndMapRoute(appGuid, domain, host string) {
 	CreateRoute(domain, host)
-	getRoutePath := fmt.Sprintf("/v3/routes?hosts=%s", host)
 
-	routeBody := cf.Cf("curl", getRoutePath).Wait().Out.Contents()
-	routeJSON := struct {
-		Resources []struct {
-			Guid string `json:"guid"`
-		} `json:"resources"`
-	}{}
-	json.Unmarshal([]byte(routeBody), &routeJSON)
-	routeGuid := routeJSON.Resources[0].Guid
-	Expect(cf.Cf("curl", fmt.Sprintf("/v3/routes/%s/destinations", routeGuid), "-X", "POST", "-d", fmt.Sprintf(`{"destinations": [{"app": {"guid": "%s"}}]}`, appGuid)).Wait()).To(Exit(0))
+	var destination cc_client.Destination
+	destination.App.GUID = appGuid
+	_, err := CCClient().InsertRouteDestinations(GetRouteGuid(host), []cc_client.Destination{destination})
+	Expect(err).NotTo(HaveOccurred())
 }
 
 func CreateApp(appName, spaceGuid, environmentVariables string) string {
-	session := cf.Cf("curl", "/v3/apps", "-X", "POST", "-d", fmt.Sprintf(`{"name":"%s", "relationships": {"space": {"data": {"guid": "%s"}}}, "environment_variables":%s}`, appName, spaceGuid, environmentVariables))
-	bytes := session.Wait().Out.Contents()
-	var app struct {
-		Guid string `json:"guid"`
-	}
-	json.Unmarshal(bytes, &app)
-	return app.Guid
+	return createApp(appName, spaceGuid, environmentVariables, nil)
 }
 
 func CreateDockerApp(appName, spaceGuid, environmentVariables string) string {
-	session := cf.Cf("curl", "/v3/apps", "-X", "POST", "-d", fmt.Sprintf(`{"name":"%s", "relationships": {"space": {"data": {"guid": "%s"}}}, "environment_variables":%s, "lifecycle": {"type": "docker", "data": {} } }`, appName, spaceGuid, environmentVariables))
-	bytes := session.Wait().Out.Contents()
-	var app struct {
-		Guid string `json:"guid"`
+	return createApp(appName, spaceGuid, environmentVariables, &cc_client.Lifecycle{Type: "docker"})
+}
+
+// createApp creates an app labeled with the run labels. environmentVariables is a JSON object.
+func createApp(appName, spaceGuid, environmentVariables string, lifecycle *cc_client.Lifecycle) string {
+	request := cc_client.CreateAppRequest{
+		Name:      appName,
+		Lifecycle: lifecycle,
+		Metadata:  &cc_client.Metadata{Labels: RunLabels()},
 	}
-	json.Unmarshal(bytes, &app)
-	return app.Guid
+	request.Relationships.Space = cc_client.RelationshipTo(spaceGuid)
+	Expect(json.Unmarshal([]byte(environmentVariables), &request.EnvironmentVariables)).To(Succeed())
+
+	app, err := CCClient().CreateApp(request)
+	Expect(err).NotTo(HaveOccurred())
+	return app.GUID
 }
 
 func CreateDockerPackage(appGuid, imagePath string) string {
-	packageCreateUrl := "/v3/packages"
-	session := cf.Cf("curl", packageCreateUrl, "-X", "POST", "-d", fmt.Sprintf(`{"relationships":{"app":{"data":{"guid":"%s"}}},"type":"docker", "data": {"image": "%s"}}`, appGuid, imagePath))
-	bytes := session.Wait().Out.Contents()
-	var pac struct {
-		Guid string `json:"guid"`
-	}
-	json.Unmarshal(bytes, &pac)
-	return pac.Guid
+	request := cc_client.CreatePackageRequest{Type: "docker", Data: &cc_client.PackageData{Image: imagePath}}
+	request.Relationships.App = cc_client.RelationshipTo(appGuid)
+	pkg, err := CCClient().CreatePackage(request)
+	Expect(err).NotTo(HaveOccurred())
+	return pkg.GUID
 }
 
+// CreateIsolationSegment returns the GUID of the new isolation segment, or "" if the Cloud
+// Controller refuses to create it, e.g. because the name is taken.
 func CreateIsolationSegment(name string) string {
-	session := cf.Cf("curl", "/v3/isolation_segments", "-X", "POST", "-d", fmt.Sprintf(`{"name":"%s"}`, name))
-	bytes := session.Wait().Out.Contents()
-
-	var isolation_segment struct {
-		Guid string `json:"guid"`
+	isolationSegment, err := CCClient().CreateIsolationSegment(name)
+	var responseError *cc_client.ResponseError
+	if errors.As(err, &responseError) && responseError.StatusCode == http.StatusUnprocessableEntity {
+		return ""
 	}
-	err := json.Unmarshal(bytes, &isolation_segment)
-	Expect(err).ToNot(HaveOccurred())
-
-	return isolation_segment.Guid
+	Expect(err).NotTo(HaveOccurred())
+	return isolationSegment.GUID
 }
 
 func CreateOrGetIsolationSegment(name string) string {
@@ -232,18 +186,16 @@ func CreateOrGetIsolationSegment(name string) string {
 }
 
 func CreatePackage(appGuid string) string {
-	packageCreateUrl := "/v3/packages"
-	session := cf.Cf("curl", packageCreateUrl, "-X", "POST", "-d", fmt.Sprintf(`{"relationships":{"app":{"data":{"guid":"%s"}}},"type":"bits"}`, appGuid))
-	bytes := session.Wait().Out.Contents()
-	var pac struct {
-		Guid string `json:"guid"`
-	}
-	json.Unmarshal(bytes, &pac)
-	return pac.Guid
+	request := cc_client.CreatePackageRequest{Type: "bits"}
+	request.Relationships.App = cc_client.RelationshipTo(appGuid)
+	pkg, err := CCClient().CreatePackage(request)
+	Expect(err).NotTo(HaveOccurred())
+	return pkg.GUID
 }
 
 func CreateRoute(domain, host string) {
 	Expect(cf.Cf("create-route", domain, "-n", host).Wait()).To(Exit(0))
+	LabelResource("/v3/routes/" + GetRouteGuid(host))
 }
 
 func HandleAsyncRequest(path string, method string) {
@@ -263,53 +215,60 @@ func GetJobPath(response []byte) string {
 }
 
 func PollJob(jobPath string) {
-	Eventually(func() string {
-		jobSession := cf.Cf("curl", jobPath)
-		return string(jobSession.Wait().Out.Contents())
-	}).Should(ContainSubstring("COMPLETE"))
+	WaitFor(CCClient().JobOperation(jobPath), Config.DefaultTimeoutDuration())
 }
 
 func DeleteApp(appGuid string) {
-	HandleAsyncRequest(fmt.Sprintf("/v3/apps/%s", appGuid), "DELETE")
+	Expect(CCClient().DeleteApp(appGuid)).To(Succeed())
 }
 
 func DeleteIsolationSegment(guid string) {
-	Eventually(cf.Cf("curl", fmt.Sprintf("/v3/isolation_segments/%s", guid), "-X", "DELETE")).Should(Exit(0))
+	Expect(CCClient().DeleteIsolationSegment(guid)).To(Succeed())
 }
 
 func EntitleOrgToIsolationSegment(orgGuid, isoSegGuid string) {
-	Eventually(cf.Cf("curl",
-		fmt.Sprintf("/v3/isolation_segments/%s/relationships/organizations", isoSegGuid),
-		"-X",
-		"POST",
-		"-d",
-		fmt.Sprintf(`{"data":[{ "guid":"%s" }]}`, orgGuid)),
-	).Should(Exit(0))
+	Expect(CCClient().EntitleOrganizations(isoSegGuid, orgGuid)).To(Succeed())
 }
 
+// AuthTokenSource returns the token GetAuthToken and CCClient authenticate with, which is the one of
+// the current cf user. Tests replace it to run the helpers without the cf CLI.
+var AuthTokenSource = cfOAuthToken
+
 func GetAuthToken() string {
+	return AuthTokenSource()
+}
+
+func cfOAuthToken() string {
 	session := cf.CfSilent("oauth-token")
 	bytes := session.Wait().Out.Contents()
 	return strings.TrimSpace(string(bytes))
 }
 
+// CCClient returns a Cloud Controller client authenticated with GetAuthToken.
+func CCClient() *cc_client.Client {
+	return cc_client.New(Config.Protocol()+Config.GetApiEndpoint(), GetAuthToken(), Config.GetSkipSSLValidation()).
+		WithJobPolling(Config.DefaultTimeoutDuration(), cc_client.DefaultBackoff)
+}
+
+// WaitFor waits up to timeout, which should already be scaled, e.g. Config.CfPushTimeoutDuration(),
+// for the asynchronous operation to complete. If it fails or times out, the spec fails with the
+// error details of the Cloud Controller and how long the operation ran.
+func WaitFor(operation cc_client.Operation, timeout time.Duration) {
+	GinkgoHelper()
+	Expect(cc_client.Wait(operation, timeout, cc_client.DefaultBackoff)).To(Succeed())
+}
+
 func GetDefaultIsolationSegment(orgGuid string) string {
-	session := cf.Cf("curl", fmt.Sprintf("/v3/organizations/%s/relationships/default_isolation_segment", orgGuid))
-	bytes := session.Wait().Out.Contents()
-	return GetIsolationSegmentGuidFromResponse(bytes)
+	guid, err := CCClient().GetDefaultIsolationSegment(orgGuid)
+	Expect(err).NotTo(HaveOccurred())
+	return guid
 }
 
 func GetDropletFromBuild(buildGuid string) string {
-	buildPath := fmt.Sprintf("/v3/builds/%s", buildGuid)
-	session := cf.Cf("curl", buildPath).Wait()
-	var build struct {
-		Droplet struct {
-			Guid string `json:"guid"`
-		} `json:"droplet"`
-	}
-	bytes := session.Wait().Out.Contents()
-	json.Unmarshal(bytes, &build)
-	return build.Droplet.Guid
+	build, err := CCClient().GetBuild(buildGuid)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(build.Droplet).NotTo(BeNil(), "build %s has no droplet", buildGuid)
+	return build.Droplet.GUID
 }
 
 func GetGuidFromResponse(response []byte) string {
@@ -331,9 +290,12 @@ func GetGuidFromResponse(response []byte) string {
 }
 
 func GetIsolationSegmentGuid(name string) string {
-	session := cf.Cf("curl", fmt.Sprintf("/v3/isolation_segments?names=%s", name))
-	bytes := session.Wait().Out.Contents()
-	return GetGuidFromResponse(bytes)
+	isolationSegments, err := CCClient().ListIsolationSegments(url.Values{"names": {name}})
+	Expect(err).NotTo(HaveOccurred())
+	if len(isolationSegments) == 0 {
+		Fail("No guid found for response")
+	}
+	return isolationSegments[0].GUID
 }
 
 func GetIsolationSegmentGuidFromResponse(response []byte) string {
@@ -361,115 +323,68 @@ func GetSpaceGuidFromName(spaceName string) string {
 }
 
 func IsolationSegmentExists(name string) bool {
-	session := cf.Cf("curl", fmt.Sprintf("/v3/isolation_segments?names=%s", name))
-	bytes := session.Wait().Out.Contents()
-	type resource struct {
-		Guid string `json:"guid"`
-	}
-	var GetResponse struct {
-		Resources []resource `json:"resources"`
-	}
-
-	err := json.Unmarshal(bytes, &GetResponse)
-	Expect(err).ToNot(HaveOccurred())
-	return len(GetResponse.Resources) > 0
+	isolationSegments, err := CCClient().ListIsolationSegments(url.Values{"names": {name}})
+	Expect(err).NotTo(HaveOccurred())
+	return len(isolationSegments) > 0
 }
 
 func OrgEntitledToIsolationSegment(orgGuid string, isoSegName string) bool {
-	session := cf.Cf("curl", fmt.Sprintf("/v3/isolation_segments?names=%s&organization_guids=%s", isoSegName, orgGuid))
-	bytes := session.Wait().Out.Contents()
-
-	type resource struct {
-		Guid string `json:"guid"`
-	}
-	var GetResponse struct {
-		Resources []resource `json:"resources"`
-	}
-
-	err := json.Unmarshal(bytes, &GetResponse)
-	Expect(err).ToNot(HaveOccurred())
-	return len(GetResponse.Resources) > 0
+	isolationSegments, err := CCClient().ListIsolationSegments(url.Values{"names": {isoSegName}, "organization_guids": {orgGuid}})
+	Expect(err).NotTo(HaveOccurred())
+	return len(isolationSegments) > 0
 }
 
 func RevokeOrgEntitlementForIsolationSegment(orgGuid, isoSegGuid string) {
-	Eventually(cf.Cf("curl",
-		fmt.Sprintf("/v3/isolation_segments/%s/relationships/organizations/%s", isoSegGuid, orgGuid),
-		"-X",
-		"DELETE",
-	)).Should(Exit(0))
+	Expect(CCClient().RevokeOrganization(isoSegGuid, orgGuid)).To(Succeed())
 }
 
 func ScaleProcess(appGuid, processType, memoryInMb string) {
-	scalePath := fmt.Sprintf("/v3/apps/%s/processes/%s/actions/scale", appGuid, processType)
-	scaleBody := fmt.Sprintf(`{"memory_in_mb":"%s"}`, memoryInMb)
-	session := cf.Cf("curl", scalePath, "-X", "POST", "-d", scaleBody).Wait()
-	Expect(session).To(Exit(0))
-	result := session.Out.Contents()
-	Expect(strings.Contains(string(result), "errors")).To(BeFalse())
+	memory, err := strconv.Atoi(memoryInMb)
+	Expect(err).NotTo(HaveOccurred())
+
+	_, err = CCClient().ScaleProcess(appGuid, processType, cc_client.ScaleRequest{MemoryInMB: &memory})
+	Expect(err).NotTo(HaveOccurred())
 }
 
 func SetDefaultIsolationSegment(orgGuid, isoSegGuid string) {
-	Eventually(cf.Cf("curl",
-		fmt.Sprintf("/v3/organizations/%s/relationships/default_isolation_segment", orgGuid),
-		"-X",
-		"PATCH",
-		"-d",
-		fmt.Sprintf(`{"data":{"guid":"%s"}}`, isoSegGuid)),
-	).Should(Exit(0))
+	Expect(CCClient().SetDefaultIsolationSegment(orgGuid, isoSegGuid)).To(Succeed())
 }
 
 func StageBuildpackPackage(packageGuid string, buildpacks ...string) string {
-	stageBody := fmt.Sprintf(`{"lifecycle":{ "type": "buildpack", "data": { "buildpacks": ["%s"] } }, "package": { "guid" : "%s"}}`, strings.Join(buildpacks, `", "`), packageGuid)
-	stageUrl := "/v3/builds"
-	session := cf.Cf("curl", stageUrl, "-X", "POST", "-d", stageBody)
-	bytes := session.Wait().Out.Contents()
-	var build struct {
-		Guid string `json:"guid"`
-	}
-	json.Unmarshal(bytes, &build)
-	Expect(build.Guid).NotTo(BeEmpty())
-	return build.Guid
+	build, err := CCClient().CreateBuild(cc_client.CreateBuildRequest{
+		Package:   cc_client.Ref{GUID: packageGuid},
+		Lifecycle: &cc_client.Lifecycle{Type: "buildpack", Data: cc_client.LifecycleData{Buildpacks: buildpacks}},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(build.GUID).NotTo(BeEmpty())
+	return build.GUID
 }
 
 func StageDockerPackage(packageGuid string) string {
-	stageBody := fmt.Sprintf(`{"lifecycle": { "type" : "docker", "data": {} }, "package": { "guid" : "%s"}}`, packageGuid)
-	stageUrl := "/v3/builds"
-	session := cf.Cf("curl", stageUrl, "-X", "POST", "-d", stageBody)
-	bytes := session.Wait().Out.Contents()
-	var build struct {
-		Guid string `json:"guid"`
-	}
-	json.Unmarshal(bytes, &build)
-	return build.Guid
+	build, err := CCClient().CreateBuild(cc_client.CreateBuildRequest{
+		Package:   cc_client.Ref{GUID: packageGuid},
+		Lifecycle: &cc_client.Lifecycle{Type: "docker"},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	return build.GUID
 }
 
 func StartApp(appGuid string) {
-	startURL := fmt.Sprintf("/v3/apps/%s/actions/start", appGuid)
-	Expect(cf.Cf("curl", startURL, "-X", "POST").Wait()).To(Exit(0))
+	_, err := CCClient().StartApp(appGuid)
+	Expect(err).NotTo(HaveOccurred())
 }
 
 func StopApp(appGuid string) {
-	stopURL := fmt.Sprintf("/v3/apps/%s/actions/stop", appGuid)
-	Expect(cf.Cf("curl", stopURL, "-X", "POST").Wait()).To(Exit(0))
+	_, err := CCClient().StopApp(appGuid)
+	Expect(err).NotTo(HaveOccurred())
 }
 
 func UnassignIsolationSegmentFromSpace(spaceGuid string) {
-	Eventually(cf.Cf("curl", fmt.Sprintf("/v3/spaces/%s/relationships/isolation_segment", spaceGuid),
-		"-X",
-		"PATCH",
-		"-d",
-		`{"data":{"guid":null}}`),
-	).Should(Exit(0))
+	Expect(CCClient().SetSpaceIsolationSegment(spaceGuid, "")).To(Succeed())
 }
 
 func UnsetDefaultIsolationSegment(orgGuid string) {
-	Eventually(cf.Cf("curl",
-		fmt.Sprintf("/v3/organizations/%s/relationships/default_isolation_segment", orgGuid),
-		"-X",
-		"PATCH",
-		"-d",
-		`{"data":{"guid": null}}`),
-	).Should(Exit(0))
+	Expect(CCClient().SetDefaultIsolationSegment(orgGuid, "")).To(Succeed())
 }
 
 func UploadPackage(uploadUrl, packageZipPath, token string) {
@@ -479,32 +394,29 @@ func UploadPackage(uploadUrl, packageZipPath, token string) {
 }
 
 func WaitForBuildToStage(buildGuid string) {
-	buildPath := fmt.Sprintf("/v3/builds/%s", buildGuid)
-	Eventually(func() *Session {
-		session := cf.Cf("curl", buildPath).Wait()
-		Expect(session).NotTo(Say("FAILED"))
-		return session
-	}, Config.CfPushTimeoutDuration()).Should(Say("STAGED"))
+	WaitFor(CCClient().BuildOperation(buildGuid), Config.CfPushTimeoutDuration())
 }
 
 func WaitForDropletToCopy(dropletGuid string) {
-	dropletPath := fmt.Sprintf("/v3/droplets/%s", dropletGuid)
-	Eventually(func() *Session {
-		session := cf.Cf("curl", dropletPath).Wait()
-		Expect(session).NotTo(Say("FAILED"))
-		return session
-	}, Config.CfPushTimeoutDuration()).Should(Say("STAGED"))
+	client := CCClient()
+	Eventually(func() string {
+		droplet, err := client.GetDroplet(dropletGuid)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(droplet.State).NotTo(Equal("FAILED"), droplet.Error)
+		return droplet.State
+	}, Config.CfPushTimeoutDuration()).Should(Equal("STAGED"))
 }
 
 func WaitForPackageToBeReady(packageGuid string) {
-	pkgUrl := fmt.Sprintf("/v3/packages/%s", packageGuid)
-	var session *Session
-	Eventually(func() *Session {
-		session = cf.Cf("curl", pkgUrl)
-		Expect(session.Wait()).To(Exit(0))
-		return session
-	}, Config.LongCurlTimeoutDuration()).Should(Say("READY|FAILED"))
-	Expect(string(session.Out.Contents())).To(ContainSubstring("READY"))
+	client := CCClient()
+	var state string
+	Eventually(func() string {
+		pkg, err := client.GetPackage(packageGuid)
+		Expect(err).NotTo(HaveOccurred())
+		state = pkg.State
+		return state
+	}, Config.LongCurlTimeoutDuration()).Should(Or(Equal("READY"), Equal("FAILED")))
+	Expect(state).To(Equal("READY"))
 }
 
 type ProcessAppUsageEvent struct {
@@ -0,0 +1,45 @@
+package v3_helpers_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/fake_cc"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/v3_helpers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeConfig points the helpers at the fake Cloud Controller. Calling any other method of the
+// config panics.
+type fakeConfig struct {
+	config.CatsConfig
+	apiEndpoint string
+}
+
+func (c fakeConfig) Protocol() string                       { return "https://" }
+func (c fakeConfig) GetApiEndpoint() string                 { return c.apiEndpoint }
+func (c fakeConfig) GetSkipSSLValidation() bool             { return true }
+func (c fakeConfig) DefaultTimeoutDuration() time.Duration  { return 10 * time.Second }
+func (c fakeConfig) CfPushTimeoutDuration() time.Duration   { return 10 * time.Second }
+func (c fakeConfig) LongCurlTimeoutDuration() time.Duration { return 10 * time.Second }
+
+var fake *fake_cc.Server
+
+func TestV3Helpers(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "V3 Helpers Suite")
+}
+
+var _ = BeforeSuite(func() {
+	fake = fake_cc.New()
+	DeferCleanup(fake.Close)
+	cats_suite_helpers.Config = fakeConfig{apiEndpoint: strings.TrimPrefix(fake.URL(), "https://")}
+
+	DeferCleanup(func(source func() string) { v3_helpers.AuthTokenSource = source }, v3_helpers.AuthTokenSource)
+	v3_helpers.AuthTokenSource = func() string { return fake.Token("admin") }
+})
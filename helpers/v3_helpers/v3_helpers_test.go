@@ -0,0 +1,156 @@
+package v3_helpers_test
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/fake_cc"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
+	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/v3_helpers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("v3 helpers", func() {
+	var orgGUID, spaceGUID, appName string
+
+	BeforeEach(func() {
+		orgGUID = fake.AddOrganization(random_name.CATSRandomName("ORG"))
+		spaceGUID = fake.AddSpace(orgGUID, random_name.CATSRandomName("SPACE"))
+		appName = random_name.CATSRandomName("APP")
+	})
+
+	It("pushes and scales docker apps", func() {
+		appGUID := CreateDockerApp(appName, spaceGUID, `{"FOO": "bar"}`)
+		Expect(GetApp(appName).GUID).To(Equal(appGUID))
+
+		buildGUID := StageDockerPackage(CreateDockerPackage(appGUID, "cloudfoundry/diego-docker-app"))
+		WaitForBuildToStage(buildGUID)
+		AssignDropletToApp(appGUID, GetDropletFromBuild(buildGUID))
+		StartApp(appGUID)
+
+		ScaleApp(appGUID, 3)
+		Expect(GetRunningInstancesStats(GetProcessGuidForType(appGUID, "web"))).To(Equal(3))
+
+		DeleteApp(appGUID)
+	})
+
+	It("deploys droplets", func() {
+		appGUID := CreateDockerApp(appName, spaceGUID, `{}`)
+		buildGUID := StageDockerPackage(CreateDockerPackage(appGUID, "cloudfoundry/diego-docker-app"))
+		WaitForBuildToStage(buildGUID)
+		dropletGUID := GetDropletFromBuild(buildGUID)
+
+		deploymentGUID := CreateDeploymentForDroplet(appGUID, dropletGUID)
+		WaitFor(CCClient().DeploymentOperation(deploymentGUID, "DEPLOYED"), time.Minute)
+		Expect(GetCurrentDropletGuidFromApp(appGUID)).To(Equal(dropletGUID))
+	})
+
+	It("steps through canary deployments", func() {
+		appGUID := CreateDockerApp(appName, spaceGUID, `{}`)
+		buildGUID := StageDockerPackage(CreateDockerPackage(appGUID, "cloudfoundry/diego-docker-app"))
+		WaitForBuildToStage(buildGUID)
+		dropletGUID := GetDropletFromBuild(buildGUID)
+
+		deploymentGUID := CreateDeploymentWithOptions(appGUID, DeploymentOptions{
+			Strategy:    "canary",
+			DropletGuid: dropletGUID,
+			MaxInFlight: 2,
+			CanarySteps: []int{25, 50},
+			Labels:      map[string]string{"step": "canary"},
+		})
+		Expect(GetLatestDeploymentGuid(appGUID)).To(Equal(deploymentGUID))
+
+		deployment := WaitForCanaryStep(deploymentGUID, 1)
+		Expect(deployment.InstanceWeights()).To(Equal([]int{25, 50}))
+		Expect(deployment.Options.MaxInFlight).To(Equal(2))
+		Expect(deployment.Metadata.Labels).To(HaveKeyWithValue("step", "canary"))
+		ContinueDeployment(deploymentGUID)
+		Expect(WaitForCanaryStep(deploymentGUID, 2).CanaryStep()).To(Equal(cc_client.CanaryProgress{Current: 2, Total: 2}))
+		ContinueDeployment(deploymentGUID)
+
+		deployment = WaitForDeploymentToComplete(deploymentGUID)
+		Expect(deployment.Droplet.GUID).To(Equal(dropletGUID))
+		Expect(deployment.Revision).NotTo(BeNil())
+		Expect(GetCurrentDropletGuidFromApp(appGUID)).To(Equal(dropletGUID))
+	})
+
+	It("labels resources and selects them with label selectors", func() {
+		appGUID := CreateDockerApp(appName, spaceGUID, `{}`)
+		otherAppGUID := CreateDockerApp(random_name.CATSRandomName("APP"), spaceGUID, `{}`)
+		Expect(GetGuidByName("apps", appName)).To(Equal(appGUID))
+
+		SetLabels("apps", appGUID, map[string]string{"tier": "web", "stage": "blue"})
+		SetLabels("apps", otherAppGUID, map[string]string{"tier": "api"})
+		SetAnnotations("apps", appGUID, map[string]string{"owner": "cats"})
+		RemoveLabels("apps", appGUID, "stage")
+		metadata := GetMetadata("apps", appGUID)
+		Expect(metadata.Labels).To(HaveKeyWithValue("tier", "web"))
+		Expect(metadata.Labels).NotTo(HaveKey("stage"))
+		Expect(metadata.Annotations).To(Equal(map[string]string{"owner": "cats"}))
+
+		inSpace := url.Values{"space_guids": {spaceGUID}}
+		Expect(GetGuidsMatchingLabelSelector("apps", LabelSelector(LabelEquals("tier", "web")), inSpace)).To(ConsistOf(appGUID))
+		Expect(GetGuidsMatchingLabelSelector("apps", LabelSelector(LabelIn("tier", "web", "api"), LabelNotEquals("tier", "api")), inSpace)).To(ConsistOf(appGUID))
+		Expect(GetGuidsMatchingLabelSelector("apps", LabelSelector(LabelNotIn("tier", "web"), LabelExists("tier")), inSpace)).To(ConsistOf(otherAppGUID))
+		Expect(GetGuidsMatchingLabelSelector("apps", LabelSelector(LabelDoesNotExist("tier")), inSpace)).To(BeEmpty())
+	})
+
+	It("entitles organizations to isolation segments", func() {
+		isolationSegmentName := random_name.CATSRandomName("ISOSEG")
+		isolationSegmentGUID := CreateOrGetIsolationSegment(isolationSegmentName)
+		Expect(CreateOrGetIsolationSegment(isolationSegmentName)).To(Equal(isolationSegmentGUID))
+
+		EntitleOrgToIsolationSegment(orgGUID, isolationSegmentGUID)
+		Expect(OrgEntitledToIsolationSegment(orgGUID, isolationSegmentName)).To(BeTrue())
+		SetDefaultIsolationSegment(orgGUID, isolationSegmentGUID)
+		Expect(GetDefaultIsolationSegment(orgGUID)).To(Equal(isolationSegmentGUID))
+
+		UnsetDefaultIsolationSegment(orgGUID)
+		RevokeOrgEntitlementForIsolationSegment(orgGUID, isolationSegmentGUID)
+		DeleteIsolationSegment(isolationSegmentGUID)
+		Expect(IsolationSegmentExists(isolationSegmentName)).To(BeFalse())
+	})
+
+	It("creates, applies and updates quotas", func() {
+		otherOrgGUID := fake.AddOrganization(random_name.CATSRandomName("ORG"))
+		limits := cc_client.QuotaLimits{Apps: cc_client.AppQuota{TotalMemoryInMB: cc_client.Limit(1024), PerAppTasks: cc_client.Limit(1)}}
+		orgQuotaGUID := CreateOrganizationQuota(random_name.CATSRandomName("QUOTA"), limits, orgGUID)
+		ApplyOrganizationQuota(orgQuotaGUID, otherOrgGUID)
+		orgQuota := GetQuota(OrganizationQuotas, orgQuotaGUID)
+		Expect(orgQuota.Apps.TotalMemoryInMB).To(HaveValue(Equal(1024)))
+		Expect(orgQuota.Apps.TotalInstances).To(BeNil())
+		Expect(orgQuota.Services.PaidServicesAllowed).To(BeNil(), "settings that are not given are left out")
+		Expect(orgQuota.Relationships.Organizations.Data).To(ConsistOf(cc_client.Ref{GUID: orgGUID}, cc_client.Ref{GUID: otherOrgGUID}))
+
+		spaceQuotaGUID := CreateSpaceQuota(random_name.CATSRandomName("QUOTA"), cc_client.QuotaLimits{}, orgGUID)
+		ApplySpaceQuota(spaceQuotaGUID, spaceGUID)
+		limits.Routes.TotalReservedPorts = cc_client.Limit(0)
+		limits.Services.PaidServicesAllowed = cc_client.Allowed(false)
+		UpdateQuota(SpaceQuotas, spaceQuotaGUID, limits)
+		spaceQuota := GetQuota(SpaceQuotas, spaceQuotaGUID)
+		Expect(spaceQuota.QuotaLimits).To(Equal(limits))
+		Expect(spaceQuota.Relationships.Organization.Data.GUID).To(Equal(orgGUID))
+		Expect(spaceQuota.Relationships.Spaces.Data).To(ConsistOf(cc_client.Ref{GUID: spaceGUID}))
+
+		unappliedQuotaGUID := CreateSpaceQuota(random_name.CATSRandomName("QUOTA"), limits, orgGUID)
+		DeleteQuota(SpaceQuotas, unappliedQuotaGUID)
+		_, err := CCClient().GetQuota(SpaceQuotas, unappliedQuotaGUID)
+		Expect(err).To(MatchError(ContainSubstring("404")))
+	})
+
+	It("fails the spec with the error of failed builds", func() {
+		appGUID := CreateDockerApp(appName, spaceGUID, `{}`)
+		fake.Fail(http.MethodPost, "/v3/builds", fake_cc.Failure{
+			Async:  true,
+			Errors: []cc_client.Error{{Detail: "StagingError - Staging error: no compatible cell"}},
+		})
+		buildGUID := StageDockerPackage(CreateDockerPackage(appGUID, "cloudfoundry/diego-docker-app"))
+
+		failures := InterceptGomegaFailures(func() { WaitForBuildToStage(buildGUID) })
+		Expect(failures).To(ConsistOf(ContainSubstring("in state FAILED: StagingError - Staging error: no compatible cell")))
+	})
+})
@@ -52,14 +52,16 @@ var _ = IsolationSegmentsDescribe("IsolationSegments", func() {
 
 			createOrg := cf.Cf("create-org", orgName).Wait()
 			Expect(createOrg).To(Exit(0), "failed to create org")
+			LabelResourcesNamed("organizations", orgName)
 
 			setQuota := cf.Cf("set-quota", orgName, quotaName).Wait(TestSetup.ShortTimeout())
 			Expect(setQuota).To(Exit(0))
 
 			createSpace := cf.Cf("create-space", spaceName, "-o", orgName).Wait()
 			Expect(createSpace).To(Exit(0), "failed to create space")
+			LabelResourcesNamed("spaces", spaceName)
 
-			addSpaceDeveloper := cf.Cf("set-space-role", TestSetup.RegularUserContext().Username, orgName, spaceName, "SpaceDeveloper").Wait()
+			addSpaceDeveloper := cf.Cf(RegularUserRoleArgs("set-space-role", orgName, spaceName, "SpaceDeveloper")...).Wait()
 			Expect(addSpaceDeveloper).To(Exit(0), "failed to add space developer role")
 
 			session := cf.Cf("curl", fmt.Sprintf("/v3/organizations?names=%s", orgName))
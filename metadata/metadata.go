@@ -0,0 +1,265 @@
+package metadata
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/manifest"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/v3_helpers"
+	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
+	"github.com/cloudfoundry/cf-test-helpers/v2/workflowhelpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+)
+
+// labelledResources are two resources of a v3 collection, labelled with key=a and key=b, and the
+// query restricting lists of the collection to the resources created by the spec. Shared
+// resources, such as stacks, only label the first one and leave other and scope empty.
+type labelledResources struct {
+	collection string
+	labelled   string
+	other      string
+	scope      url.Values
+}
+
+var _ = MetadataDescribe("metadata", func() {
+	var (
+		orgName   string
+		spaceName string
+		spaceGuid string
+		key       string
+	)
+
+	asAdmin := func(actions func()) {
+		workflowhelpers.AsUser(TestSetup.AdminUserContext(), Config.DefaultTimeoutDuration(), func() {
+			Expect(cf.Cf("target", "-o", orgName, "-s", spaceName).Wait()).To(Exit(0))
+			actions()
+		})
+	}
+
+	setLabel := func(resourceType, name, value string) {
+		Expect(cf.Cf("set-label", resourceType, name, fmt.Sprintf("%s=%s", key, value)).Wait()).To(Exit(0))
+	}
+
+	// labelShared labels a resource shared with other tests through the API and removes the label
+	// once the spec is done.
+	labelShared := func(collection, name string) labelledResources {
+		guid := v3_helpers.GetGuidByName(collection, name)
+		v3_helpers.SetLabels(collection, guid, map[string]string{key: "a"})
+		v3_helpers.SetAnnotations(collection, guid, map[string]string{key: "shared"})
+		DeferCleanup(func() {
+			asAdmin(func() {
+				v3_helpers.RemoveLabels(collection, guid, key)
+				v3_helpers.RemoveAnnotations(collection, guid, key)
+			})
+		})
+		return labelledResources{collection: collection, labelled: guid}
+	}
+
+	BeforeEach(func() {
+		orgName = TestSetup.RegularUserContext().Org
+		spaceName = TestSetup.RegularUserContext().Space
+		spaceGuid = v3_helpers.GetGuidByName("spaces", spaceName)
+		key = random_name.CATSRandomName("LABEL")
+	})
+
+	DescribeTable("selects labelled resources with label selectors",
+		func(label func() labelledResources) {
+			var resources labelledResources
+			asAdmin(func() {
+				resources = label()
+
+				selected := func(requirements ...string) []string {
+					return v3_helpers.GetGuidsMatchingLabelSelector(resources.collection, v3_helpers.LabelSelector(requirements...), resources.scope)
+				}
+
+				all := []string{resources.labelled}
+				if resources.other != "" {
+					all = append(all, resources.other)
+				}
+
+				Expect(selected(v3_helpers.LabelEquals(key, "a"))).To(ConsistOf(resources.labelled))
+				Expect(selected(v3_helpers.LabelNotEquals(key, "a"))).NotTo(ContainElement(resources.labelled))
+				Expect(selected(v3_helpers.LabelIn(key, "a", "b"))).To(ConsistOf(all))
+				Expect(selected(v3_helpers.LabelNotIn(key, "a"))).NotTo(ContainElement(resources.labelled))
+				Expect(selected(v3_helpers.LabelExists(key))).To(ConsistOf(all))
+				withoutLabel := selected(v3_helpers.LabelDoesNotExist(key))
+				for _, guid := range all {
+					Expect(withoutLabel).NotTo(ContainElement(guid))
+				}
+				Expect(selected(v3_helpers.LabelExists(key), v3_helpers.LabelNotIn(key, "b"))).To(ConsistOf(resources.labelled))
+				if resources.other != "" {
+					Expect(selected(v3_helpers.LabelNotEquals(key, "a"))).To(ContainElement(resources.other))
+					Expect(selected(v3_helpers.LabelNotIn(key, "a"))).To(ContainElement(resources.other))
+				}
+
+				By("annotating the labelled resource")
+				v3_helpers.SetAnnotations(resources.collection, resources.labelled, map[string]string{key: "annotated"})
+				Expect(v3_helpers.GetMetadata(resources.collection, resources.labelled).Annotations).To(HaveKeyWithValue(key, "annotated"))
+
+				By("removing the label")
+				v3_helpers.RemoveLabels(resources.collection, resources.labelled, key)
+				Expect(v3_helpers.GetMetadata(resources.collection, resources.labelled).Labels).NotTo(HaveKey(key))
+				Expect(selected(v3_helpers.LabelEquals(key, "a"))).To(BeEmpty())
+			})
+		},
+		Entry("apps", func() labelledResources {
+			names := []string{random_name.CATSRandomName("APP"), random_name.CATSRandomName("APP")}
+			for _, name := range names {
+				Expect(cf.Cf("create-app", name).Wait()).To(Exit(0))
+				DeferCleanup(func() {
+					asAdmin(func() { Expect(cf.Cf("delete", name, "-f").Wait()).To(Exit(0)) })
+				})
+			}
+			setLabel("app", names[0], "a")
+			setLabel("app", names[1], "b")
+			return labelledResources{
+				collection: "apps",
+				labelled:   app_helpers.GetAppGuid(names[0]),
+				other:      app_helpers.GetAppGuid(names[1]),
+				scope:      url.Values{"space_guids": {spaceGuid}},
+			}
+		}),
+		Entry("spaces", func() labelledResources {
+			otherSpaceName := random_name.CATSRandomName("SPACE")
+			Expect(cf.Cf("create-space", otherSpaceName, "-o", orgName).Wait()).To(Exit(0))
+			LabelResourcesNamed("spaces", otherSpaceName)
+			DeferCleanup(func() {
+				asAdmin(func() { Expect(cf.Cf("delete-space", otherSpaceName, "-o", orgName, "-f").Wait()).To(Exit(0)) })
+			})
+			setLabel("space", spaceName, "a")
+			setLabel("space", otherSpaceName, "b")
+			DeferCleanup(func() {
+				asAdmin(func() { Expect(cf.Cf("unset-label", "space", spaceName, key).Wait()).To(Exit(0)) })
+			})
+			return labelledResources{
+				collection: "spaces",
+				labelled:   spaceGuid,
+				other:      v3_helpers.GetGuidByName("spaces", otherSpaceName),
+				scope:      url.Values{"organization_guids": {v3_helpers.GetGuidByName("organizations", orgName)}},
+			}
+		}),
+		Entry("routes", func() labelledResources {
+			hosts := []string{random_name.CATSRandomName("ROUTE"), random_name.CATSRandomName("ROUTE")}
+			for _, host := range hosts {
+				Expect(cf.Cf("create-route", Config.GetAppsDomain(), "--hostname", host).Wait()).To(Exit(0))
+				LabelResource("/v3/routes/" + v3_helpers.GetRouteGuid(host))
+				DeferCleanup(func() {
+					asAdmin(func() {
+						Expect(cf.Cf("delete-route", Config.GetAppsDomain(), "--hostname", host, "-f").Wait()).To(Exit(0))
+					})
+				})
+			}
+			setLabel("route", fmt.Sprintf("%s.%s", hosts[0], Config.GetAppsDomain()), "a")
+			setLabel("route", fmt.Sprintf("%s.%s", hosts[1], Config.GetAppsDomain()), "b")
+			return labelledResources{
+				collection: "routes",
+				labelled:   v3_helpers.GetRouteGuid(hosts[0]),
+				other:      v3_helpers.GetRouteGuid(hosts[1]),
+				scope:      url.Values{"space_guids": {spaceGuid}},
+			}
+		}),
+		Entry("service instances", func() labelledResources {
+			names := []string{random_name.CATSRandomName("SVIN"), random_name.CATSRandomName("SVIN")}
+			for _, name := range names {
+				Expect(cf.Cf("create-user-provided-service", name).Wait()).To(Exit(0))
+				LabelResourcesNamed("service_instances", name)
+				DeferCleanup(func() {
+					asAdmin(func() { Expect(cf.Cf("delete-service", name, "-f").Wait()).To(Exit(0)) })
+				})
+			}
+			setLabel("service-instance", names[0], "a")
+			setLabel("service-instance", names[1], "b")
+			return labelledResources{
+				collection: "service_instances",
+				labelled:   v3_helpers.GetGuidByName("service_instances", names[0]),
+				other:      v3_helpers.GetGuidByName("service_instances", names[1]),
+				scope:      url.Values{"space_guids": {spaceGuid}},
+			}
+		}),
+		Entry("organizations", func() labelledResources {
+			setLabel("org", orgName, "a")
+			DeferCleanup(func() {
+				asAdmin(func() { Expect(cf.Cf("unset-label", "org", orgName, key).Wait()).To(Exit(0)) })
+			})
+			return labelledResources{collection: "organizations", labelled: v3_helpers.GetGuidByName("organizations", orgName)}
+		}),
+		Entry("buildpacks", func() labelledResources {
+			return labelShared("buildpacks", Config.GetBinaryBuildpackName())
+		}),
+		Entry("stacks", func() labelledResources {
+			return labelShared("stacks", Config.GetStacks()[0])
+		}),
+		Entry("domains", func() labelledResources {
+			return labelShared("domains", Config.GetAppsDomain())
+		}),
+	)
+
+	Describe("app metadata", func() {
+		var appName string
+
+		BeforeEach(func() {
+			appName = random_name.CATSRandomName("APP")
+		})
+
+		AfterEach(func() {
+			app_helpers.AppReport(appName)
+			Expect(cf.Cf("delete", appName, "-f", "-r").Wait()).To(Exit(0))
+		})
+
+		It("keeps labels and annotations across pushes, restages and deployments", func() {
+			manifestFile := createManifest(appName)
+			Expect(cf.Cf("push", appName, "-f", manifestFile).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+			appGuid := app_helpers.GetAppGuid(appName)
+			setLabel("app", appName, "cli")
+
+			expectMetadata := func() {
+				metadata := v3_helpers.GetMetadata("apps", appGuid)
+				Expect(metadata.Labels).To(HaveKeyWithValue(key, "cli"))
+				Expect(metadata.Labels).To(HaveKeyWithValue("tier", "web"))
+				Expect(metadata.Annotations).To(HaveKeyWithValue("owner", "cats"))
+				Expect(v3_helpers.GetGuidsMatchingLabelSelector("apps",
+					v3_helpers.LabelSelector(v3_helpers.LabelEquals(key, "cli"), v3_helpers.LabelIn("tier", "web")),
+					url.Values{"space_guids": {spaceGuid}},
+				)).To(ConsistOf(appGuid))
+			}
+			expectMetadata()
+
+			By("restaging the app")
+			Expect(cf.Cf("restage", appName).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+			expectMetadata()
+
+			By("restarting the app with a rolling deployment")
+			Expect(cf.Cf("restart", appName, "--strategy", "rolling").Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+			expectMetadata()
+
+			By("pushing the manifest again")
+			Expect(cf.Cf("push", appName, "-f", manifestFile, "--strategy", "rolling").Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+			expectMetadata()
+		})
+	})
+})
+
+// createManifest writes a manifest for catnip labelled tier=web and annotated owner=cats.
+func createManifest(appName string) string {
+	appPath, err := filepath.Abs(assets.NewAssets().Catnip)
+	Expect(err).ToNot(HaveOccurred())
+
+	return app_helpers.WriteManifest(manifest.Manifest{Applications: []manifest.Application{{
+		Name:       appName,
+		Path:       appPath,
+		Buildpacks: []string{Config.GetBinaryBuildpackName()},
+		Command:    "./catnip",
+		Memory:     DEFAULT_MEMORY_LIMIT,
+		Metadata: &manifest.Metadata{
+			Labels:      map[string]string{"tier": "web"},
+			Annotations: map[string]string{"owner": "cats"},
+		},
+	}}})
+}
@@ -0,0 +1,228 @@
+package quotas
+
+import (
+	"fmt"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/cc_client"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/services"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/v3_helpers"
+	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
+	"github.com/cloudfoundry/cf-test-helpers/v2/workflowhelpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+)
+
+// The CC reports exceeded quotas as unprocessable entities.
+const unprocessableEntityCode = 10008
+
+var _ = QuotasDescribe("quotas", func() {
+	var (
+		orgName      string
+		spaceName    string
+		orgGuid      string
+		spaceGuid    string
+		orgQuotaGuid string
+		appName      string
+
+		// setLimits replaces the limits of the quota under test.
+		setLimits func(cc_client.QuotaLimits)
+	)
+
+	asAdmin := func(actions func()) {
+		workflowhelpers.AsUser(TestSetup.AdminUserContext(), Config.DefaultTimeoutDuration(), actions)
+	}
+
+	// asDeveloper runs actions as the regular user, targeting the space with the quotas.
+	asDeveloper := func(actions func()) {
+		workflowhelpers.AsUser(TestSetup.RegularUserContext(), Config.DefaultTimeoutDuration(), func() {
+			Expect(cf.Cf("target", "-o", orgName, "-s", spaceName).Wait()).To(Exit(0))
+			actions()
+		})
+	}
+
+	// expectQuotaExceeded runs cf with request tracing and expects it to fail with the CC error of
+	// exceeded quotas and a detail matching detail.
+	expectQuotaExceeded := func(detail string, args ...string) {
+		session := cf.Cf(append(args, "-v")...).Wait(Config.CfPushTimeoutDuration())
+		Expect(session).To(Exit(1))
+		output := string(session.Out.Contents()) + string(session.Err.Contents())
+		Expect(output).To(MatchRegexp(`"code":\s*%d`, unprocessableEntityCode))
+		Expect(output).To(MatchRegexp(detail))
+	}
+
+	expectSuccess := func(args ...string) {
+		Expect(cf.Cf(args...).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+	}
+
+	pushApp := func(args ...string) {
+		expectSuccess(app_helpers.CatnipWithArgs(appName, append([]string{"-m", DEFAULT_MEMORY_LIMIT}, args...)...)...)
+	}
+
+	BeforeEach(func() {
+		orgName = random_name.CATSRandomName("ORG")
+		spaceName = random_name.CATSRandomName("SPACE")
+		appName = random_name.CATSRandomName("APP")
+
+		asAdmin(func() {
+			Expect(cf.Cf("create-org", orgName).Wait()).To(Exit(0), "failed to create org")
+			Expect(cf.Cf("create-space", spaceName, "-o", orgName).Wait()).To(Exit(0), "failed to create space")
+			LabelResourcesNamed("organizations", orgName)
+			LabelResourcesNamed("spaces", spaceName)
+			Expect(cf.Cf(RegularUserRoleArgs("set-space-role", orgName, spaceName, "SpaceDeveloper")...).Wait()).To(Exit(0))
+			orgGuid = v3_helpers.GetGuidByName("organizations", orgName)
+			spaceGuid = v3_helpers.GetGuidByName("spaces", spaceName)
+			orgQuotaGuid = v3_helpers.CreateOrganizationQuota(random_name.CATSRandomName("QUOTA"), cc_client.QuotaLimits{}, orgGuid)
+		})
+	})
+
+	AfterEach(func() {
+		asAdmin(func() {
+			Expect(cf.Cf("delete-org", orgName, "-f").Wait(Config.CfPushTimeoutDuration())).To(Exit(0), "failed to delete org")
+			v3_helpers.DeleteQuota(v3_helpers.OrganizationQuotas, orgQuotaGuid)
+		})
+	})
+
+	enforcesLimits := func() {
+		It("enforces the total memory limit on push and scale until it is raised", func() {
+			asDeveloper(func() {
+				pushApp()
+				setLimits(cc_client.QuotaLimits{Apps: cc_client.AppQuota{TotalMemoryInMB: cc_client.Limit(512)}})
+
+				By("pushing an app that needs more memory than is left")
+				otherAppName := random_name.CATSRandomName("APP")
+				expectQuotaExceeded(`quota_exceeded|memory limit exceeded`, app_helpers.CatnipWithArgs(otherAppName, "-m", "512M")...)
+
+				By("scaling beyond the limit")
+				expectQuotaExceeded(`quota_exceeded`, "scale", appName, "-i", "3")
+
+				setLimits(cc_client.QuotaLimits{Apps: cc_client.AppQuota{TotalMemoryInMB: cc_client.Limit(1024)}})
+				expectSuccess("scale", appName, "-i", "3")
+			})
+		})
+
+		It("enforces the instance memory limit until it is raised", func() {
+			asDeveloper(func() {
+				pushApp()
+				setLimits(cc_client.QuotaLimits{Apps: cc_client.AppQuota{PerProcessMemoryInMB: cc_client.Limit(256)}})
+				expectQuotaExceeded(`instance_memory_limit_exceeded`, "scale", appName, "-m", "512M", "-f")
+
+				setLimits(cc_client.QuotaLimits{Apps: cc_client.AppQuota{PerProcessMemoryInMB: cc_client.Limit(512)}})
+				expectSuccess("scale", appName, "-m", "512M", "-f")
+			})
+		})
+
+		It("enforces the app instance limit until it is raised", func() {
+			asDeveloper(func() {
+				pushApp()
+				setLimits(cc_client.QuotaLimits{Apps: cc_client.AppQuota{TotalInstances: cc_client.Limit(1)}})
+				expectQuotaExceeded(`instance_limit_exceeded`, "scale", appName, "-i", "2")
+
+				setLimits(cc_client.QuotaLimits{Apps: cc_client.AppQuota{TotalInstances: cc_client.Limit(2)}})
+				expectSuccess("scale", appName, "-i", "2")
+			})
+		})
+
+		It("enforces the log rate limit until it is raised", func() {
+			asDeveloper(func() {
+				pushApp("-l", "1K")
+				setLimits(cc_client.QuotaLimits{Apps: cc_client.AppQuota{LogRateLimitInBytesPerSecond: cc_client.Limit(2048)}})
+				expectQuotaExceeded(`log_rate_limit|log rate`, "scale", appName, "-i", "3")
+
+				setLimits(cc_client.QuotaLimits{Apps: cc_client.AppQuota{LogRateLimitInBytesPerSecond: cc_client.Limit(4096)}})
+				expectSuccess("scale", appName, "-i", "3")
+			})
+		})
+
+		It("enforces the limit of tasks per app until it is raised", RequiresTasks, func() {
+			asDeveloper(func() {
+				pushApp()
+				setLimits(cc_client.QuotaLimits{Apps: cc_client.AppQuota{PerAppTasks: cc_client.Limit(1)}})
+				expectSuccess("run-task", appName, "--command", "sleep 300")
+				expectQuotaExceeded(`app_task_limit|task limit`, "run-task", appName, "--command", "sleep 300")
+
+				setLimits(cc_client.QuotaLimits{Apps: cc_client.AppQuota{PerAppTasks: cc_client.Limit(2)}})
+				expectSuccess("run-task", appName, "--command", "sleep 300")
+			})
+		})
+
+		It("enforces the route limit until it is raised", func() {
+			asDeveloper(func() {
+				setLimits(cc_client.QuotaLimits{Routes: cc_client.RouteQuota{TotalRoutes: cc_client.Limit(1)}})
+				expectSuccess("create-route", Config.GetAppsDomain(), "--hostname", random_name.CATSRandomName("ROUTE"))
+				host := random_name.CATSRandomName("ROUTE")
+				expectQuotaExceeded(`(?i)routes quota exceeded`, "create-route", Config.GetAppsDomain(), "--hostname", host)
+
+				setLimits(cc_client.QuotaLimits{Routes: cc_client.RouteQuota{TotalRoutes: cc_client.Limit(2)}})
+				expectSuccess("create-route", Config.GetAppsDomain(), "--hostname", host)
+			})
+		})
+
+		It("enforces the reserved route port limit until it is raised", RequiresTCPRouting, func() {
+			asDeveloper(func() {
+				By("finding a free port of the TCP domain")
+				setLimits(cc_client.QuotaLimits{Routes: cc_client.RouteQuota{TotalReservedPorts: cc_client.Limit(1)}})
+				expectSuccess("create-route", Config.GetTCPDomain())
+				ports := v3_helpers.GetRoutePorts(spaceGuid, v3_helpers.GetGuidByName("domains", Config.GetTCPDomain()))
+				Expect(ports).To(HaveLen(1))
+				port := fmt.Sprint(ports[0])
+				expectSuccess("delete-route", Config.GetTCPDomain(), "--port", port, "-f")
+
+				setLimits(cc_client.QuotaLimits{Routes: cc_client.RouteQuota{TotalReservedPorts: cc_client.Limit(0)}})
+				expectQuotaExceeded(`(?i)reserved route ports quota exceeded`, "create-route", Config.GetTCPDomain(), "--port", port)
+
+				setLimits(cc_client.QuotaLimits{Routes: cc_client.RouteQuota{TotalReservedPorts: cc_client.Limit(1)}})
+				expectSuccess("create-route", Config.GetTCPDomain(), "--port", port)
+			})
+		})
+
+		It("enforces the service instance limit until it is raised", RequiresServices, func() {
+			broker := services.NewServiceBroker(random_name.CATSRandomName("BRKR"), assets.NewAssets().ServiceBroker, TestSetup)
+			broker.Push(Config)
+			broker.Configure()
+			broker.Create()
+			broker.PublicizePlans()
+			DeferCleanup(broker.Destroy)
+
+			asDeveloper(func() {
+				limits := cc_client.QuotaLimits{Services: cc_client.ServiceQuota{PaidServicesAllowed: cc_client.Allowed(true), TotalServiceInstances: cc_client.Limit(0)}}
+				setLimits(limits)
+				instanceName := random_name.CATSRandomName("SVIN")
+				expectQuotaExceeded(`(?i)services limit`, "create-service", broker.Service.Name, broker.SyncPlans[0].Name, instanceName)
+
+				limits.Services.TotalServiceInstances = cc_client.Limit(1)
+				setLimits(limits)
+				expectSuccess("create-service", broker.Service.Name, broker.SyncPlans[0].Name, instanceName)
+				LabelResourcesNamed("service_instances", instanceName)
+			})
+		})
+	}
+
+	Context("with an organization quota", func() {
+		BeforeEach(func() {
+			setLimits = func(limits cc_client.QuotaLimits) {
+				asAdmin(func() { v3_helpers.UpdateQuota(v3_helpers.OrganizationQuotas, orgQuotaGuid, limits) })
+			}
+		})
+
+		enforcesLimits()
+	})
+
+	Context("with a space quota", func() {
+		BeforeEach(func() {
+			var spaceQuotaGuid string
+			asAdmin(func() {
+				spaceQuotaGuid = v3_helpers.CreateSpaceQuota(random_name.CATSRandomName("QUOTA"), cc_client.QuotaLimits{}, orgGuid, spaceGuid)
+			})
+			setLimits = func(limits cc_client.QuotaLimits) {
+				asAdmin(func() { v3_helpers.UpdateQuota(v3_helpers.SpaceQuotas, spaceQuotaGuid, limits) })
+			}
+		})
+
+		enforcesLimits()
+	})
+})
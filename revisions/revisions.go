@@ -0,0 +1,121 @@
+package revisions
+
+import (
+	. "github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/v3_helpers"
+	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
+	"github.com/cloudfoundry/cf-test-helpers/v2/helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = RevisionsDescribe("revisions", func() {
+	var (
+		appName string
+		appGuid string
+	)
+
+	setEnvAndRestart := func(value string) {
+		Expect(cf.Cf("set-env", appName, "CATS_REVISION", value).Wait()).To(Exit(0))
+		Expect(cf.Cf("restart", appName).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+	}
+
+	repush := func() {
+		Expect(cf.Cf(app_helpers.CatnipWithArgs(appName, "-m", DEFAULT_MEMORY_LIMIT)...).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+	}
+
+	expectAppToSeeRevision := func(value string) {
+		Eventually(func() string {
+			return helpers.CurlApp(Config, appName, "/env/CATS_REVISION")
+		}).Should(Equal(value))
+	}
+
+	BeforeEach(func() {
+		appName = random_name.CATSRandomName("APP")
+		Expect(cf.Cf(app_helpers.CatnipWithArgs(appName, "-m", DEFAULT_MEMORY_LIMIT, "--no-start")...).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+		appGuid = app_helpers.GetAppGuid(appName)
+		setEnvAndRestart("one")
+		expectAppToSeeRevision("one")
+	})
+
+	AfterEach(func() {
+		app_helpers.AppReport(appName)
+		Expect(cf.Cf("delete", appName, "-f", "-r").Wait()).To(Exit(0))
+	})
+
+	It("creates a revision when the droplet, environment variables or start command change", func() {
+		revisions := v3_helpers.GetRevisions(appGuid)
+		Expect(revisions).To(HaveLen(1))
+		initial := revisions[0]
+		Expect(initial.Description).To(Equal("Initial revision."))
+		Expect(initial.Deployable).To(BeTrue())
+		Expect(initial.Droplet.GUID).To(Equal(v3_helpers.GetCurrentDropletGuidFromApp(appGuid)))
+		Expect(initial.Processes).To(HaveKeyWithValue("web", HaveField("Command", HaveValue(Equal("./catnip")))))
+		Expect(v3_helpers.GetRevisionEnvironmentVariables(initial.GUID)).To(HaveKeyWithValue("CATS_REVISION", "one"))
+
+		By("changing the environment variables")
+		setEnvAndRestart("two")
+		environmentRevision := v3_helpers.GetLatestRevision(appGuid)
+		Expect(environmentRevision.Version).To(Equal(initial.Version + 1))
+		Expect(environmentRevision.Description).To(ContainSubstring("New environment variables deployed."))
+		Expect(environmentRevision.Droplet.GUID).To(Equal(initial.Droplet.GUID))
+		Expect(v3_helpers.GetRevisionEnvironmentVariables(environmentRevision.GUID)).To(HaveKeyWithValue("CATS_REVISION", "two"))
+
+		By("changing the start command")
+		web := v3_helpers.GetProcessByType(v3_helpers.GetProcesses(appGuid, appName), "web")
+		v3_helpers.SetProcessCommand(web.Guid, "exec ./catnip")
+		Expect(cf.Cf("restart", appName).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+		commandRevision := v3_helpers.GetLatestRevision(appGuid)
+		Expect(commandRevision.Version).To(Equal(environmentRevision.Version + 1))
+		Expect(commandRevision.Description).To(ContainSubstring("start command"))
+		Expect(commandRevision.Processes).To(HaveKeyWithValue("web", HaveField("Command", HaveValue(Equal("exec ./catnip")))))
+
+		By("staging a new droplet")
+		repush()
+		dropletRevision := v3_helpers.GetLatestRevision(appGuid)
+		Expect(dropletRevision.Version).To(BeNumerically(">", commandRevision.Version))
+		Expect(dropletRevision.Description).To(ContainSubstring("New droplet deployed."))
+		Expect(dropletRevision.Droplet.GUID).NotTo(Equal(initial.Droplet.GUID))
+		Expect(dropletRevision.Droplet.GUID).To(Equal(v3_helpers.GetCurrentDropletGuidFromApp(appGuid)))
+
+		By("restarting without changes")
+		Expect(cf.Cf("restart", appName).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+		Expect(v3_helpers.GetLatestRevision(appGuid).GUID).To(Equal(dropletRevision.GUID))
+
+		Expect(v3_helpers.GetDeployedRevisions(appGuid)).To(ConsistOf(HaveField("GUID", dropletRevision.GUID)))
+	})
+
+	It("rolls back to the droplet and environment variables of a previous revision", func() {
+		initial := v3_helpers.GetLatestRevision(appGuid)
+
+		By("pushing a second version with a new droplet and environment")
+		Expect(cf.Cf("set-env", appName, "CATS_REVISION", "two").Wait()).To(Exit(0))
+		repush()
+		expectAppToSeeRevision("two")
+		second := v3_helpers.GetLatestRevision(appGuid)
+		Expect(second.Droplet.GUID).NotTo(Equal(initial.Droplet.GUID))
+		Expect(v3_helpers.GetDeployedRevisions(appGuid)).To(ConsistOf(HaveField("GUID", second.GUID)))
+
+		By("rolling back to the initial revision")
+		deployment := v3_helpers.RollbackToRevision(appGuid, initial.GUID)
+		Expect(deployment.Droplet.GUID).To(Equal(initial.Droplet.GUID))
+		Expect(deployment.PreviousDroplet.GUID).To(Equal(second.Droplet.GUID))
+
+		rollback := v3_helpers.GetLatestRevision(appGuid)
+		Expect(deployment.Revision).NotTo(BeNil())
+		Expect(deployment.Revision.GUID).To(Equal(rollback.GUID))
+		Expect(rollback.Version).To(Equal(second.Version + 1))
+		Expect(rollback.Description).To(ContainSubstring("Rolled back to revision %d.", initial.Version))
+		Expect(rollback.Droplet.GUID).To(Equal(initial.Droplet.GUID))
+		Expect(v3_helpers.GetDeployedRevisions(appGuid)).To(ConsistOf(HaveField("GUID", rollback.GUID)))
+
+		By("verifying the environment of the initial revision is back")
+		Expect(v3_helpers.GetRevisionEnvironmentVariables(rollback.GUID)).To(HaveKeyWithValue("CATS_REVISION", "one"))
+		Expect(v3_helpers.GetAppEnvironmentVariables(appGuid)).To(HaveKeyWithValue("CATS_REVISION", "one"))
+		Expect(v3_helpers.GetCurrentDropletGuidFromApp(appGuid)).To(Equal(initial.Droplet.GUID))
+		expectAppToSeeRevision("one")
+	})
+})
@@ -0,0 +1,240 @@
+package role_matrix
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/config"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
+	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
+	"github.com/cloudfoundry/cf-test-helpers/v2/workflowhelpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+)
+
+// role is a space or organization role, named as in cf set-space-role and cf set-org-role.
+type role string
+
+const (
+	spaceDeveloper role = "SpaceDeveloper"
+	spaceSupporter role = "SpaceSupporter"
+	spaceManager   role = "SpaceManager"
+	spaceAuditor   role = "SpaceAuditor"
+	orgAuditor     role = "OrgAuditor"
+	billingManager role = "BillingManager"
+)
+
+var (
+	spaceRoles = []role{spaceDeveloper, spaceSupporter, spaceManager, spaceAuditor}
+	orgRoles   = []role{orgAuditor, billingManager}
+)
+
+func (r role) isOrgRole() bool {
+	return slices.Contains(orgRoles, r)
+}
+
+// Placeholders in the arguments of operations, replaced when the operation is run. guid is the GUID
+// of the app.
+const (
+	app       = "{app}"
+	guid      = "{guid}"
+	unique    = "{unique}"
+	domain    = "{domain}"
+	service   = "{service}"
+	catnip    = "{catnip}"
+	buildpack = "{buildpack}"
+)
+
+// operation is a row of the permission matrix: a cf command, run against the app of the suite,
+// and the roles that are allowed to run it. All other roles must be forbidden from running it.
+type operation struct {
+	name    string
+	args    []string
+	allowed []role
+
+	// evidence must appear in the output for the command to count as allowed, and hidden for it to
+	// count as forbidden, for commands that succeed with an empty result for users who cannot see
+	// anything, such as listing audit events.
+	evidence string
+	hidden   string
+
+	// enabled reports whether the operation can be checked with the config. Nil means always.
+	enabled func(config.CatsConfig) bool
+}
+
+func cmd(args ...string) []string {
+	return args
+}
+
+// The outcomes of running an operation as a role.
+const (
+	allowedOutcome   = "allowed"
+	forbiddenOutcome = "forbidden"
+)
+
+// notAuthorized matches the output of a command the Cloud Controller refused to run for the user
+// with a CF-NotAuthorized error, as the cf CLI prints it.
+var notAuthorized = regexp.MustCompile(`CF-NotAuthorized|\b10003\b|You are not authorized to perform the requested action`)
+
+var operations = []operation{
+	{name: "view the app", args: cmd("app", app), allowed: spaceRoles},
+	{name: "push", args: cmd("push", unique, "-p", catnip, "-b", buildpack, "-m", DEFAULT_MEMORY_LIMIT, "--no-start"), allowed: []role{spaceDeveloper}},
+	{name: "scale", args: cmd("scale", app, "-i", "1"), allowed: []role{spaceDeveloper, spaceSupporter}},
+	{name: "ssh", args: cmd("ssh", app, "-c", "true"), allowed: []role{spaceDeveloper}, enabled: config.CatsConfig.GetIncludeSsh},
+	{name: "env", args: cmd("env", app), allowed: []role{spaceDeveloper}},
+	{name: "logs", args: cmd("logs", app, "--recent"), allowed: spaceRoles},
+	{name: "bind", args: cmd("bind-service", app, service), allowed: []role{spaceDeveloper, spaceSupporter}},
+	{name: "create route", args: cmd("create-route", domain, "--hostname", unique), allowed: []role{spaceDeveloper}},
+	{name: "read events", args: cmd("curl", "/v3/audit_events?target_guids="+guid), allowed: []role{spaceDeveloper, spaceSupporter, spaceAuditor}, evidence: `"audit\.app\.`, hidden: `"total_results":\s*0\b`},
+}
+
+// roleUser is a user created for a role, which satisfies the user values of cf-test-helpers.
+type roleUser struct {
+	name     string
+	password string
+}
+
+func (u roleUser) Username() string { return u.name }
+func (u roleUser) Password() string { return u.password }
+func (u roleUser) Origin() string   { return "" }
+
+var _ = RoleMatrixDescribe("role matrix", func() {
+	var (
+		orgName     string
+		spaceName   string
+		appName     string
+		appGuid     string
+		serviceName string
+		users       map[role]roleUser
+		pushedApps  []string
+	)
+
+	BeforeAll(func() {
+		orgName = TestSetup.RegularUserContext().Org
+		spaceName = TestSetup.RegularUserContext().Space
+		appName = random_name.CATSRandomName("APP")
+		serviceName = random_name.CATSRandomName("SVIN")
+
+		Expect(cf.Cf(app_helpers.CatnipWithArgs(appName, "-m", DEFAULT_MEMORY_LIMIT)...).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+		appGuid = app_helpers.GetAppGuid(appName)
+		Expect(cf.Cf("create-user-provided-service", serviceName).Wait()).To(Exit(0))
+		LabelResourcesNamed("service_instances", serviceName)
+
+		users = map[role]roleUser{}
+		workflowhelpers.AsUser(TestSetup.AdminUserContext(), Config.DefaultTimeoutDuration(), func() {
+			for _, r := range append(slices.Clone(spaceRoles), orgRoles...) {
+				user := roleUser{name: random_name.CATSRandomName("USER"), password: random_name.CATSRandomName("PASSWORD")}
+				Expect(cf.Cf("create-user", user.name, user.password).Wait()).To(Exit(0))
+				if r.isOrgRole() {
+					Expect(cf.Cf("set-org-role", user.name, orgName, string(r)).Wait()).To(Exit(0))
+				} else {
+					Expect(cf.Cf("set-space-role", user.name, orgName, spaceName, string(r)).Wait()).To(Exit(0))
+				}
+				users[r] = user
+			}
+		})
+	})
+
+	AfterAll(func() {
+		app_helpers.AppReport(appName)
+		for _, pushed := range append(pushedApps, appName) {
+			Expect(cf.Cf("delete", pushed, "-f", "-r").Wait()).To(Exit(0))
+		}
+		Expect(cf.Cf("delete-service", serviceName, "-f").Wait()).To(Exit(0))
+		Expect(cf.Cf("delete-orphaned-routes", "-f").Wait()).To(Exit(0))
+		workflowhelpers.AsUser(TestSetup.AdminUserContext(), Config.DefaultTimeoutDuration(), func() {
+			for _, user := range users {
+				Expect(cf.Cf("delete-user", user.name, "-f").Wait()).To(Exit(0))
+			}
+		})
+	})
+
+	// run runs the operation as the current user and returns its outcome, with the output of the
+	// command. Commands that fail for other reasons than the authorization of the user have neither
+	// outcome.
+	run := func(op operation) (string, string) {
+		name := random_name.CATSRandomName("APP")
+		replacer := strings.NewReplacer(
+			app, appName,
+			guid, appGuid,
+			unique, name,
+			domain, Config.GetAppsDomain(),
+			service, serviceName,
+			catnip, assets.NewAssets().Catnip,
+			buildpack, Config.GetBinaryBuildpackName(),
+		)
+		var args []string
+		for _, arg := range op.args {
+			args = append(args, replacer.Replace(arg))
+		}
+		if op.name == "push" {
+			pushedApps = append(pushedApps, name)
+		}
+
+		session := cf.Cf(args...).Wait(Config.CfPushTimeoutDuration())
+		output := string(session.Out.Contents()) + string(session.Err.Contents())
+		outcome := "failed"
+		switch {
+		case session.ExitCode() == 0 && (op.evidence == "" || regexp.MustCompile(op.evidence).MatchString(output)):
+			outcome = allowedOutcome
+		case session.ExitCode() == 0 && op.hidden != "" && regexp.MustCompile(op.hidden).MatchString(output):
+			outcome = forbiddenOutcome
+		case session.ExitCode() != 0 && notAuthorized.MatchString(output):
+			outcome = forbiddenOutcome
+		}
+		return outcome, fmt.Sprintf("cf %s\n%s", strings.Join(args, " "), output)
+	}
+
+	DescribeTable("allows each role exactly the operations of its row in the matrix",
+		func(r role) {
+			user := users[r]
+			userContext := workflowhelpers.NewUserContext(Config.GetApiEndpoint(), user, nil, Config.GetSkipSSLValidation(), Config.DefaultTimeoutDuration())
+
+			expected := map[string]string{}
+			actual := map[string]string{}
+			var outputs []string
+			workflowhelpers.AsUser(userContext, Config.DefaultTimeoutDuration(), func() {
+				// Users with an org role cannot see the space, so the Cloud Controller forbids them
+				// every operation in it.
+				target := cf.Cf("target", "-o", orgName, "-s", spaceName).Wait()
+				if r.isOrgRole() {
+					Expect(target).NotTo(Exit(0))
+					Expect(string(target.Out.Contents()) + string(target.Err.Contents())).To(ContainSubstring("Space '%s' not found", spaceName))
+				} else {
+					Expect(target).To(Exit(0))
+				}
+
+				for _, op := range operations {
+					if op.enabled != nil && !op.enabled(Config) {
+						continue
+					}
+					expected[op.name] = forbiddenOutcome
+					if slices.Contains(op.allowed, r) {
+						expected[op.name] = allowedOutcome
+					}
+					if r.isOrgRole() {
+						actual[op.name] = forbiddenOutcome
+						continue
+					}
+					var output string
+					actual[op.name], output = run(op)
+					outputs = append(outputs, output)
+				}
+			})
+
+			Expect(actual).To(Equal(expected), "operations allowed for or forbidden to %s:\n%s", r, strings.Join(outputs, "\n"))
+		},
+		Entry("a space developer", spaceDeveloper),
+		Entry("a space supporter", spaceSupporter),
+		Entry("a space manager", spaceManager),
+		Entry("a space auditor", spaceAuditor),
+		Entry("an org auditor", orgAuditor),
+		Entry("an org billing manager", billingManager),
+	)
+}, Ordered)
@@ -3,7 +3,6 @@ package routing
 import (
 	"crypto/rand"
 	"fmt"
-	"path/filepath"
 	"regexp"
 	"slices"
 	"sync"
@@ -15,6 +14,7 @@ import (
 	. "github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/manifest"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -51,16 +51,31 @@ var _ = RoutingDescribe("Per-Route Options", func() {
 			leastConnHost = random_name.CATSRandomName("dora-lc")
 			roundRobinHost = random_name.CATSRandomName("dora-rr")
 			hashBasedRoutingHost = random_name.CATSRandomName("dora-hash")
+			route := func(host string, options manifest.RouteOptions) manifest.Route {
+				return manifest.Route{Route: fmt.Sprintf("%s.%s", host, Config.GetAppsDomain()), Options: &options}
+			}
+			dora := manifest.Application{
+				Name: appName,
+				Routes: []manifest.Route{
+					route(roundRobinHost, manifest.RouteOptions{LoadBalancing: "round-robin"}),
+					route(leastConnHost, manifest.RouteOptions{LoadBalancing: "least-connection"}),
+					route(hashBasedRoutingHost, manifest.RouteOptions{LoadBalancing: "hash", HashHeader: "X-Hash-Header"}),
+				},
+				Processes: []manifest.Process{{
+					Type:                    "web",
+					Instances:               manifest.Instances(2),
+					DiskQuota:               "1024M",
+					LogRateLimitPerSecond:   "16K",
+					HealthCheckType:         "http",
+					HealthCheckHTTPEndpoint: "/",
+				}},
+			}
 			Expect(cf.Cf("push",
 				appName,
 				"-b", Config.GetRubyBuildpackName(),
 				"-m", DEFAULT_MEMORY_LIMIT,
 				"-p", asset.Dora,
-				"--var", fmt.Sprintf("domain=%s", Config.GetAppsDomain()),
-				"--var", fmt.Sprintf("hashbasedroutinghost=%s", hashBasedRoutingHost),
-				"--var", fmt.Sprintf("leastconnhost=%s", leastConnHost),
-				"--var", fmt.Sprintf("roundrobinhost=%s", roundRobinHost),
-				"-f", filepath.Join(asset.Dora, "route_options_manifest.yml"),
+				"-f", app_helpers.WriteManifest(manifest.Manifest{Applications: []manifest.Application{dora}}),
 			).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
 			appId = app_helpers.GetAppGuid(appName)
 			for i := range 2 {
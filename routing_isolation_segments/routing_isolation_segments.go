@@ -46,12 +46,14 @@ var _ = RoutingIsolationSegmentsDescribe("RoutingIsolationSegments", func() {
 
 			createOrg := cf.Cf("create-org", orgName).Wait()
 			Expect(createOrg).To(Exit(0), "failed to create org")
+			LabelResourcesNamed("organizations", orgName)
 
 			setQuota := cf.Cf("set-quota", orgName, quotaName).Wait(TestSetup.ShortTimeout())
 			Expect(setQuota).To(Exit(0))
 
 			createSpace := cf.Cf("create-space", spaceName, "-o", orgName).Wait()
 			Expect(createSpace).To(Exit(0), "failed to create space")
+			LabelResourcesNamed("spaces", spaceName)
 
 			session := cf.Cf("curl", fmt.Sprintf("/v3/organizations?names=%s", orgName))
 			bytes := session.Wait().Out.Contents()
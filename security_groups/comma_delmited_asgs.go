@@ -11,7 +11,7 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gexec"
 
-	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
 	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
 )
@@ -30,12 +30,7 @@ var _ = CommaDelimitedSecurityGroupsDescribe("Comma Delimited ASGs", func() {
 		appName = random_name.CATSRandomName("APP")
 
 		By("pushing a proxy app")
-		Expect(cf.Cf(
-			"push", appName,
-			"-b", Config.GetGoBuildpackName(),
-			"-p", assets.NewAssets().Proxy,
-			"-f", assets.NewAssets().Proxy+"/manifest.yml",
-		).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+		Expect(cf.Cf(app_helpers.ProxyWithArgs(appName)...).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
 	})
 
 	AfterEach(func() {
@@ -13,7 +13,7 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gexec"
 
-	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
 	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
 )
@@ -32,12 +32,7 @@ var _ = SecurityGroupsDescribe("ASGs", func() {
 		appName = random_name.CATSRandomName("APP")
 
 		By("pushing a proxy app")
-		Expect(cf.Cf(
-			"push", appName,
-			"-b", Config.GetGoBuildpackName(),
-			"-p", assets.NewAssets().Proxy,
-			"-f", assets.NewAssets().Proxy+"/manifest.yml",
-		).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+		Expect(cf.Cf(app_helpers.ProxyWithArgs(appName)...).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
 	})
 
 	AfterEach(func() {
@@ -18,7 +18,6 @@ import (
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/logs"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
-	"github.com/cloudfoundry/cf-acceptance-tests/helpers/skip_messages"
 	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
 	"github.com/cloudfoundry/cf-test-helpers/v2/helpers"
 	"github.com/cloudfoundry/cf-test-helpers/v2/workflowhelpers"
@@ -188,15 +187,11 @@ var _ = SecurityGroupsDescribe("App Instance Networking", func() {
 	var serverAppName, privateHost string
 	var privatePort int
 
-	Describe("Using container-networking and running security-groups", func() {
+	Describe("Using container-networking and running security-groups", RequiresContainerNetworking, func() {
 		var serverAppName, clientAppName, privateHost, orgName, spaceName, securityGroupName string
 		var privatePort int
 
 		BeforeEach(func() {
-			if !Config.GetIncludeContainerNetworking() {
-				Skip(skip_messages.SkipContainerNetworkingMessage)
-			}
-
 			orgName = TestSetup.RegularUserContext().Org
 			spaceName = TestSetup.RegularUserContext().Space
 
@@ -43,13 +43,7 @@ var _ = ServiceDiscoveryDescribe("Service Discovery", func() {
 		Expect(cf.Cf("map-route", appNameBackend, defaultInternalDomain, "--hostname", internalHostName).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
 
 		// push frontend app
-		Expect(cf.Cf(
-			"push", appNameFrontend,
-			"-b", Config.GetGoBuildpackName(),
-			"-m", DEFAULT_MEMORY_LIMIT,
-			"-p", assets.NewAssets().Proxy,
-			"-f", assets.NewAssets().Proxy+"/manifest.yml",
-		).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+		Expect(cf.Cf(app_helpers.ProxyWithArgs(appNameFrontend, "-m", DEFAULT_MEMORY_LIMIT)...).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
 	})
 
 	AfterEach(func() {
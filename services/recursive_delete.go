@@ -43,12 +43,14 @@ var _ = ServicesDescribe("Recursive Delete", func() {
 
 			createOrg := cf.Cf("create-org", orgName).Wait()
 			Expect(createOrg).To(Exit(0), "failed to create org")
+			LabelResourcesNamed("organizations", orgName)
 
 			setQuota := cf.Cf("set-quota", orgName, quotaName).Wait(TestSetup.ShortTimeout())
 			Expect(setQuota).To(Exit(0))
 
 			createSpace := cf.Cf("create-space", spaceName, "-o", orgName).Wait()
 			Expect(createSpace).To(Exit(0), "failed to create space")
+			LabelResourcesNamed("spaces", spaceName)
 
 			target := cf.Cf("target", "-o", orgName, "-s", spaceName).Wait()
 			Expect(target).To(Exit(0), "failed targeting")
@@ -61,6 +63,7 @@ var _ = ServicesDescribe("Recursive Delete", func() {
 
 			createService := cf.Cf("create-service", broker.Service.Name, broker.SyncPlans[0].Name, instanceName).Wait()
 			Expect(createService).To(Exit(0), "failed creating service")
+			LabelResourcesNamed("service_instances", instanceName)
 		})
 	})
 
@@ -146,8 +146,9 @@ var _ = ServicesDescribe("Service Broker Lifecycle", func() {
 						otherOrgName = random_name.CATSRandomName("ORG")
 						createOrg := cf.Cf("create-org", otherOrgName).Wait()
 						Expect(createOrg).To(Exit(0), "failed to create org")
+						LabelResourcesNamed("organizations", otherOrgName)
 
-						addOrgManager := cf.Cf("set-org-role", TestSetup.RegularUserContext().Username, otherOrgName, "OrgManager").Wait()
+						addOrgManager := cf.Cf(RegularUserRoleArgs("set-org-role", otherOrgName, "OrgManager")...).Wait()
 						Expect(addOrgManager).To(Exit(0), "failed to add org manager role")
 
 						commandResult := cf.Cf("enable-service-access", broker.Service.Name, "-p", globallyPublicPlan.Name).Wait()
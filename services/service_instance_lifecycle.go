@@ -16,6 +16,7 @@ import (
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/services"
 	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/services"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/v3_helpers"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -27,12 +28,8 @@ var _ = ServicesDescribe("Service Instance Lifecycle", func() {
 	const asyncOperationPollInterval = 5 * time.Second
 	var broker services.ServiceBroker
 
-	waitForAsyncDeletionToComplete := func(broker services.ServiceBroker, instanceName string) {
-		Eventually(func() *Buffer {
-			session := cf.Cf("service", instanceName).Wait()
-			combinedOutputBytes := append(session.Out.Contents(), session.Err.Contents()...)
-			return BufferWithBytes(combinedOutputBytes)
-		}, Config.AsyncServiceOperationTimeoutDuration(), asyncOperationPollInterval).Should(Say("not found"))
+	waitForAsyncOperationToComplete := func(instanceName string) {
+		v3_helpers.WaitFor(v3_helpers.CCClient().ServiceInstanceOperation(instanceName), Config.AsyncServiceOperationTimeoutDuration())
 	}
 
 	waitForAsyncOperationToCompleteAndSay := func(broker services.ServiceBroker, instanceName, expectedText string) {
@@ -317,7 +314,7 @@ var _ = ServicesDescribe("Service Instance Lifecycle", func() {
 			app_helpers.AppReport(broker.Name)
 
 			Expect(cf.Cf("delete-service", instanceName, "-f").Wait()).To(Exit())
-			waitForAsyncDeletionToComplete(broker, instanceName)
+			waitForAsyncOperationToComplete(instanceName)
 
 			broker.Destroy()
 		})
@@ -332,7 +329,7 @@ var _ = ServicesDescribe("Service Instance Lifecycle", func() {
 				Expect(createService).To(Exit(0))
 				Expect(createService).To(Say("Create in progress."))
 
-				waitForAsyncOperationToCompleteAndSay(broker, instanceName, "succeeded")
+				waitForAsyncOperationToComplete(instanceName)
 
 				serviceInfo := cf.Cf("-v", "service", instanceName).Wait()
 				Expect(serviceInfo).To(Say("[P|p]lan:\\s+%s", broker.AsyncPlans[0].Name))
@@ -351,7 +348,7 @@ var _ = ServicesDescribe("Service Instance Lifecycle", func() {
 					Expect(createService).To(Exit(0))
 					Expect(createService).To(Say("Create in progress."))
 
-					waitForAsyncOperationToCompleteAndSay(broker, instanceName, "succeeded")
+					waitForAsyncOperationToComplete(instanceName)
 				})
 
 				It("can update a service plan", func() {
@@ -363,7 +360,7 @@ var _ = ServicesDescribe("Service Instance Lifecycle", func() {
 					Expect(serviceInfo).To(Exit(0), "failed getting service instance details")
 					Expect(serviceInfo).To(Say("[P|p]lan:\\s+%s", broker.AsyncPlans[0].Name))
 
-					waitForAsyncOperationToCompleteAndSay(broker, instanceName, "succeeded")
+					waitForAsyncOperationToComplete(instanceName)
 
 					serviceInfo = cf.Cf("service", instanceName).Wait()
 					Expect(serviceInfo).To(Exit(0), "failed getting service instance details")
@@ -375,7 +372,7 @@ var _ = ServicesDescribe("Service Instance Lifecycle", func() {
 					Expect(updateService).To(Exit(0))
 					Expect(updateService).To(Say("Update in progress."))
 
-					waitForAsyncOperationToCompleteAndSay(broker, instanceName, "succeeded")
+					waitForAsyncOperationToComplete(instanceName)
 				})
 
 				It("can update all of the possible parameters at once", func() {
@@ -387,7 +384,7 @@ var _ = ServicesDescribe("Service Instance Lifecycle", func() {
 					Expect(updateService).To(Exit(0))
 					Expect(updateService).To(Say("Update in progress."))
 
-					waitForAsyncOperationToCompleteAndSay(broker, instanceName, "succeeded")
+					waitForAsyncOperationToComplete(instanceName)
 
 					serviceInfo := cf.Cf("-v", "service", instanceName).Wait()
 					Expect(serviceInfo).To(Exit(0), "failed getting service instance details")
@@ -400,7 +397,7 @@ var _ = ServicesDescribe("Service Instance Lifecycle", func() {
 					Expect(deleteService).To(Exit(0), "failed making delete request")
 					Expect(deleteService).To(Say("Delete in progress."))
 
-					waitForAsyncDeletionToComplete(broker, instanceName)
+					waitForAsyncOperationToComplete(instanceName)
 				})
 
 				Context("when there is an app", func() {
@@ -471,7 +468,7 @@ var _ = ServicesDescribe("Service Instance Lifecycle", func() {
 				Expect(createService).To(Exit(0))
 				Expect(createService).To(Say("Create in progress."))
 
-				waitForAsyncOperationToCompleteAndSay(broker, instanceName, "succeeded")
+				waitForAsyncOperationToComplete(instanceName)
 
 				appName = random_name.CATSRandomName("APP")
 				Expect(cf.Cf(app_helpers.CatnipWithArgs(
@@ -51,6 +51,7 @@ var _ = ServiceInstanceSharingDescribe("Service Instance Sharing", func() {
 				userASpaceName = random_name.CATSRandomName("SPACE")
 				createSpace := cf.Cf("create-space", userASpaceName, "-o", orgName).Wait()
 				Expect(createSpace).To(Exit(0), "failed to create space")
+				LabelResourcesNamed("spaces", userASpaceName)
 
 				target = cf.Cf("target", "-s", userASpaceName).Wait()
 				Expect(target).To(Exit(0), "failed targeting")
@@ -60,6 +61,7 @@ var _ = ServiceInstanceSharingDescribe("Service Instance Sharing", func() {
 				By("Creating a service instance in User A's space")
 				createService := cf.Cf("create-service", broker.Service.Name, broker.SyncPlans[0].Name, serviceInstanceName).Wait()
 				Expect(createService).To(Exit(0))
+				LabelResourcesNamed("service_instances", serviceInstanceName)
 
 				By("Sharing the service instance into User B's space")
 				userBSpaceName := TestSetup.RegularUserContext().TestSpace.SpaceName()
@@ -0,0 +1,122 @@
+package sidecars
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/manifest"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/v3_helpers"
+	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
+	"github.com/cloudfoundry/cf-test-helpers/v2/helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+)
+
+const sidecarPort = 8081
+
+var _ = SidecarsDescribe("sidecars", func() {
+	var appName string
+
+	BeforeEach(func() {
+		appName = random_name.CATSRandomName("APP")
+	})
+
+	AfterEach(func() {
+		app_helpers.AppReport(appName)
+		Expect(cf.Cf("delete", appName, "-f", "-r").Wait()).To(Exit(0))
+	})
+
+	Context("with a sidecar serving HTTP for the web process", func() {
+		var appGuid string
+
+		BeforeEach(func() {
+			manifestFile := createManifest(appName, fmt.Sprintf("PORT=%d ./catnip", sidecarPort))
+			Expect(cf.Cf("push", appName, "-f", manifestFile).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+			appGuid = app_helpers.GetAppGuid(appName)
+		})
+
+		It("runs the sidecar in the network namespace of the app", func() {
+			var curlResponse struct {
+				Stdout     string `json:"stdout"`
+				ReturnCode int    `json:"return_code"`
+			}
+			Eventually(func(g Gomega) {
+				response := helpers.CurlApp(Config, appName, fmt.Sprintf("/curl/localhost/%d", sidecarPort))
+				g.Expect(json.Unmarshal([]byte(response), &curlResponse)).To(Succeed())
+				g.Expect(curlResponse.ReturnCode).To(Equal(0))
+				g.Expect(curlResponse.Stdout).To(ContainSubstring("Catnip?"))
+			}).Should(Succeed())
+		})
+
+		It("only adds the sidecar to the declared process types", func() {
+			sidecars := v3_helpers.GetAppSidecars(appGuid)
+			Expect(sidecars).To(ConsistOf(SatisfyAll(
+				HaveField("Name", "catnip-sidecar"),
+				HaveField("ProcessTypes", ConsistOf("web")),
+				HaveField("Origin", "user"),
+			)))
+
+			processes := v3_helpers.GetProcesses(appGuid, appName)
+			web := v3_helpers.GetProcessByType(processes, "web")
+			worker := v3_helpers.GetProcessByType(processes, "worker")
+			Expect(worker.Guid).NotTo(BeEmpty())
+			Expect(v3_helpers.GetProcessSidecars(web.Guid)).To(ConsistOf(HaveField("GUID", sidecars[0].GUID)))
+			Expect(v3_helpers.GetProcessSidecars(worker.Guid)).To(BeEmpty())
+		})
+
+		It("counts the memory of the sidecar against the process", func() {
+			Expect(v3_helpers.GetAppSidecars(appGuid)).To(ConsistOf(HaveField("MemoryInMB", 64)))
+
+			scale := cf.Cf("scale", appName, "-m", "64M", "-f").Wait(Config.CfPushTimeoutDuration())
+			Expect(scale).To(Exit(1))
+			Expect(scale.Err).To(Say("sidecar"))
+
+			Expect(cf.Cf("scale", appName, "-m", "128M", "-f").Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+		})
+	})
+
+	Context("with a sidecar that exits", func() {
+		It("reports the crash of the sidecar in the app events", func() {
+			manifestFile := createManifest(appName, "echo cats-sidecar-exiting; sleep 5; exit 1")
+			Expect(cf.Cf("push", appName, "-f", manifestFile).Wait(Config.CfPushTimeoutDuration())).To(Exit())
+
+			Eventually(func() string {
+				return string(cf.Cf("logs", appName, "--recent").Wait().Out.Contents())
+			}).Should(ContainSubstring("cats-sidecar-exiting"))
+
+			Eventually(func() string {
+				return string(cf.Cf("events", appName).Wait().Out.Contents())
+			}).Should(MatchRegexp("app.crash"))
+		})
+	})
+})
+
+// createManifest writes a manifest for catnip with a web and a stopped worker process, and a
+// sidecar running sidecarCommand for the web process only.
+func createManifest(appName, sidecarCommand string) string {
+	appPath, err := filepath.Abs(assets.NewAssets().Catnip)
+	Expect(err).ToNot(HaveOccurred())
+
+	return app_helpers.WriteManifest(manifest.Manifest{Applications: []manifest.Application{{
+		Name:       appName,
+		Path:       appPath,
+		Buildpacks: []string{Config.GetBinaryBuildpackName()},
+		Processes: []manifest.Process{
+			{Type: "web", Command: "./catnip", Memory: "256M"},
+			{Type: "worker", Command: "./catnip", Instances: manifest.Instances(0), HealthCheckType: "process"},
+		},
+		Sidecars: []manifest.Sidecar{{
+			Name:         "catnip-sidecar",
+			ProcessTypes: []string{"web"},
+			Command:      sidecarCommand,
+			Memory:       "64M",
+		}},
+	}}})
+}
@@ -4,9 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"os"
 	"os/exec"
-	"path/filepath"
 	"strings"
 
 	. "github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
@@ -16,6 +14,7 @@ import (
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/logs"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/manifest"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
 	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
 	"github.com/cloudfoundry/cf-test-helpers/v2/helpers"
@@ -263,20 +262,10 @@ func sshProxyAddress() string {
 }
 
 func createManifestSshDisabled(appName string) string {
-	tmpdir, err := os.MkdirTemp(os.TempDir(), appName)
-	Expect(err).ToNot(HaveOccurred())
-
-	manifestFile := filepath.Join(tmpdir, "manifest.yml")
-	manifestContent := fmt.Sprintf(`---
-applications:
-- name: %s
-  features:
-    ssh: false
-`, appName)
-	err = os.WriteFile(manifestFile, []byte(manifestContent), 0644)
-	Expect(err).ToNot(HaveOccurred())
-
-	return manifestFile
+	return app_helpers.WriteManifest(manifest.Manifest{Applications: []manifest.Application{{
+		Name:     appName,
+		Features: map[string]bool{"ssh": false},
+	}}})
 }
 
 func expectSshCmdToFail(appName string) {
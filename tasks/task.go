@@ -12,7 +12,6 @@ import (
 	. "github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
 
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
-	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/skip_messages"
 	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
@@ -232,13 +231,7 @@ var _ = TasksDescribe("v3 tasks", func() {
 		var securityGroupName string
 
 		BeforeEach(func() {
-			Expect(cf.Cf(
-				"push", appName,
-				"-b", Config.GetGoBuildpackName(),
-				"-m", DEFAULT_MEMORY_LIMIT,
-				"-p", assets.NewAssets().Proxy,
-				"-f", assets.NewAssets().Proxy+"/manifest.yml",
-			).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+			Expect(cf.Cf(app_helpers.ProxyWithArgs(appName, "-m", DEFAULT_MEMORY_LIMIT)...).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
 			appGuid = app_helpers.GetAppGuid(appName)
 		})
 
@@ -248,13 +241,7 @@ var _ = TasksDescribe("v3 tasks", func() {
 			Expect(cf.Cf("delete", appName, "-f", "-r").Wait()).To(Exit(0))
 		})
 
-		Context("and applying a network policy", func() {
-			BeforeEach(func() {
-				if !Config.GetIncludeContainerNetworking() {
-					Skip(skip_messages.SkipContainerNetworkingMessage)
-				}
-			})
-
+		Context("and applying a network policy", RequiresContainerNetworking, func() {
 			It("applies the associated app's policies to the task", func() {
 				By("creating the network policy")
 				workflowhelpers.AsUser(TestSetup.AdminUserContext(), Config.DefaultTimeoutDuration(), func() {
@@ -296,13 +283,7 @@ exit 1`
 			})
 		})
 
-		Context("and binding a space-specific ASG", func() {
-			BeforeEach(func() {
-				if !Config.GetIncludeSecurityGroups() {
-					Skip(skip_messages.SkipSecurityGroupsMessage)
-				}
-			})
-
+		Context("and binding a space-specific ASG", RequiresSecurityGroups, func() {
 			AfterEach(func() {
 				workflowhelpers.AsUser(TestSetup.AdminUserContext(), Config.DefaultTimeoutDuration(), func() {
 					Expect(cf.Cf("unbind-security-group", securityGroupName, TestSetup.RegularUserContext().Org, TestSetup.RegularUserContext().Space).Wait()).To(Exit(0))
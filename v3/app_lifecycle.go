@@ -8,7 +8,6 @@ import (
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
-	"github.com/cloudfoundry/cf-acceptance-tests/helpers/skip_messages"
 	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/v3_helpers"
 	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
 	"github.com/cloudfoundry/cf-test-helpers/v2/helpers"
@@ -231,9 +230,6 @@ var _ = V3Describe("v3 docker app lifecycle", func() {
 	)
 
 	BeforeEach(func() {
-		if !Config.GetIncludeDocker() {
-			Skip(skip_messages.SkipDockerMessage)
-		}
 		appName = random_name.CATSRandomName("APP")
 		spaceGuid = GetSpaceGuidFromName(TestSetup.RegularUserContext().Space)
 		appCreationEnvironmentVariables = `"foo":"bar"`
@@ -303,4 +299,4 @@ var _ = V3Describe("v3 docker app lifecycle", func() {
 			return helpers.CurlAppRoot(Config, webProcess.Name)
 		}).Should(ContainSubstring(expectedNullResponse))
 	})
-})
+}, RequiresDocker)
@@ -14,7 +14,6 @@ import (
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/logs"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
-	"github.com/cloudfoundry/cf-acceptance-tests/helpers/skip_messages"
 	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/v3_helpers"
 	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
 	"github.com/cloudfoundry/cf-test-helpers/v2/helpers"
@@ -78,10 +77,7 @@ var _ = V3Describe("buildpack", func() {
 			}).Should(ContainSubstring("STAGED WITH CUSTOM BUILDPACK"))
 		})
 
-		It("Downloads the correct user specified git buildpack", func() {
-			if !Config.GetIncludeInternetDependent() {
-				Skip(skip_messages.SkipInternetDependentMessage)
-			}
+		It("Downloads the correct user specified git buildpack", RequiresInternetDependent, func() {
 			StageBuildpackPackage(packageGuid, "https://github.com/cloudfoundry/example-git-buildpack")
 
 			Eventually(func() string {
@@ -6,11 +6,11 @@ import (
 	"time"
 
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
-	"github.com/cloudfoundry/cf-acceptance-tests/helpers/skip_messages"
 
 	. "github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/v3_helpers"
 	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
 	"github.com/cloudfoundry/cf-test-helpers/v2/helpers"
 	. "github.com/onsi/ginkgo/v2"
@@ -30,9 +30,6 @@ var _ = V3Describe("deployment", func() {
 	const numberOfAppCurlChecks = 10
 
 	BeforeEach(func() {
-		if !Config.GetIncludeDeployments() {
-			Skip(skip_messages.SkipDeploymentsMessage)
-		}
 		appName = random_name.CATSRandomName("APP")
 		Expect(cf.Cf("push", appName, "-i", "3", "-b", Config.GetRubyBuildpackName(), "-p", assets.NewAssets().DoraZip).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
 
@@ -51,6 +48,16 @@ var _ = V3Describe("deployment", func() {
 		Expect(cf.Cf("delete", appName, "-f").Wait(Config.DefaultTimeoutDuration())).To(Exit(0))
 	})
 
+	pushNoWait := func(args ...string) string {
+		pushArgs := append([]string{"push", appName, "--no-wait", "-b", Config.GetStaticFileBuildpackName(), "-p", assets.NewAssets().Staticfile}, args...)
+		Expect(cf.Cf(pushArgs...).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+		return v3_helpers.GetLatestDeploymentGuid(app_helpers.GetAppGuid(appName))
+	}
+
+	expectCurrentDroplet := func(dropletGuid string) {
+		Expect(v3_helpers.GetCurrentDropletGuidFromApp(app_helpers.GetAppGuid(appName))).To(Equal(dropletGuid))
+	}
+
 	Describe("Rolling Deployments", func() {
 		BeforeEach(func() {
 			stopCheckingAppAlive, appCheckerIsDone = checkAppRemainsAlive(appName)
@@ -63,45 +70,28 @@ var _ = V3Describe("deployment", func() {
 
 		It("deploys an app with no downtime", func() {
 			By("Pushing a rolling deployment")
-			Expect(cf.Cf("push", appName, "--strategy", "rolling", "--no-wait", "-b", Config.GetStaticFileBuildpackName(), "-p", assets.NewAssets().Staticfile).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
-
-			Eventually(func(g Gomega) {
-				session := cf.Cf("app", appName).Wait()
-				g.Expect(session).Should(Say("Active deployment with status DEPLOYING"))
-				g.Expect(session).Should(Say(`strategy:\s+rolling`))
-				g.Expect(session).Should(Exit(0))
-			}).Should(Succeed())
+			deploymentGuid := pushNoWait("--strategy", "rolling")
+			Expect(v3_helpers.GetDeployment(deploymentGuid).Strategy).To(Equal("rolling"))
 
 			By("Verifying the new rolled out process")
-			Eventually(func(g Gomega) {
-				session := cf.Cf("app", appName).Wait()
-				g.Expect(session).ShouldNot(Say("Active deployment"))
-				g.Expect(session).Should(Exit(0))
-			}).Should(Succeed())
+			deployment := v3_helpers.WaitForDeploymentToComplete(deploymentGuid)
+			expectCurrentDroplet(deployment.Droplet.GUID)
 
 			checkAppCurlResponse(appName, "Hello from a staticfile", numberOfAppCurlChecks)
 		})
 
 		It("can be cancelled and rolls back to the previous app", func() {
 			By("Pushing a rolling deployment")
-			Expect(cf.Cf("push", appName, "--strategy", "rolling", "--no-wait", "-b", Config.GetStaticFileBuildpackName(), "-p", assets.NewAssets().Staticfile).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
-
-			Eventually(func(g Gomega) {
-				session := cf.Cf("app", appName).Wait()
-				g.Expect(session).Should(Say("Active deployment with status DEPLOYING"))
-				g.Expect(session).Should(Say(`strategy:\s+rolling`))
-				g.Expect(session).Should(Exit(0))
-			}).Should(Succeed())
+			deploymentGuid := pushNoWait("--strategy", "rolling")
+			deployment := v3_helpers.WaitForDeploymentStatus(deploymentGuid, "ACTIVE", "DEPLOYING")
+			Expect(deployment.Strategy).To(Equal("rolling"))
 
 			By("Cancelling the deployment")
-			Expect(cf.Cf("cancel-deployment", appName).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+			v3_helpers.CancelDeployment(deploymentGuid)
 
 			By("Verifying the cancel succeeded and we rolled back to old process")
-			Eventually(func(g Gomega) {
-				session := cf.Cf("app", appName).Wait()
-				g.Expect(session).ShouldNot(Say("Active deployment"))
-				g.Expect(session).Should(Exit(0))
-			}).Should(Succeed())
+			deployment = v3_helpers.WaitForDeploymentStatus(deploymentGuid, "FINALIZED", "CANCELED")
+			expectCurrentDroplet(deployment.PreviousDroplet.GUID)
 
 			checkAppCurlResponse(appName, "Hi, I'm Dora", numberOfAppCurlChecks)
 		})
@@ -109,20 +99,14 @@ var _ = V3Describe("deployment", func() {
 		Context("max-in-flight", func() {
 			It("deploys an app with max_in_flight with a rolling deployment", func() {
 				By("Pushing a new rolling deployment with max in flight of 2")
-				Expect(cf.Cf("push", appName, "--strategy", "rolling", "--max-in-flight", "2", "--no-wait", "-b", Config.GetStaticFileBuildpackName(), "-p", assets.NewAssets().Staticfile).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
-
-				Eventually(func(g Gomega) {
-					session := cf.Cf("app", appName).Wait()
-					g.Expect(session).Should(Say("Active deployment with status DEPLOYING"))
-					g.Expect(session).Should(Say(`strategy:\s+rolling`))
-					g.Expect(session).Should(Say(`max-in-flight:\s+2`))
-				}).Should(Succeed())
+				deploymentGuid := pushNoWait("--strategy", "rolling", "--max-in-flight", "2")
+				deployment := v3_helpers.GetDeployment(deploymentGuid)
+				Expect(deployment.Strategy).To(Equal("rolling"))
+				Expect(deployment.Options.MaxInFlight).To(Equal(2))
 
 				By("Verifying the new app has rolled out to all instances")
-				Eventually(func(g Gomega) {
-					session := cf.Cf("app", appName).Wait()
-					g.Expect(session).ShouldNot(Say("Active deployment"))
-				}).Should(Succeed())
+				deployment = v3_helpers.WaitForDeploymentToComplete(deploymentGuid)
+				expectCurrentDroplet(deployment.Droplet.GUID)
 
 				checkAppCurlResponse(appName, "Hello from a staticfile", numberOfAppCurlChecks)
 			})
@@ -139,17 +123,7 @@ var _ = V3Describe("deployment", func() {
 			<-appCheckerIsDone
 		})
 
-		It("deploys an app, transitions to pause, is continued and then deploys successfully", func() {
-			By("Pushing a canary deployment")
-			Expect(cf.Cf("push", appName, "--strategy", "canary", "--no-wait", "-b", Config.GetStaticFileBuildpackName(), "-p", assets.NewAssets().Staticfile).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
-
-			Eventually(func(g Gomega) {
-				session := cf.Cf("app", appName).Wait()
-				g.Expect(session).Should(Say("Active deployment with status PAUSED"))
-				g.Expect(session).Should(Say("strategy:        canary"))
-			}).Should(Succeed())
-
-			By("Checking that both the canary and original apps exist simultaneously")
+		checkCanaryAndOriginalAppsExist := func() {
 			Eventually(func() string {
 				return helpers.CurlAppRoot(Config, appName)
 			}).Should(ContainSubstring("Hello from a staticfile"))
@@ -157,79 +131,62 @@ var _ = V3Describe("deployment", func() {
 			Eventually(func() string {
 				return helpers.CurlAppRoot(Config, appName)
 			}).Should(ContainSubstring("Hi, I'm Dora"))
+		}
 
-			By("Continuing the deployment")
-			Expect(cf.Cf("continue-deployment", appName).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+		It("deploys an app, transitions to pause, is continued and then deploys successfully", func() {
+			By("Pushing a canary deployment")
+			deploymentGuid := pushNoWait("--strategy", "canary")
+			deployment := v3_helpers.WaitForCanaryStep(deploymentGuid, 1)
+			Expect(deployment.Strategy).To(Equal("canary"))
 
-			Eventually(func(g Gomega) {
-				session := cf.Cf("app", appName).Wait()
-				g.Expect(session).ShouldNot(Say("Active deployment"))
-			}).Should(Succeed())
+			By("Checking that both the canary and original apps exist simultaneously")
+			checkCanaryAndOriginalAppsExist()
+
+			By("Continuing the deployment")
+			v3_helpers.ContinueDeployment(deploymentGuid)
 
 			By("Verifying the continue succeeded and we rolled out the new process")
+			deployment = v3_helpers.WaitForDeploymentToComplete(deploymentGuid)
+			expectCurrentDroplet(deployment.Droplet.GUID)
 
 			checkAppCurlResponse(appName, "Hello from a staticfile", numberOfAppCurlChecks)
 		})
 
 		It("can be cancelled when paused", func() {
 			By("Pushing a canary deployment")
-			Expect(cf.Cf("push", appName, "--strategy", "canary", "--no-wait", "-b", Config.GetStaticFileBuildpackName(), "-p", assets.NewAssets().Staticfile).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
-
-			Eventually(func(g Gomega) {
-				session := cf.Cf("app", appName).Wait()
-				g.Expect(session).Should(Say("Active deployment with status PAUSED"))
-				g.Expect(session).Should(Say("strategy:        canary"))
-			}).Should(Succeed())
+			deploymentGuid := pushNoWait("--strategy", "canary")
+			deployment := v3_helpers.WaitForCanaryStep(deploymentGuid, 1)
+			Expect(deployment.Strategy).To(Equal("canary"))
 
 			By("Checking that both the canary and original apps exist simultaneously")
-			Eventually(func() string {
-				return helpers.CurlAppRoot(Config, appName)
-			}).Should(ContainSubstring("Hello from a staticfile"))
-
-			Eventually(func() string {
-				return helpers.CurlAppRoot(Config, appName)
-			}).Should(ContainSubstring("Hi, I'm Dora"))
+			checkCanaryAndOriginalAppsExist()
 
 			By("Cancelling the deployment")
-			Expect(cf.Cf("cancel-deployment", appName).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
-
-			Eventually(func(g Gomega) {
-				session := cf.Cf("app", appName).Wait()
-				g.Expect(session).ShouldNot(Say("Active deployment"))
-			}).Should(Succeed())
+			v3_helpers.CancelDeployment(deploymentGuid)
 
 			By("Verifying the cancel succeeded and we rolled back to old process")
+			deployment = v3_helpers.WaitForDeploymentStatus(deploymentGuid, "FINALIZED", "CANCELED")
+			expectCurrentDroplet(deployment.PreviousDroplet.GUID)
+
 			checkAppCurlResponse(appName, "Hi, I'm Dora", numberOfAppCurlChecks)
 		})
 
 		Context("max-in-flight", func() {
 			It("deploys an app with max_in_flight after a canary deployment has been continued", func() {
 				By("Pushing a new canary deployment with max in flight of 2")
-				Expect(cf.Cf("push", appName, "--strategy", "canary", "--max-in-flight", "2", "--no-wait", "-b", Config.GetStaticFileBuildpackName(), "-p", assets.NewAssets().Staticfile).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+				deploymentGuid := pushNoWait("--strategy", "canary", "--max-in-flight", "2")
 
 				By("Waiting for the a canary deployment to be paused")
-				Eventually(func(g Gomega) {
-					session := cf.Cf("app", appName).Wait()
-					g.Expect(session).Should(Say("Active deployment with status PAUSED"))
-					g.Expect(session).Should(Say(`strategy:\s+canary`))
-				}).Should(Succeed())
+				deployment := v3_helpers.WaitForCanaryStep(deploymentGuid, 1)
+				Expect(deployment.Strategy).To(Equal("canary"))
+				Expect(deployment.Options.MaxInFlight).To(Equal(2))
 
 				By("Continuing the deployment")
-				Expect(cf.Cf("continue-deployment", appName, "--no-wait").Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
-
-				Eventually(func(g Gomega) {
-					session := cf.Cf("app", appName).Wait()
-					g.Expect(session).Should(Say("Active deployment with status DEPLOYING"))
-					g.Expect(session).Should(Say(`strategy:\s+canary`))
-					g.Expect(session).Should(Say(`max-in-flight:\s+2`))
-				}).Should(Succeed())
-
-				Eventually(func(g Gomega) {
-					session := cf.Cf("app", appName).Wait()
-					g.Expect(session).ShouldNot(Say("Active deployment"))
-				}).Should(Succeed())
+				v3_helpers.ContinueDeployment(deploymentGuid)
 
 				By("Verifying the new app has rolled out to all instances")
+				deployment = v3_helpers.WaitForDeploymentToComplete(deploymentGuid)
+				expectCurrentDroplet(deployment.Droplet.GUID)
 
 				checkAppCurlResponse(appName, "Hello from a staticfile", numberOfAppCurlChecks)
 			})
@@ -242,41 +199,30 @@ var _ = V3Describe("deployment", func() {
 
 			It("deploys an app, transitions to pause and can be continued multiple times and then deploys successfully", func() {
 				By("Pushing a canary deployment")
-				Expect(cf.Cf("push", appName, "--strategy", "canary", "--instance-steps=25,50,75", "--no-wait", "-b", Config.GetStaticFileBuildpackName(), "-p", assets.NewAssets().Staticfile).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+				deploymentGuid := pushNoWait("--strategy", "canary", "--instance-steps=25,50,75")
+				Expect(v3_helpers.GetDeployment(deploymentGuid).InstanceWeights()).To(Equal([]int{25, 50, 75}))
 
 				for i := 1; i <= 3; i++ {
 					By(fmt.Sprintf("Waiting for the a canary deployment to be paused on step %d", i))
-					Eventually(func(g Gomega) {
-						session := cf.Cf("app", appName).Wait()
-						g.Expect(session).Should(Say("Active deployment with status PAUSED"))
-						g.Expect(session).Should(Say("strategy:\\s+canary"))
-						g.Expect(session).Should(Say(fmt.Sprintf("canary-steps:\\s+%d/%d", i, 3)))
-					}).Should(Succeed())
+					deployment := v3_helpers.WaitForCanaryStep(deploymentGuid, i)
+					Expect(deployment.CanaryStep().Total).To(Equal(3))
 
 					By(fmt.Sprintf("Checking that both the canary and original apps exist simultaneously on step %d", i))
-					Eventually(func() string {
-						return helpers.CurlAppRoot(Config, appName)
-					}).Should(ContainSubstring("Hello from a staticfile"))
-
-					Eventually(func() string {
-						return helpers.CurlAppRoot(Config, appName)
-					}).Should(ContainSubstring("Hi, I'm Dora"))
+					checkCanaryAndOriginalAppsExist()
 
 					By(fmt.Sprintf("Continuing the deployment on step %d", i))
-					Expect(cf.Cf("continue-deployment", appName).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+					v3_helpers.ContinueDeployment(deploymentGuid)
 				}
 
-				Eventually(func(g Gomega) {
-					session := cf.Cf("app", appName).Wait()
-					g.Expect(session).ShouldNot(Say("Active deployment"))
-				}).Should(Succeed())
-
 				By("Verifying the continue succeeded and we rolled out the new process")
+				deployment := v3_helpers.WaitForDeploymentToComplete(deploymentGuid)
+				expectCurrentDroplet(deployment.Droplet.GUID)
+
 				checkAppCurlResponse(appName, "Hello from a staticfile", numberOfAppCurlChecks)
 			})
 		})
 	})
-})
+}, RequiresDeployments)
 
 func checkAppRemainsAlive(appName string) (chan<- bool, <-chan bool) {
 	doneChannel := make(chan bool, 1)
@@ -0,0 +1,196 @@
+package v3
+
+import (
+	"fmt"
+	"path/filepath"
+
+	. "github.com/cloudfoundry/cf-acceptance-tests/cats_suite_helpers"
+
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/manifest"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
+	. "github.com/cloudfoundry/cf-acceptance-tests/helpers/v3_helpers"
+	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
+	"github.com/cloudfoundry/cf-test-helpers/v2/helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = V3Describe("space manifests", func() {
+	var (
+		spaceGuid     string
+		webAppName    string
+		workerAppName string
+		serviceName   string
+
+		// pushed is the manifest the apps are pushed with, and changed the manifest that is diffed
+		// against and applied to them.
+		pushed  manifest.Manifest
+		changed manifest.Manifest
+	)
+
+	route := func(host string) manifest.Route {
+		return manifest.Route{Route: fmt.Sprintf("%s.%s", host, Config.GetAppsDomain())}
+	}
+
+	sleepCommand := "while true; do sleep 60; done"
+
+	// operation matches a diff operation with the op at a path matching pathPattern.
+	operation := func(op, pathPattern string) OmegaMatcher {
+		return SatisfyAll(HaveField("Op", op), HaveField("Path", MatchRegexp(pathPattern)))
+	}
+
+	BeforeEach(func() {
+		spaceGuid = GetSpaceGuidFromName(TestSetup.RegularUserContext().Space)
+		webAppName = random_name.CATSRandomName("APP")
+		workerAppName = random_name.CATSRandomName("APP")
+		serviceName = random_name.CATSRandomName("SVIN")
+		Expect(cf.Cf("create-user-provided-service", serviceName).Wait()).To(Exit(0))
+
+		catnipPath, err := filepath.Abs(assets.NewAssets().Catnip)
+		Expect(err).NotTo(HaveOccurred())
+
+		catnip := func(name string) manifest.Application {
+			return manifest.Application{
+				Name:       name,
+				Path:       catnipPath,
+				Buildpacks: []string{Config.GetBinaryBuildpackName()},
+				Processes:  []manifest.Process{{Type: "web", Command: "./catnip", Memory: DEFAULT_MEMORY_LIMIT}},
+			}
+		}
+
+		web := catnip(webAppName)
+		web.Env = map[string]string{"KEPT": "kept", "REMOVED": "removed"}
+		web.Routes = []manifest.Route{route(webAppName), route(webAppName + "-extra")}
+
+		worker := catnip(workerAppName)
+		worker.Routes = []manifest.Route{route(workerAppName)}
+		worker.Processes = append(worker.Processes, manifest.Process{Type: "worker", Command: sleepCommand, Instances: manifest.Instances(0), HealthCheckType: "process"})
+
+		pushed = manifest.Manifest{Applications: []manifest.Application{web, worker}}
+
+		changedWeb := catnip(webAppName)
+		changedWeb.Env = map[string]string{"KEPT": "kept", "ADDED": "added"}
+		changedWeb.Routes = []manifest.Route{route(webAppName)}
+		changedWeb.Processes[0].Memory = "512M"
+		changedWeb.Sidecars = []manifest.Sidecar{{Name: "sleeper", ProcessTypes: []string{"web"}, Command: sleepCommand}}
+		changedWeb.Services = []manifest.Service{{Name: serviceName}}
+
+		changedWorker := catnip(workerAppName)
+		changedWorker.Routes = worker.Routes
+		changedWorker.Processes = append(changedWorker.Processes, manifest.Process{Type: "worker", Command: sleepCommand, Instances: manifest.Instances(1), HealthCheckType: "process"})
+
+		changed = manifest.Manifest{Applications: []manifest.Application{changedWeb, changedWorker}}
+
+		Expect(cf.Cf("push", "-f", app_helpers.WriteManifest(pushed)).Wait(2 * Config.CfPushTimeoutDuration())).To(Exit(0))
+	})
+
+	AfterEach(func() {
+		for _, appName := range []string{webAppName, workerAppName} {
+			app_helpers.AppReport(appName)
+			Expect(cf.Cf("delete", appName, "-f", "-r").Wait()).To(Exit(0))
+		}
+		Expect(cf.Cf("delete-service", serviceName, "-f").Wait()).To(Exit(0))
+	})
+
+	Describe("manifest_diff", func() {
+		It("reports no changes for the manifest the apps were pushed with", func() {
+			Expect(DiffSpaceManifest(spaceGuid, pushed)).To(BeEmpty())
+		})
+
+		It("reports the operations applying a manifest would make, without making them", func() {
+			diff := DiffSpaceManifest(spaceGuid, changed)
+
+			Expect(diff).To(ContainElement(SatisfyAll(
+				operation("replace", `^/applications/0/processes/0/memory$`),
+				HaveField("Was", "256M"),
+				HaveField("Value", "512M"),
+			)), "the memory of the web process is replaced")
+			Expect(diff).To(ContainElement(SatisfyAll(
+				operation("replace", `^/applications/1/processes/1/instances$`),
+				HaveField("Was", BeNumerically("==", 0)),
+				HaveField("Value", BeNumerically("==", 1)),
+			)), "the instances of the worker process are replaced")
+			Expect(diff).To(ContainElement(SatisfyAll(
+				operation("add", `^/applications/0/env/ADDED$`),
+				HaveField("Value", "added"),
+			)), "an environment variable is added")
+			Expect(diff).To(ContainElement(SatisfyAll(
+				operation("remove", `^/applications/0/env/REMOVED$`),
+				HaveField("Was", "removed"),
+			)), "an environment variable is removed")
+			Expect(diff).To(ContainElement(operation("remove", `^/applications/0/routes/1$`)), "a route is removed")
+			Expect(diff).To(ContainElement(operation("add", `^/applications/0/sidecars(/0)?$`)), "a sidecar is added")
+			Expect(diff).To(ContainElement(operation("add", `^/applications/0/services(/0)?$`)), "a service binding is added")
+
+			Expect(diff).NotTo(ContainElement(HaveField("Path", MatchRegexp(`^/applications/\d+/(env/KEPT|buildpacks|processes/0/command)`))),
+				"values that are unchanged are not reported")
+			Expect(diff).To(HaveEach(HaveField("Path", Not(ContainSubstring("/path")))), "paths of app bits are ignored")
+
+			By("leaving the apps as they are")
+			webAppGuid := app_helpers.GetAppGuid(webAppName)
+			Expect(GetAppEnvironmentVariables(webAppGuid)).To(SatisfyAll(HaveKey("REMOVED"), Not(HaveKey("ADDED"))))
+			Expect(GetAppProcess(webAppGuid, "web").MemoryInMB).To(Equal(256))
+			Expect(GetAppSidecars(webAppGuid)).To(BeEmpty())
+		})
+
+		It("reports every key of apps that do not exist yet as added", func() {
+			newAppName := random_name.CATSRandomName("APP")
+			newApp := manifest.Application{Name: newAppName, Env: map[string]string{"FOO": "bar"}, Routes: []manifest.Route{route(newAppName)}}
+
+			diff := DiffSpaceManifest(spaceGuid, manifest.Manifest{Applications: []manifest.Application{changed.Applications[0], newApp}})
+
+			Expect(diff).To(ContainElement(operation("add", `^/applications/1/env(/FOO)?$`)))
+			Expect(diff).To(ContainElement(operation("add", `^/applications/1/routes(/0)?$`)))
+			Expect(diff).To(ContainElement(operation("remove", `^/applications/0/env/REMOVED$`)), "apps that exist are still diffed")
+			Expect(cf.Cf("app", newAppName).Wait()).NotTo(Exit(0), "the diff does not create the app")
+		})
+	})
+
+	Describe("apply_manifest", func() {
+		It("converges every app in the manifest", func() {
+			webAppGuid := app_helpers.GetAppGuid(webAppName)
+			workerAppGuid := app_helpers.GetAppGuid(workerAppName)
+
+			ApplySpaceManifest(spaceGuid, changed)
+
+			By("updating the configuration of the web app")
+			Expect(GetAppEnvironmentVariables(webAppGuid)).To(SatisfyAll(HaveKeyWithValue("KEPT", "kept"), HaveKeyWithValue("ADDED", "added")))
+			Expect(GetAppProcess(webAppGuid, "web").MemoryInMB).To(Equal(512))
+			Expect(GetAppSidecars(webAppGuid)).To(ConsistOf(SatisfyAll(
+				HaveField("Name", "sleeper"),
+				HaveField("Command", sleepCommand),
+				HaveField("ProcessTypes", ConsistOf("web")),
+			)))
+			Expect(GetBoundServiceInstanceGuids(webAppGuid)).To(ConsistOf(GetGuidByName("service_instances", serviceName)))
+			Expect(GetAppRouteUrls(webAppGuid)).To(ContainElement(route(webAppName).Route))
+
+			By("scaling the worker app")
+			Expect(GetAppProcess(workerAppGuid, "worker").Instances).To(Equal(1))
+
+			By("running the new configuration once the apps are restarted")
+			for _, appName := range []string{webAppName, workerAppName} {
+				Expect(cf.Cf("restart", appName).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
+			}
+			Eventually(func() int {
+				return GetRunningInstancesStats(GetAppProcess(workerAppGuid, "worker").GUID)
+			}, Config.CfPushTimeoutDuration()).Should(Equal(1))
+			Eventually(func() string {
+				return helpers.CurlAppRoot(Config, webAppName)
+			}, Config.DefaultTimeoutDuration()).Should(ContainSubstring("Catnip?"))
+			Expect(GetProcessSidecars(GetAppProcess(webAppGuid, "web").GUID)).To(HaveLen(1))
+		})
+
+		It("reports no changes for a manifest once it is applied, apart from what applying keeps", func() {
+			ApplySpaceManifest(spaceGuid, changed)
+
+			diff := DiffSpaceManifest(spaceGuid, changed)
+			Expect(diff).To(ConsistOf(
+				operation("remove", `^/applications/0/env/REMOVED$`),
+				operation("remove", `^/applications/0/routes/1$`),
+			), "applying a manifest adds and replaces values, but does not remove them")
+		})
+	})
+})
@@ -8,8 +8,8 @@ import (
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/logs"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/manifest"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
-	"github.com/cloudfoundry/cf-acceptance-tests/helpers/skip_messages"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gbytes"
@@ -21,9 +21,6 @@ var _ = WindowsDescribe("Readiness Healthcheck", func() {
 	var readinessHealthCheckTimeout = "25s" // 20s route emitter sync loop + 2s hc interval + bonus
 
 	BeforeEach(func() {
-		if !Config.GetReadinessHealthChecksEnabled() {
-			Skip(skip_messages.SkipReadinessHealthChecksMessage)
-		}
 		appName = random_name.CATSRandomName("APP")
 	})
 
@@ -37,7 +34,20 @@ var _ = WindowsDescribe("Readiness Healthcheck", func() {
 			By("pushing the app")
 			Expect(cf.Cf("push",
 				appName,
-				"-f", assets.NewAssets().Nora+"/../readiness_manifest.yml",
+				"-f", app_helpers.WriteManifest(manifest.Manifest{Applications: []manifest.Application{{
+					Name: appName,
+					Processes: []manifest.Process{{
+						Type:                             "web",
+						Instances:                        manifest.Instances(1),
+						Memory:                           "1024M",
+						DiskQuota:                        "1024M",
+						LogRateLimitPerSecond:            "16K",
+						HealthCheckType:                  "port",
+						ReadinessHealthCheckType:         "http",
+						ReadinessHealthCheckHTTPEndpoint: "/ready",
+						ReadinessHealthCheckInterval:     1,
+					}},
+				}}}),
 				"-p", assets.NewAssets().Nora,
 				"-s", Config.GetWindowsStack(),
 			).Wait(Config.CfPushTimeoutDuration())).To(Exit(0))
@@ -72,7 +82,7 @@ var _ = WindowsDescribe("Readiness Healthcheck", func() {
 			By("verifying that the app hasn't restarted")
 			Consistently(cf.Cf("events", appName)).ShouldNot(Say("audit.app.process.crash"))
 
-			if Config.GetIncludeSsh() {
+			if Config.GetIncludeSsh() && !Config.UsesClientCredentials() {
 				By("re-enabling the app's readiness endpoint")
 				Expect(cf.Cf("ssh", appName, "-c", "curl localhost:8080/ready/true").Wait(Config.DefaultTimeoutDuration())).To(Exit(0))
 
@@ -86,4 +96,4 @@ var _ = WindowsDescribe("Readiness Healthcheck", func() {
 			}
 		})
 	})
-})
+}, RequiresReadinessHealthChecks)
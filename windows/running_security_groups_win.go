@@ -195,11 +195,7 @@ var _ = WindowsDescribe("WINDOWS: App Instance Networking", func() {
 			}).Should(ContainSubstring("Unable to connect to the remote server"))
 		})
 
-		It("allows traffic to the public internet by default", func() {
-			if !Config.GetIncludeInternetDependent() {
-				Skip("skipping internet dependent test as 'include_internet_dependent' is not set")
-			}
-
+		It("allows traffic to the public internet by default", RequiresInternetDependent, func() {
 			By("Asserting default running security group configuration from a running container to an external destination")
 			noraCurlResponse := testAppConnectivity(clientAppName, "www.google.com", 80)
 
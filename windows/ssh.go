@@ -4,9 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"os"
 	"os/exec"
-	"path/filepath"
 	"strings"
 
 	"golang.org/x/crypto/ssh"
@@ -15,8 +13,8 @@ import (
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/app_helpers"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/assets"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/logs"
+	"github.com/cloudfoundry/cf-acceptance-tests/helpers/manifest"
 	"github.com/cloudfoundry/cf-acceptance-tests/helpers/random_name"
-	"github.com/cloudfoundry/cf-acceptance-tests/helpers/skip_messages"
 	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
 	"github.com/cloudfoundry/cf-test-helpers/v2/helpers"
 	. "github.com/onsi/ginkgo/v2"
@@ -29,10 +27,6 @@ var _ = WindowsDescribe("SSH", func() {
 	var appName string
 
 	BeforeEach(func() {
-		if !Config.GetIncludeSsh() {
-			Skip(skip_messages.SkipSSHMessage)
-		}
-
 		appName = random_name.CATSRandomName("APP")
 
 		Expect(cf.Cf("push",
@@ -236,7 +230,7 @@ var _ = WindowsDescribe("SSH", func() {
 		})
 	})
 
-})
+}, RequiresSsh)
 
 func sshAccessCode() string {
 	getCode := cf.Cf("ssh-code")
@@ -264,20 +258,10 @@ func sshProxyAddress() string {
 }
 
 func createManifestSshDisabled(appName string) string {
-	tmpdir, err := os.MkdirTemp(os.TempDir(), appName)
-	Expect(err).ToNot(HaveOccurred())
-
-	manifestFile := filepath.Join(tmpdir, "manifest.yml")
-	manifestContent := fmt.Sprintf(`---
-applications:
-- name: %s
-  features:
-    ssh: false
-`, appName)
-	err = os.WriteFile(manifestFile, []byte(manifestContent), 0644)
-	Expect(err).ToNot(HaveOccurred())
-
-	return manifestFile
+	return app_helpers.WriteManifest(manifest.Manifest{Applications: []manifest.Application{{
+		Name:     appName,
+		Features: map[string]bool{"ssh": false},
+	}}})
 }
 
 func expectSshCmdToFail(appName string) {
@@ -10,7 +10,6 @@ import (
 	. "github.com/onsi/gomega/gbytes"
 	. "github.com/onsi/gomega/gexec"
 
-	"github.com/cloudfoundry/cf-acceptance-tests/helpers/skip_messages"
 	"github.com/cloudfoundry/cf-test-helpers/v2/cf"
 	"github.com/cloudfoundry/cf-test-helpers/v2/helpers"
 )
@@ -19,10 +18,6 @@ var _ = WindowsDescribe("Task Lifecycle", func() {
 	var appName string
 
 	BeforeEach(func() {
-		if !Config.GetUseWindowsTestTask() {
-			Skip(skip_messages.SkipWindowsTasksMessage)
-		}
-
 		appName = random_name.CATSRandomName("APP")
 
 		Expect(cf.Cf("push",
@@ -52,4 +47,4 @@ var _ = WindowsDescribe("Task Lifecycle", func() {
 			return taskSession
 		}).Should(Say("SUCCEEDED"))
 	})
-})
+}, RequiresWindowsTestTask)